@@ -0,0 +1,84 @@
+package main
+
+// This is the IntTree from examples/binaryTree trimmed down for TinyGo on
+// microcontrollers. It doesn't use fmt, sort or interfaces, which pull in a
+// lot of code and would not fit in 32 KB of flash. Output goes through the
+// println builtin, which TinyGo sends to the serial port.
+
+type Tree struct {
+	left, right *Tree
+	val         int
+}
+
+func (t *Tree) Insert(val int) *Tree {
+	if t == nil {
+		return &Tree{val: val}
+	}
+	if val < t.val {
+		t.left = t.left.Insert(val)
+	} else if val > t.val {
+		t.right = t.right.Insert(val)
+	}
+	return t
+}
+
+func (t *Tree) Contains(val int) bool {
+	for t != nil {
+		switch {
+		case val < t.val:
+			t = t.left
+		case val > t.val:
+			t = t.right
+		default:
+			return true
+		}
+	}
+	return false
+}
+
+// InOrder calls visit with each value in ascending order.
+func (t *Tree) InOrder(visit func(int)) {
+	if t == nil {
+		return
+	}
+	t.left.InOrder(visit)
+	visit(t.val)
+	t.right.InOrder(visit)
+}
+
+// insertionSort sorts vals in place. It stands in for sort.Ints, and is
+// plenty fast for the handful of values that fit in a microcontroller's RAM.
+func insertionSort(vals []int) {
+	for i := 1; i < len(vals); i++ {
+		v := vals[i]
+		j := i - 1
+		for ; j >= 0 && vals[j] > v; j-- {
+			vals[j+1] = vals[j]
+		}
+		vals[j+1] = v
+	}
+}
+
+// Balanced builds a tree of minimum height from vals, which it sorts in
+// place.
+func Balanced(vals []int) *Tree {
+	insertionSort(vals)
+	var build func(lo, hi int) *Tree
+	build = func(lo, hi int) *Tree {
+		if lo >= hi {
+			return nil
+		}
+		mid := (lo + hi) / 2
+		return &Tree{val: vals[mid], left: build(lo, mid), right: build(mid+1, hi)}
+	}
+	return build(0, len(vals))
+}
+
+func demo() {
+	t := Balanced([]int{7, 3, 9, 1, 5, 8, 10})
+	t.Insert(4)
+	t.InOrder(func(v int) {
+		println(v)
+	})
+	println(t.Contains(4), t.Contains(6))
+}
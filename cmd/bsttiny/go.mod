@@ -0,0 +1,3 @@
+module bsttiny
+
+go 1.21.3
@@ -0,0 +1,16 @@
+//go:build tinygo
+
+package main
+
+import "time"
+
+// Build and check the size with:
+//
+//	tinygo build -target=cortex-m0 -size=short -o bsttiny.elf .
+func main() {
+	demo()
+	// A program on a microcontroller has nothing to return to
+	for {
+		time.Sleep(time.Hour)
+	}
+}
@@ -0,0 +1,9 @@
+//go:build !tinygo
+
+package main
+
+// Built with the standard toolchain this runs the same demo once, which
+// makes it easy to check the tree on a desktop.
+func main() {
+	demo()
+}
@@ -0,0 +1,3 @@
+module buildmatrix
+
+go 1.21.3
@@ -0,0 +1,140 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"io/fs"
+	"log"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/tabwriter"
+)
+
+const defaultPlatforms = "linux/amd64,linux/arm64,darwin/amd64,windows/amd64"
+
+// platform is a GOOS/GOARCH pair to build for.
+type platform struct {
+	OS   string
+	Arch string
+}
+
+func (p platform) String() string {
+	return p.OS + "/" + p.Arch
+}
+
+func parsePlatforms(s string) ([]platform, error) {
+	var out []platform
+	for _, field := range strings.Split(s, ",") {
+		goos, goarch, ok := strings.Cut(strings.TrimSpace(field), "/")
+		if !ok || goos == "" || goarch == "" {
+			return nil, fmt.Errorf("invalid platform %q, want GOOS/GOARCH", field)
+		}
+		out = append(out, platform{OS: goos, Arch: goarch})
+	}
+	return out, nil
+}
+
+// modules returns the path, relative to root, of every module under root.
+func modules(root string) ([]string, error) {
+	var out []string
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() && d.Name() == ".git" {
+			return filepath.SkipDir
+		}
+		if d.IsDir() || d.Name() != "go.mod" {
+			return nil
+		}
+		rel, err := filepath.Rel(root, filepath.Dir(path))
+		if err != nil {
+			return err
+		}
+		out = append(out, rel)
+		return nil
+	})
+	sort.Strings(out)
+	return out, err
+}
+
+// build compiles every package in dir for p. Binaries go to a temporary
+// directory so the build doesn't leave files in the repo.
+func build(dir string, p platform) error {
+	out, err := os.MkdirTemp("", "buildmatrix")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(out)
+	var stderr bytes.Buffer
+	cmd := exec.Command("go", "build", "-o", out+string(filepath.Separator), "./...")
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), "GOOS="+p.OS, "GOARCH="+p.Arch, "CGO_ENABLED=0")
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("%w: %s", err, bytes.TrimSpace(stderr.Bytes()))
+	}
+	return nil
+}
+
+func main() {
+	root := flag.String("root", ".", "directory to search for modules")
+	platformList := flag.String("platforms", defaultPlatforms, "comma separated GOOS/GOARCH pairs to build for")
+	flag.Parse()
+
+	platforms, err := parsePlatforms(*platformList)
+	if err != nil {
+		log.Fatal(err)
+	}
+	mods, err := modules(*root)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// errs holds the build errors for each platform, keyed by module
+	errs := make([]map[string]error, len(platforms))
+	for i, p := range platforms {
+		errs[i] = map[string]error{}
+		for _, m := range mods {
+			if err := build(filepath.Join(*root, m), p); err != nil {
+				errs[i][m] = err
+			}
+		}
+	}
+
+	tw := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', 0)
+	fmt.Fprint(tw, "module")
+	for _, p := range platforms {
+		fmt.Fprintf(tw, "\t%s", p)
+	}
+	fmt.Fprintln(tw)
+	failed := false
+	for _, m := range mods {
+		fmt.Fprint(tw, m)
+		for i := range platforms {
+			status := "pass"
+			if errs[i][m] != nil {
+				status = "FAIL"
+				failed = true
+			}
+			fmt.Fprintf(tw, "\t%s", status)
+		}
+		fmt.Fprintln(tw)
+	}
+	tw.Flush()
+
+	for i, p := range platforms {
+		for _, m := range mods {
+			if err := errs[i][m]; err != nil {
+				fmt.Printf("\n%s on %s:\n%v\n", m, p, err)
+			}
+		}
+	}
+	if failed {
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,3 @@
+module changelog
+
+go 1.21.3
@@ -0,0 +1,103 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"os/exec"
+	"regexp"
+	"strings"
+)
+
+// gitRunner runs git with the given arguments and returns its stdout. It's a
+// variable so the git history can be replaced with canned output.
+var gitRunner = func(args ...string) (string, error) {
+	out, err := exec.Command("git", args...).Output()
+	return string(out), err
+}
+
+// Each line of the log is the subject and the ref names pointing at the
+// commit, separated by a tab.
+const logFormat = "--format=%s%x09%D"
+
+var commitPattern = regexp.MustCompile(`^(feat|fix|docs)(?:\(([^)]+)\))?!?: (.+)$`)
+
+var tagPattern = regexp.MustCompile(`tag: (v[^,]+)`)
+
+var sectionTitles = map[string]string{
+	"feat": "Features",
+	"fix":  "Bug Fixes",
+	"docs": "Documentation",
+}
+
+var sectionOrder = []string{"feat", "fix", "docs"}
+
+type release struct {
+	version string
+	entries map[string][]string
+}
+
+// parseLog groups conventional commits by the version tag they were released
+// under. git log lists the newest commit first, so every commit belongs to
+// the closest tag above it, commits newer than every tag are unreleased.
+func parseLog(out string) []release {
+	current := release{version: "Unreleased", entries: map[string][]string{}}
+	var releases []release
+	for _, line := range strings.Split(out, "\n") {
+		if line == "" {
+			continue
+		}
+		subject, refs, _ := strings.Cut(line, "\t")
+		if m := tagPattern.FindStringSubmatch(refs); m != nil {
+			releases = append(releases, current)
+			current = release{version: m[1], entries: map[string][]string{}}
+		}
+		m := commitPattern.FindStringSubmatch(subject)
+		if m == nil {
+			continue
+		}
+		entry := m[3]
+		if m[2] != "" {
+			entry = "**" + m[2] + ":** " + entry
+		}
+		current.entries[m[1]] = append(current.entries[m[1]], entry)
+	}
+	return append(releases, current)
+}
+
+func writeChangelog(w io.Writer, releases []release) {
+	fmt.Fprintln(w, "# Changelog")
+	for _, r := range releases {
+		if len(r.entries) == 0 {
+			continue
+		}
+		fmt.Fprintf(w, "\n## %s\n", r.version)
+		for _, kind := range sectionOrder {
+			entries := r.entries[kind]
+			if len(entries) == 0 {
+				continue
+			}
+			fmt.Fprintf(w, "\n### %s\n\n", sectionTitles[kind])
+			for _, e := range entries {
+				fmt.Fprintf(w, "- %s\n", e)
+			}
+		}
+	}
+}
+
+// changelog writes the changelog for the git history gitRunner returns.
+func changelog(w io.Writer) error {
+	out, err := gitRunner("log", logFormat)
+	if err != nil {
+		return fmt.Errorf("git log: %w", err)
+	}
+	writeChangelog(w, parseLog(out))
+	return nil
+}
+
+func main() {
+	if err := changelog(os.Stdout); err != nil {
+		log.Fatal(err)
+	}
+}
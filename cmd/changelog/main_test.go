@@ -0,0 +1,107 @@
+package main
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// fakeGit replaces gitRunner with one that returns out and records the
+// arguments it was called with, until the test ends.
+func fakeGit(t *testing.T, out string, err error) *[]string {
+	t.Helper()
+	var args []string
+	orig := gitRunner
+	gitRunner = func(a ...string) (string, error) {
+		args = a
+		return out, err
+	}
+	t.Cleanup(func() { gitRunner = orig })
+	return &args
+}
+
+func TestChangelog(t *testing.T) {
+	log := strings.Join([]string{
+		"feat: add cursor pagination\tHEAD -> main, origin/main",
+		"chore: bump deps\t",
+		"fix(parser): handle unary minus\t",
+		"feat(api)!: drop the v1 endpoints\ttag: v2.0.0",
+		"docs: explain the exit codes\t",
+		"Merge branch 'topic'\t",
+		"fix: off by one in FileTail\ttag: v1.1.0, origin/release",
+		"feat: first release\ttag: v1.0.0",
+		"",
+	}, "\n")
+	args := fakeGit(t, log, nil)
+
+	var b strings.Builder
+	if err := changelog(&b); err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"log", logFormat}; !slices.Equal(*args, want) {
+		t.Errorf("git was run with %q, want %q", *args, want)
+	}
+	want := `# Changelog
+
+## Unreleased
+
+### Features
+
+- add cursor pagination
+
+### Bug Fixes
+
+- **parser:** handle unary minus
+
+## v2.0.0
+
+### Features
+
+- **api:** drop the v1 endpoints
+
+### Documentation
+
+- explain the exit codes
+
+## v1.1.0
+
+### Bug Fixes
+
+- off by one in FileTail
+
+## v1.0.0
+
+### Features
+
+- first release
+`
+	if got := b.String(); got != want {
+		t.Errorf("changelog =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestChangelogEmptyRelease(t *testing.T) {
+	// A tag with no conventional commits under it, and nothing unreleased
+	fakeGit(t, "chore: tidy\ttag: v1.1.0\nfeat: start\ttag: v1.0.0\n", nil)
+	var b strings.Builder
+	if err := changelog(&b); err != nil {
+		t.Fatal(err)
+	}
+	want := "# Changelog\n\n## v1.0.0\n\n### Features\n\n- start\n"
+	if got := b.String(); got != want {
+		t.Errorf("changelog = %q, want %q", got, want)
+	}
+}
+
+func TestChangelogGitError(t *testing.T) {
+	errGit := errors.New("not a git repository")
+	fakeGit(t, "", errGit)
+	var b strings.Builder
+	if err := changelog(&b); !errors.Is(err, errGit) {
+		t.Errorf("changelog error = %v, want %v", err, errGit)
+	}
+	if b.Len() != 0 {
+		t.Errorf("changelog wrote %q after git failed", b.String())
+	}
+}
@@ -0,0 +1,3 @@
+module complexity
+
+go 1.21.3
@@ -0,0 +1,223 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// graph maps each package path to the packages it imports.
+type graph map[string][]string
+
+// moduleDirs returns every directory under root that contains a go.mod.
+func moduleDirs(root string) ([]string, error) {
+	var dirs []string
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() && d.Name() == ".git" {
+			return filepath.SkipDir
+		}
+		if !d.IsDir() && d.Name() == "go.mod" {
+			dirs = append(dirs, filepath.Dir(path))
+		}
+		return nil
+	})
+	return dirs, err
+}
+
+// loadGraph records the imports between the packages of every module under
+// root. Only imports within the same module are kept, a cycle can't cross
+// module boundaries. go/packages drops the import that closes a cycle, so the
+// import stack in the cycle error is added back to the graph.
+func loadGraph(root string) (graph, error) {
+	dirs, err := moduleDirs(root)
+	if err != nil {
+		return nil, err
+	}
+	g := graph{}
+	for _, dir := range dirs {
+		cfg := &packages.Config{Mode: packages.NeedName | packages.NeedImports | packages.NeedModule, Dir: dir}
+		pkgs, err := packages.Load(cfg, "./...")
+		if err != nil {
+			return nil, fmt.Errorf("loading %s: %w", dir, err)
+		}
+		for _, p := range pkgs {
+			if p.Module == nil {
+				continue
+			}
+			g[p.PkgPath] = g[p.PkgPath]
+			for path, imp := range p.Imports {
+				if imp.Module != nil && imp.Module.Path == p.Module.Path {
+					g[p.PkgPath] = append(g[p.PkgPath], path)
+				}
+			}
+			for _, e := range p.Errors {
+				stack := importStack(e.Msg)
+				for i := 0; i+1 < len(stack); i++ {
+					g[stack[i]] = append(g[stack[i]], stack[i+1])
+				}
+			}
+		}
+	}
+	for from, imports := range g {
+		sort.Strings(imports)
+		g[from] = slices.Compact(imports)
+	}
+	return g, nil
+}
+
+// importStack returns the packages listed in an import cycle error, such as
+// "import cycle not allowed: import stack: [a b a]".
+func importStack(msg string) []string {
+	if !strings.Contains(msg, "import cycle not allowed") {
+		return nil
+	}
+	_, list, ok := strings.Cut(msg, "import stack: [")
+	if !ok {
+		return nil
+	}
+	list, _, _ = strings.Cut(list, "]")
+	return strings.Fields(list)
+}
+
+// components returns the strongly connected components of g with more than
+// one package, found with Tarjan's algorithm. A package that imports itself
+// is also returned on its own.
+func components(g graph) [][]string {
+	index := map[string]int{}
+	low := map[string]int{}
+	onStack := map[string]bool{}
+	var stack []string
+	var out [][]string
+	next := 0
+
+	var visit func(v string)
+	visit = func(v string) {
+		index[v], low[v] = next, next
+		next++
+		stack = append(stack, v)
+		onStack[v] = true
+		for _, w := range g[v] {
+			if _, seen := index[w]; !seen {
+				visit(w)
+				low[v] = min(low[v], low[w])
+			} else if onStack[w] {
+				low[v] = min(low[v], index[w])
+			}
+		}
+		if low[v] == index[v] {
+			var comp []string
+			for {
+				w := stack[len(stack)-1]
+				stack = stack[:len(stack)-1]
+				onStack[w] = false
+				comp = append(comp, w)
+				if w == v {
+					break
+				}
+			}
+			if len(comp) > 1 || importsItself(g, v) {
+				sort.Strings(comp)
+				out = append(out, comp)
+			}
+		}
+	}
+	for _, v := range sortedKeys(g) {
+		if _, seen := index[v]; !seen {
+			visit(v)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i][0] < out[j][0] })
+	return out
+}
+
+func importsItself(g graph, v string) bool {
+	for _, w := range g[v] {
+		if w == v {
+			return true
+		}
+	}
+	return false
+}
+
+// cyclePath returns the shortest path that starts and ends at the first
+// package of comp without leaving the component.
+func cyclePath(g graph, comp []string) []string {
+	start := comp[0]
+	inComp := map[string]bool{}
+	for _, v := range comp {
+		inComp[v] = true
+	}
+	prev := map[string]string{}
+	queue := []string{start}
+	for len(queue) > 0 {
+		v := queue[0]
+		queue = queue[1:]
+		for _, w := range g[v] {
+			if !inComp[w] {
+				continue
+			}
+			if w == start {
+				path := []string{start}
+				for u := v; u != start; u = prev[u] {
+					path = append(path, u)
+				}
+				// The path was built backwards from the end
+				for i, j := 1, len(path)-1; i < j; i, j = i+1, j-1 {
+					path[i], path[j] = path[j], path[i]
+				}
+				return append(path, start)
+			}
+			if _, seen := prev[w]; !seen {
+				prev[w] = v
+				queue = append(queue, w)
+			}
+		}
+	}
+	return nil
+}
+
+func sortedKeys(g graph) []string {
+	keys := make([]string, 0, len(g))
+	for k := range g {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func main() {
+	root := flag.String("root", ".", "directory to search for modules")
+	flag.Parse()
+
+	g, err := loadGraph(*root)
+	if err != nil {
+		log.Fatal(err)
+	}
+	comps := components(g)
+	if len(comps) == 0 {
+		fmt.Println("no import cycles")
+		return
+	}
+	for i, comp := range comps {
+		fmt.Printf("cycle %d:\n", i+1)
+		for j, pkg := range cyclePath(g, comp) {
+			if j == 0 {
+				fmt.Printf("\t%s\n", pkg)
+			} else {
+				fmt.Printf("\t-> %s\n", pkg)
+			}
+		}
+	}
+	os.Exit(1)
+}
@@ -0,0 +1,3 @@
+module deadcode
+
+go 1.21.3
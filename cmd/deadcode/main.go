@@ -0,0 +1,146 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// finding is a function or variable that's declared but never used.
+type finding struct {
+	Pos  token.Position
+	Kind string
+	Name string
+}
+
+// findDead parses the package in dir and reports package level functions and
+// variables that are never referenced. Uses are found by name, which is good
+// enough for small single package programs but doesn't understand scopes, so
+// a local variable that shadows a dead function hides it. Methods are skipped
+// since they're often only called through an interface.
+func findDead(fset *token.FileSet, dir string, allow *regexp.Regexp) ([]finding, error) {
+	pkgs, err := parser.ParseDir(fset, dir, func(fi fs.FileInfo) bool {
+		return !strings.HasSuffix(fi.Name(), "_test.go")
+	}, 0)
+	if err != nil {
+		return nil, err
+	}
+	var out []finding
+	for _, pkg := range pkgs {
+		decls := map[*ast.Ident]string{}
+		for _, f := range pkg.Files {
+			for _, d := range f.Decls {
+				switch d := d.(type) {
+				case *ast.FuncDecl:
+					if d.Recv == nil {
+						decls[d.Name] = "func"
+					}
+				case *ast.GenDecl:
+					if d.Tok != token.VAR {
+						continue
+					}
+					for _, spec := range d.Specs {
+						for _, name := range spec.(*ast.ValueSpec).Names {
+							decls[name] = "var"
+						}
+					}
+				}
+			}
+		}
+
+		used := map[string]bool{}
+		for _, f := range pkg.Files {
+			ast.Inspect(f, func(n ast.Node) bool {
+				if id, ok := n.(*ast.Ident); ok {
+					if _, isDecl := decls[id]; !isDecl {
+						used[id.Name] = true
+					}
+				}
+				return true
+			})
+		}
+
+		for id, kind := range decls {
+			name := id.Name
+			if used[name] || name == "_" || name == "main" || name == "init" || allow.MatchString(name) {
+				continue
+			}
+			out = append(out, finding{Pos: fset.Position(id.Pos()), Kind: kind, Name: name})
+		}
+	}
+	return out, nil
+}
+
+// goDirs returns every directory under root that contains Go files.
+func goDirs(root string) ([]string, error) {
+	seen := map[string]bool{}
+	var dirs []string
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() && (d.Name() == ".git" || d.Name() == "testdata") {
+			return filepath.SkipDir
+		}
+		dir := filepath.Dir(path)
+		if !d.IsDir() && strings.HasSuffix(path, ".go") && !seen[dir] {
+			seen[dir] = true
+			dirs = append(dirs, dir)
+		}
+		return nil
+	})
+	return dirs, err
+}
+
+func report(w io.Writer, findings []finding) {
+	sort.Slice(findings, func(i, j int) bool {
+		if findings[i].Pos.Filename != findings[j].Pos.Filename {
+			return findings[i].Pos.Filename < findings[j].Pos.Filename
+		}
+		return findings[i].Pos.Line < findings[j].Pos.Line
+	})
+	for _, f := range findings {
+		fmt.Fprintf(w, "%s:%d: %s %s is never used\n", f.Pos.Filename, f.Pos.Line, f.Kind, f.Name)
+	}
+}
+
+func main() {
+	allowFlag := flag.String("allow", "^$", "regexp of names that are allowed to be unused")
+	flag.Parse()
+	allow, err := regexp.Compile(*allowFlag)
+	if err != nil {
+		log.Fatal(err)
+	}
+	root := "."
+	if flag.NArg() > 0 {
+		root = flag.Arg(0)
+	}
+
+	dirs, err := goDirs(root)
+	if err != nil {
+		log.Fatal(err)
+	}
+	fset := token.NewFileSet()
+	var all []finding
+	for _, dir := range dirs {
+		found, err := findDead(fset, dir, allow)
+		if err != nil {
+			log.Fatal(err)
+		}
+		all = append(all, found...)
+	}
+	report(os.Stdout, all)
+	if len(all) > 0 {
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,185 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"log"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// graph maps each package path to the packages it imports.
+type graph map[string][]string
+
+// moduleDirs returns every directory under root that contains a go.mod. Each
+// example and exercise in the repo is its own module, so they have to be
+// loaded one at a time.
+func moduleDirs(root string) ([]string, error) {
+	var dirs []string
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() && d.Name() == ".git" {
+			return filepath.SkipDir
+		}
+		if !d.IsDir() && d.Name() == "go.mod" {
+			dirs = append(dirs, filepath.Dir(path))
+		}
+		return nil
+	})
+	return dirs, err
+}
+
+// loadGraph loads the packages in every module under root and records the
+// imports between them. Standard library and third party packages are left
+// out to keep the graph readable.
+func loadGraph(root string) (graph, error) {
+	dirs, err := moduleDirs(root)
+	if err != nil {
+		return nil, err
+	}
+	g := graph{}
+	for _, dir := range dirs {
+		cfg := &packages.Config{Mode: packages.NeedName | packages.NeedImports | packages.NeedModule, Dir: dir}
+		pkgs, err := packages.Load(cfg, "./...")
+		if err != nil {
+			return nil, fmt.Errorf("loading %s: %w", dir, err)
+		}
+		for _, p := range pkgs {
+			if p.Module == nil {
+				continue
+			}
+			g[p.PkgPath] = g[p.PkgPath]
+			for path, imp := range p.Imports {
+				if imp.Module != nil && imp.Module.Path == p.Module.Path {
+					g[p.PkgPath] = append(g[p.PkgPath], path)
+				}
+			}
+		}
+	}
+	for _, imports := range g {
+		sort.Strings(imports)
+	}
+	return g, nil
+}
+
+// cycleEdges returns the edges that are part of an import cycle. An edge is in
+// a cycle when both of its ends are in the same strongly connected component,
+// which is found with Tarjan's algorithm.
+func cycleEdges(g graph) map[[2]string]bool {
+	index := map[string]int{}
+	low := map[string]int{}
+	onStack := map[string]bool{}
+	component := map[string]int{}
+	var stack []string
+	next, components := 0, 0
+
+	var visit func(v string)
+	visit = func(v string) {
+		index[v], low[v] = next, next
+		next++
+		stack = append(stack, v)
+		onStack[v] = true
+		for _, w := range g[v] {
+			if _, seen := index[w]; !seen {
+				visit(w)
+				low[v] = min(low[v], low[w])
+			} else if onStack[w] {
+				low[v] = min(low[v], index[w])
+			}
+		}
+		if low[v] == index[v] {
+			for {
+				w := stack[len(stack)-1]
+				stack = stack[:len(stack)-1]
+				onStack[w] = false
+				component[w] = components
+				if w == v {
+					break
+				}
+			}
+			components++
+		}
+	}
+	for _, v := range sortedKeys(g) {
+		if _, seen := index[v]; !seen {
+			visit(v)
+		}
+	}
+
+	edges := map[[2]string]bool{}
+	for from, imports := range g {
+		for _, to := range imports {
+			if component[from] == component[to] {
+				edges[[2]string{from, to}] = true
+			}
+		}
+	}
+	return edges
+}
+
+func sortedKeys(g graph) []string {
+	keys := make([]string, 0, len(g))
+	for k := range g {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func writeDOT(w io.Writer, g graph) {
+	cycles := cycleEdges(g)
+	fmt.Fprintln(w, "digraph deps {")
+	fmt.Fprintln(w, "\trankdir=LR;")
+	for _, from := range sortedKeys(g) {
+		fmt.Fprintf(w, "\t%q;\n", from)
+		for _, to := range g[from] {
+			if cycles[[2]string{from, to}] {
+				fmt.Fprintf(w, "\t%q -> %q [color=red];\n", from, to)
+			} else {
+				fmt.Fprintf(w, "\t%q -> %q;\n", from, to)
+			}
+		}
+	}
+	fmt.Fprintln(w, "}")
+}
+
+func main() {
+	root := flag.String("root", ".", "directory to search for modules")
+	out := flag.String("o", "deps.dot", "file to write the graph to")
+	format := flag.String("format", "dot", "output format, dot or svg")
+	flag.Parse()
+
+	g, err := loadGraph(*root)
+	if err != nil {
+		log.Fatal(err)
+	}
+	var buf bytes.Buffer
+	writeDOT(&buf, g)
+
+	data := buf.Bytes()
+	switch *format {
+	case "dot":
+	case "svg":
+		cmd := exec.Command("dot", "-Tsvg")
+		cmd.Stdin = &buf
+		cmd.Stderr = os.Stderr
+		data, err = cmd.Output()
+		if err != nil {
+			log.Fatal(err)
+		}
+	default:
+		log.Fatalf("unknown format: %s", *format)
+	}
+	if err := os.WriteFile(*out, data, 0644); err != nil {
+		log.Fatal(err)
+	}
+}
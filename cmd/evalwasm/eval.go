@@ -0,0 +1,126 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+)
+
+// This is a cut down copy of the calculator in exercises/05/ex1, which is
+// its own main package and can't be imported. It handles the four basic
+// operators and parentheses with the usual precedence.
+
+type opFuncType func(int, int) (int, error)
+
+func add(i, j int) (int, error) { return i + j, nil }
+
+func sub(i, j int) (int, error) { return i - j, nil }
+
+func mul(i, j int) (int, error) { return i * j, nil }
+
+func div(i, j int) (int, error) {
+	if j == 0 {
+		return 0, errors.New("division by zero")
+	}
+	return i / j, nil
+}
+
+var opMap = map[byte]opFuncType{
+	'+': add,
+	'-': sub,
+	'*': mul,
+	'/': div,
+}
+
+var precedence = map[byte]int{'+': 1, '-': 1, '*': 2, '/': 2}
+
+// evaluator parses and evaluates an expression in a single pass.
+type evaluator struct {
+	s   string
+	pos int
+}
+
+func (e *evaluator) skipSpace() {
+	for e.pos < len(e.s) && (e.s[e.pos] == ' ' || e.s[e.pos] == '\t') {
+		e.pos++
+	}
+}
+
+func (e *evaluator) expr(minPrec int) (int, error) {
+	left, err := e.operand()
+	if err != nil {
+		return 0, err
+	}
+	for {
+		e.skipSpace()
+		if e.pos >= len(e.s) {
+			return left, nil
+		}
+		op := e.s[e.pos]
+		prec, ok := precedence[op]
+		if !ok || prec < minPrec {
+			return left, nil
+		}
+		e.pos++
+		right, err := e.expr(prec + 1)
+		if err != nil {
+			return 0, err
+		}
+		if left, err = opMap[op](left, right); err != nil {
+			return 0, err
+		}
+	}
+}
+
+func (e *evaluator) operand() (int, error) {
+	e.skipSpace()
+	if e.pos >= len(e.s) {
+		return 0, errors.New("expected number or '(' at end of input")
+	}
+	if e.s[e.pos] == '(' {
+		open := e.pos
+		e.pos++
+		v, err := e.expr(1)
+		if err != nil {
+			return 0, err
+		}
+		e.skipSpace()
+		if e.pos >= len(e.s) || e.s[e.pos] != ')' {
+			return 0, fmt.Errorf("expected ')' at offset %d to close '(' at offset %d", e.pos, open)
+		}
+		e.pos++
+		return v, nil
+	}
+	start := e.pos
+	for e.pos < len(e.s) && e.s[e.pos] >= '0' && e.s[e.pos] <= '9' {
+		e.pos++
+	}
+	if start == e.pos {
+		return 0, fmt.Errorf("expected number or '(' at offset %d, found %q", start, e.s[start])
+	}
+	return strconv.Atoi(e.s[start:e.pos])
+}
+
+// Eval evaluates an infix expression such as "2 + 3 * (4 - 1)".
+func Eval(expr string) (int, error) {
+	e := &evaluator{s: expr}
+	v, err := e.expr(1)
+	if err != nil {
+		return 0, err
+	}
+	e.skipSpace()
+	if e.pos < len(e.s) {
+		return 0, fmt.Errorf("expected operator at offset %d, found %q", e.pos, e.s[e.pos])
+	}
+	return v, nil
+}
+
+// evalExpr is what JavaScript calls. It returns the result, or the error
+// prefixed with "error: ", as a string.
+func evalExpr(expr string) string {
+	v, err := Eval(expr)
+	if err != nil {
+		return "error: " + err.Error()
+	}
+	return strconv.Itoa(v)
+}
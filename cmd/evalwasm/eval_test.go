@@ -0,0 +1,64 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+// These run natively and as wasm, see .github/workflows/evalwasm.yml.
+
+func TestEval(t *testing.T) {
+	tests := []struct {
+		expr string
+		want int
+	}{
+		{"1 + 2", 3},
+		{"7 - 10", -3},
+		{"6 * 7", 42},
+		{"7 / 2", 3},
+		{"2 + 3 * (4 - 1)", 11},
+		{"(2 + 3) * 4", 20},
+		{"10 - 4 - 3", 3},
+		{"8 / 2 / 2", 2},
+		{"1 + 2 * 3 - 8 / 4", 5},
+		{" ( 42 ) ", 42},
+	}
+	for _, tt := range tests {
+		got, err := Eval(tt.expr)
+		if err != nil || got != tt.want {
+			t.Errorf("Eval(%q) = %d, %v, want %d", tt.expr, got, err, tt.want)
+		}
+	}
+}
+
+func TestEvalErrors(t *testing.T) {
+	tests := []struct {
+		expr, want string
+	}{
+		{"1 / 0", "division by zero"},
+		{"1 +", "at end of input"},
+		{"", "at end of input"},
+		{"(1 + 2", "expected ')'"},
+		{"1 2", "expected operator"},
+		{"1 + x", "expected number"},
+		{"99999999999999999999", "out of range"},
+	}
+	for _, tt := range tests {
+		_, err := Eval(tt.expr)
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("Eval(%q) error = %v, want one containing %q", tt.expr, err, tt.want)
+		}
+	}
+}
+
+func TestEvalExpr(t *testing.T) {
+	for expr, want := range map[string]string{
+		"12 * 12": "144",
+		"0 - 5":   "-5",
+		"1 / 0":   "error: division by zero",
+	} {
+		if got := evalExpr(expr); got != want {
+			t.Errorf("evalExpr(%q) = %q, want %q", expr, got, want)
+		}
+	}
+}
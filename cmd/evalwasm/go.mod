@@ -0,0 +1,3 @@
+module evalwasm
+
+go 1.21.3
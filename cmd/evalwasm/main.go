@@ -0,0 +1,27 @@
+//go:build js && wasm
+
+package main
+
+import "syscall/js"
+
+// Build with:
+//
+//	GOOS=js GOARCH=wasm go build -o static/evalwasm.wasm .
+//	cp "$(go env GOROOT)/lib/wasm/wasm_exec.js" static/
+//
+// and serve the static directory.
+func main() {
+	register()
+	// Keep the program running so JavaScript can keep calling evalExpr
+	select {}
+}
+
+// register sets the global evalExpr function for JavaScript to call.
+func register() {
+	js.Global().Set("evalExpr", js.FuncOf(func(this js.Value, args []js.Value) any {
+		if len(args) != 1 || args[0].Type() != js.TypeString {
+			return "error: evalExpr takes one string"
+		}
+		return evalExpr(args[0].String())
+	}))
+}
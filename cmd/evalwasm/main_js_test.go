@@ -0,0 +1,28 @@
+//go:build js && wasm
+
+package main
+
+import (
+	"syscall/js"
+	"testing"
+)
+
+func TestRegister(t *testing.T) {
+	register()
+	tests := []struct {
+		args []any
+		want string
+	}{
+		{[]any{"2 + 3 * (4 - 1)"}, "11"},
+		{[]any{"9 / 3 - 1"}, "2"},
+		{[]any{"1 / 0"}, "error: division by zero"},
+		{nil, "error: evalExpr takes one string"},
+		{[]any{42}, "error: evalExpr takes one string"},
+		{[]any{"1", "2"}, "error: evalExpr takes one string"},
+	}
+	for _, tt := range tests {
+		if got := js.Global().Call("evalExpr", tt.args...).String(); got != tt.want {
+			t.Errorf("evalExpr(%v) = %q, want %q", tt.args, got, tt.want)
+		}
+	}
+}
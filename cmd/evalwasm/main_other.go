@@ -0,0 +1,19 @@
+//go:build !(js && wasm)
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// Outside the browser the evaluator runs as a command line tool, which is
+// handy for checking it without building the wasm binary.
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprintln(os.Stderr, "usage: evalwasm expression")
+		os.Exit(2)
+	}
+	fmt.Println(evalExpr(strings.Join(os.Args[1:], " ")))
+}
@@ -0,0 +1,101 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/format"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/tools/imports"
+)
+
+// problem is a file whose formatting doesn't match gofmt or goimports.
+type problem struct {
+	Path   string
+	Reason string
+	Fixed  []byte
+}
+
+// checkFile runs the file through gofmt and then goimports, which also
+// groups and sorts the imports. It returns nil if the file is already
+// formatted.
+func checkFile(path string) (*problem, error) {
+	src, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	formatted, err := format.Source(src)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	fixed, err := imports.Process(path, formatted, &imports.Options{Comments: true, TabIndent: true, TabWidth: 8, FormatOnly: true})
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	switch {
+	case !bytes.Equal(src, formatted):
+		return &problem{Path: path, Reason: "gofmt", Fixed: fixed}, nil
+	case !bytes.Equal(formatted, fixed):
+		return &problem{Path: path, Reason: "import order", Fixed: fixed}, nil
+	}
+	return nil, nil
+}
+
+func findProblems(root string) ([]problem, error) {
+	var problems []problem
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() && (d.Name() == ".git" || d.Name() == "testdata") {
+			return filepath.SkipDir
+		}
+		if d.IsDir() || !strings.HasSuffix(path, ".go") {
+			return nil
+		}
+		p, err := checkFile(path)
+		if err != nil {
+			return err
+		}
+		if p != nil {
+			problems = append(problems, *p)
+		}
+		return nil
+	})
+	return problems, err
+}
+
+func main() {
+	check := flag.Bool("check", false, "report badly formatted files and exit non-zero without changing them")
+	flag.Parse()
+	root := "."
+	if flag.NArg() > 0 {
+		root = flag.Arg(0)
+	}
+
+	problems, err := findProblems(root)
+	if err != nil {
+		log.Fatal(err)
+	}
+	for _, p := range problems {
+		fmt.Printf("%s (%s)\n", p.Path, p.Reason)
+		if *check {
+			continue
+		}
+		info, err := os.Stat(p.Path)
+		if err != nil {
+			log.Fatal(err)
+		}
+		if err := os.WriteFile(p.Path, p.Fixed, info.Mode()); err != nil {
+			log.Fatal(err)
+		}
+	}
+	if *check && len(problems) > 0 {
+		os.Exit(1)
+	}
+}
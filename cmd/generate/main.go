@@ -0,0 +1,278 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"go/parser"
+	"go/token"
+	"io/fs"
+	"log"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// directive is a single //go:generate comment.
+type directive struct {
+	Pos     token.Position
+	Package string
+	Args    []string
+}
+
+// pkgInfo is what the driver needs to know about each directory with Go
+// files in it.
+type pkgInfo struct {
+	Dir        string
+	ImportPath string
+	Imports    []string
+	Directives []directive
+}
+
+// modulePath returns the module path declared in the go.mod in dir, or ""
+// if there isn't one.
+func modulePath(dir string) string {
+	f, err := os.Open(filepath.Join(dir, "go.mod"))
+	if err != nil {
+		return ""
+	}
+	defer f.Close()
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		if path, ok := strings.CutPrefix(strings.TrimSpace(scanner.Text()), "module "); ok {
+			return strings.Trim(strings.TrimSpace(path), `"`)
+		}
+	}
+	return ""
+}
+
+// findPackages parses every Go file under root and collects the generate
+// directives and imports of each directory. Import paths are worked out from
+// the nearest go.mod above the directory.
+func findPackages(fset *token.FileSet, root string) ([]*pkgInfo, error) {
+	pkgs := map[string]*pkgInfo{}
+	var modDir, modPath string
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if d.Name() == ".git" || d.Name() == "testdata" {
+				return filepath.SkipDir
+			}
+			if p := modulePath(path); p != "" {
+				modDir, modPath = path, p
+			}
+			return nil
+		}
+		if !strings.HasSuffix(path, ".go") {
+			return nil
+		}
+		f, err := parser.ParseFile(fset, path, nil, parser.ImportsOnly|parser.ParseComments)
+		if err != nil {
+			return err
+		}
+		dir := filepath.Dir(path)
+		pkg, ok := pkgs[dir]
+		if !ok {
+			pkg = &pkgInfo{Dir: dir, ImportPath: dir}
+			if rel, err := filepath.Rel(modDir, dir); err == nil && modPath != "" && !strings.HasPrefix(rel, "..") {
+				pkg.ImportPath = strings.TrimSuffix(modPath+"/"+filepath.ToSlash(rel), "/.")
+			}
+			pkgs[dir] = pkg
+		}
+		for _, imp := range f.Imports {
+			p, _ := strconv.Unquote(imp.Path.Value)
+			pkg.Imports = append(pkg.Imports, p)
+		}
+		for _, group := range f.Comments {
+			for _, c := range group.List {
+				text, ok := strings.CutPrefix(c.Text, "//go:generate ")
+				if !ok {
+					continue
+				}
+				pos := fset.Position(c.Pos())
+				args, err := splitArgs(text)
+				if err != nil {
+					return fmt.Errorf("%s: %w", pos, err)
+				}
+				pkg.Directives = append(pkg.Directives, directive{Pos: pos, Package: f.Name.Name, Args: args})
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	out := make([]*pkgInfo, 0, len(pkgs))
+	for _, p := range pkgs {
+		out = append(out, p)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Dir < out[j].Dir })
+	return out, nil
+}
+
+// dependencyOrder sorts the packages so that each one comes after the
+// packages it imports. Generated code in a package is often used by the
+// packages that import it, so it has to be generated first.
+func dependencyOrder(pkgs []*pkgInfo) ([]*pkgInfo, error) {
+	byPath := map[string]*pkgInfo{}
+	for _, p := range pkgs {
+		byPath[p.ImportPath] = p
+	}
+	const (
+		unvisited = iota
+		visiting
+		done
+	)
+	state := map[*pkgInfo]int{}
+	var out []*pkgInfo
+	var visit func(p *pkgInfo) error
+	visit = func(p *pkgInfo) error {
+		switch state[p] {
+		case visiting:
+			return fmt.Errorf("import cycle through %s", p.ImportPath)
+		case done:
+			return nil
+		}
+		state[p] = visiting
+		for _, imp := range p.Imports {
+			if dep, ok := byPath[imp]; ok {
+				if err := visit(dep); err != nil {
+					return err
+				}
+			}
+		}
+		state[p] = done
+		out = append(out, p)
+		return nil
+	}
+	for _, p := range pkgs {
+		if err := visit(p); err != nil {
+			return nil, err
+		}
+	}
+	return out, nil
+}
+
+// splitArgs splits a directive into words the way go generate does. Words
+// are separated by spaces, and a word in double quotes is unquoted as a Go
+// string so it can contain spaces.
+func splitArgs(line string) ([]string, error) {
+	var args []string
+	for line = strings.TrimSpace(line); line != ""; line = strings.TrimSpace(line) {
+		if line[0] != '"' {
+			word, rest, _ := strings.Cut(line, " ")
+			args = append(args, word)
+			line = rest
+			continue
+		}
+		quoted, err := strconv.QuotedPrefix(line)
+		if err != nil {
+			return nil, fmt.Errorf("bad quoted string in %q", line)
+		}
+		word, _ := strconv.Unquote(quoted)
+		args = append(args, word)
+		line = line[len(quoted):]
+	}
+	if len(args) == 0 {
+		return nil, errors.New("empty go:generate directive")
+	}
+	return args, nil
+}
+
+// env returns the variables go generate sets for a directive, which can be
+// used as $NAME in its arguments.
+func env(d directive) map[string]string {
+	return map[string]string{
+		"GOARCH":    runtime.GOARCH,
+		"GOOS":      runtime.GOOS,
+		"GOFILE":    filepath.Base(d.Pos.Filename),
+		"GOLINE":    strconv.Itoa(d.Pos.Line),
+		"GOPACKAGE": d.Package,
+		"DOLLAR":    "$",
+	}
+}
+
+// command expands the variables in the directive's arguments and returns
+// the command to run in the directive's directory.
+func command(d directive) *exec.Cmd {
+	vars := env(d)
+	lookup := func(name string) string {
+		if v, ok := vars[name]; ok {
+			return v
+		}
+		return os.Getenv(name)
+	}
+	args := make([]string, len(d.Args))
+	for i, a := range d.Args {
+		args[i] = os.Expand(a, lookup)
+	}
+	cmd := exec.Command(args[0], args[1:]...)
+	cmd.Dir = filepath.Dir(d.Pos.Filename)
+	cmd.Env = os.Environ()
+	for k, v := range vars {
+		cmd.Env = append(cmd.Env, k+"="+v)
+	}
+	return cmd
+}
+
+// quoteArgs joins args for printing, quoting any that contain spaces or
+// other special characters.
+func quoteArgs(args []string) string {
+	out := make([]string, len(args))
+	for i, a := range args {
+		if a == "" || strings.ContainsAny(a, " \t\n\"'\\") {
+			a = strconv.Quote(a)
+		}
+		out[i] = a
+	}
+	return strings.Join(out, " ")
+}
+
+func main() {
+	dryRun := flag.Bool("dry-run", false, "print the commands without running them")
+	flag.Parse()
+	root := "."
+	if flag.NArg() > 0 {
+		root = flag.Arg(0)
+	}
+
+	fset := token.NewFileSet()
+	pkgs, err := findPackages(fset, root)
+	if err != nil {
+		log.Fatal(err)
+	}
+	ordered, err := dependencyOrder(pkgs)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	failures := 0
+	for _, p := range ordered {
+		for _, d := range p.Directives {
+			cmd := command(d)
+			fmt.Printf("%s: %s\n", d.Pos, quoteArgs(cmd.Args))
+			if *dryRun {
+				continue
+			}
+			var stderr bytes.Buffer
+			cmd.Stdout = os.Stdout
+			cmd.Stderr = &stderr
+			if err := cmd.Run(); err != nil {
+				failures++
+				fmt.Printf("%s: FAIL: %v\n%s", d.Pos, err, stderr.Bytes())
+			}
+		}
+	}
+	if failures > 0 {
+		fmt.Printf("%d directives failed\n", failures)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,130 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+)
+
+// revealed maps an exercise path to how many of its hints have been shown.
+type revealed map[string]int
+
+func loadHints(path string) (map[string][]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	hints := map[string][]string{}
+	if err := json.Unmarshal(data, &hints); err != nil {
+		return nil, fmt.Errorf("reading %s: %w", path, err)
+	}
+	return hints, nil
+}
+
+func loadRevealed(path string) (revealed, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return revealed{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	r := revealed{}
+	if err := json.Unmarshal(data, &r); err != nil {
+		return nil, fmt.Errorf("reading %s: %w", path, err)
+	}
+	return r, nil
+}
+
+// saveRevealed writes to a temporary file and renames it into place so the
+// database is never left half written.
+func saveRevealed(path string, r revealed) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	data, err := json.Marshal(r)
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// nextHint shows every hint revealed so far plus the next one, and records
+// that one more hint has been seen. Once every hint is out it keeps showing
+// all of them.
+func nextHint(w io.Writer, hints map[string][]string, r revealed, exercise string) error {
+	list, ok := hints[exercise]
+	if !ok || len(list) == 0 {
+		return fmt.Errorf("no hints for %s", exercise)
+	}
+	if r[exercise] < len(list) {
+		r[exercise]++
+	}
+	for i, h := range list[:r[exercise]] {
+		fmt.Fprintf(w, "Hint %d/%d: %s\n", i+1, len(list), h)
+	}
+	if r[exercise] == len(list) {
+		fmt.Fprintln(w, "That's the last hint for this exercise.")
+	}
+	return nil
+}
+
+func defaultDB() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return filepath.Join(".letsgo", "hints.db")
+	}
+	return filepath.Join(home, ".letsgo", "hints.db")
+}
+
+func main() {
+	hintsFile := flag.String("hints", "hints.json", "file with the hints for each exercise")
+	db := flag.String("db", defaultDB(), "file that remembers which hints you've seen")
+	flag.Usage = func() {
+		fmt.Fprintln(flag.CommandLine.Output(), "usage: hint [flags] <exercise>\n       hint [flags] reset <exercise>")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	r, err := loadRevealed(*db)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	switch {
+	case flag.NArg() == 2 && flag.Arg(0) == "reset":
+		delete(r, flag.Arg(1))
+	case flag.NArg() == 1:
+		hints, err := loadHints(*hintsFile)
+		if err != nil {
+			log.Fatal(err)
+		}
+		if err := nextHint(os.Stdout, hints, r, flag.Arg(0)); err != nil {
+			log.Fatal(err)
+		}
+	default:
+		flag.Usage()
+		os.Exit(2)
+	}
+	if err := saveRevealed(*db, r); err != nil {
+		log.Fatal(err)
+	}
+}
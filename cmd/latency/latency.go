@@ -0,0 +1,96 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"math/bits"
+	"sort"
+	"strings"
+	"time"
+)
+
+// LatencySample is how long LeagueHandler took to serve one request, see
+// AttachLatencyProbe.
+type LatencySample struct {
+	Endpoint string
+	Duration time.Duration
+}
+
+// LatencyHistogram counts latency samples per endpoint in power of two
+// buckets of microseconds. Bucket 0 is anything under 2µs and bucket i is
+// from 2^i up to 2^(i+1) µs.
+type LatencyHistogram map[string][]int
+
+// Add counts s.
+func (h LatencyHistogram) Add(s LatencySample) {
+	i := 0
+	if us := s.Duration.Microseconds(); us > 1 {
+		i = bits.Len64(uint64(us)) - 1
+	}
+	counts := h[s.Endpoint]
+	for len(counts) <= i {
+		counts = append(counts, 0)
+	}
+	counts[i]++
+	h[s.Endpoint] = counts
+}
+
+// Write writes a bar chart for each endpoint, in endpoint order, with the
+// bars scaled so the biggest bucket is 40 characters long.
+func (h LatencyHistogram) Write(w io.Writer) {
+	endpoints := make([]string, 0, len(h))
+	most := 0
+	for e, counts := range h {
+		endpoints = append(endpoints, e)
+		for _, c := range counts {
+			most = max(most, c)
+		}
+	}
+	sort.Strings(endpoints)
+	for _, e := range endpoints {
+		total := 0
+		for _, c := range h[e] {
+			total += c
+		}
+		fmt.Fprintf(w, "%s (%d requests)\n", e, total)
+		for i, c := range h[e] {
+			if c == 0 {
+				continue
+			}
+			lo := time.Duration(1<<i) * time.Microsecond
+			if i == 0 {
+				lo = 0
+			}
+			hi := time.Duration(2<<i) * time.Microsecond
+			fmt.Fprintf(w, "  %10v - %-10v %-40s %d\n", lo, hi, strings.Repeat("#", (c*40+most-1)/most), c)
+		}
+	}
+}
+
+// printLatency adds every sample to a histogram and writes the whole
+// histogram to w each time every passes, until samples is closed.
+func printLatency(w io.Writer, samples <-chan LatencySample, every time.Duration) {
+	h := LatencyHistogram{}
+	tick := time.NewTicker(every)
+	defer tick.Stop()
+	changed := false
+	for {
+		select {
+		case s, ok := <-samples:
+			if !ok {
+				if changed {
+					h.Write(w)
+				}
+				return
+			}
+			h.Add(s)
+			changed = true
+		case t := <-tick.C:
+			if changed {
+				fmt.Fprintf(w, "--- %s\n", t.Format(time.TimeOnly))
+				h.Write(w)
+				changed = false
+			}
+		}
+	}
+}
@@ -0,0 +1,357 @@
+//go:build linux
+
+package main
+
+import (
+	"debug/elf"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+	"unsafe"
+
+	"github.com/cilium/ebpf"
+	"github.com/cilium/ebpf/asm"
+	"github.com/cilium/ebpf/link"
+	"github.com/cilium/ebpf/ringbuf"
+	"github.com/cilium/ebpf/rlimit"
+	"golang.org/x/arch/x86/x86asm"
+)
+
+// The probe is two BPF programs, written out as instructions so there's no
+// C to compile. One runs when leagueHandler.ServeHTTP is entered and stores
+// the time and r.URL.Path under the goroutine's g pointer, the other runs at
+// each of the function's RET instructions and sends the elapsed time to a
+// ring buffer. Go moves goroutine stacks, which breaks uretprobes, so the
+// RETs are found by disassembling the function instead.
+//
+// Go's register ABI passes r in RDI and keeps g in R14 on amd64, so these
+// are the offsets of those registers in the kernel's struct pt_regs.
+const (
+	ptRegsR14 = 8
+	ptRegsRDI = 112
+)
+
+// maxEndpoint is how many bytes of the path are kept.
+const maxEndpoint = 64
+
+// latencyEvent is laid out like the values in the starts map and the ring
+// buffer records: the start time, or the duration once it's sent, then the
+// path length and the path.
+const latencyEvent = 16 + maxEndpoint
+
+// The offsets of r.URL and URL.Path, read from this build of net/http and
+// net/url. The probed program has to be built with the same Go version.
+var (
+	requestURLOffset = int32(unsafe.Offsetof(http.Request{}.URL))
+	urlPathOffset    = int32(unsafe.Offsetof(url.URL{}.Path))
+)
+
+// AttachLatencyProbe traces LeagueHandler in the process with the given pid,
+// which must be running an unstripped build of the league server from
+// learning_go/exercises/07/ex3, and sends a sample for each request it serves. stop detaches the
+// probe and closes the channel, and can be called more than once. It needs
+// root, or CAP_BPF and CAP_PERFMON, and only works on amd64.
+//
+// Samples are dropped if they aren't received quickly enough.
+func AttachLatencyProbe(pid int) (<-chan LatencySample, func(), error) {
+	if runtime.GOARCH != "amd64" {
+		return nil, nil, fmt.Errorf("latency probe: %w on %s", errors.ErrUnsupported, runtime.GOARCH)
+	}
+	exe := fmt.Sprintf("/proc/%d/exe", pid)
+	symbol, rets, err := handlerReturns(exe)
+	if err != nil {
+		return nil, nil, fmt.Errorf("latency probe: %w", err)
+	}
+	if err := rlimit.RemoveMemlock(); err != nil {
+		return nil, nil, fmt.Errorf("latency probe: %w", err)
+	}
+
+	p := &latencyProbe{}
+	if err := p.load(); err != nil {
+		p.close()
+		return nil, nil, fmt.Errorf("latency probe: %w", err)
+	}
+	ex, err := link.OpenExecutable(exe)
+	if err != nil {
+		p.close()
+		return nil, nil, fmt.Errorf("latency probe: %w", err)
+	}
+	l, err := ex.Uprobe(symbol, p.entry, &link.UprobeOptions{PID: pid})
+	if err != nil {
+		p.close()
+		return nil, nil, fmt.Errorf("latency probe: attaching to %s: %w", symbol, err)
+	}
+	p.links = append(p.links, l)
+	for _, off := range rets {
+		l, err := ex.Uprobe(symbol, p.exit, &link.UprobeOptions{Offset: off, PID: pid})
+		if err != nil {
+			p.close()
+			return nil, nil, fmt.Errorf("latency probe: attaching to %s+%#x: %w", symbol, off, err)
+		}
+		p.links = append(p.links, l)
+	}
+	p.reader, err = ringbuf.NewReader(p.events)
+	if err != nil {
+		p.close()
+		return nil, nil, fmt.Errorf("latency probe: %w", err)
+	}
+
+	samples := make(chan LatencySample, 64)
+	p.wg.Add(1)
+	go func() {
+		defer p.wg.Done()
+		defer close(samples)
+		for {
+			rec, err := p.reader.Read()
+			if err != nil {
+				return
+			}
+			if len(rec.RawSample) < latencyEvent {
+				continue
+			}
+			n := min(binary.NativeEndian.Uint64(rec.RawSample[8:]), maxEndpoint)
+			s := LatencySample{
+				Endpoint: string(rec.RawSample[16 : 16+n]),
+				Duration: time.Duration(binary.NativeEndian.Uint64(rec.RawSample)),
+			}
+			select {
+			case samples <- s:
+			default:
+			}
+		}
+	}()
+	return samples, p.close, nil
+}
+
+// handlerReturns finds leagueHandler.ServeHTTP in the executable at path,
+// and returns its symbol name and the offset of each RET instruction in it.
+// The symbol is main.(*leagueHandler).ServeHTTP, or has the package's import
+// path in place of main in a test binary. Binaries that load plugins, as the
+// league server's tests do, export their functions as dynamic symbols, so
+// both tables are searched, the same as link.Executable does.
+func handlerReturns(path string) (string, []uint64, error) {
+	f, err := elf.Open(path)
+	if err != nil {
+		return "", nil, err
+	}
+	defer f.Close()
+	syms, err := f.Symbols()
+	if err != nil && !errors.Is(err, elf.ErrNoSymbols) {
+		return "", nil, err
+	}
+	dynsyms, err := f.DynamicSymbols()
+	if err != nil && !errors.Is(err, elf.ErrNoSymbols) {
+		return "", nil, err
+	}
+	var sym elf.Symbol
+	for _, s := range append(syms, dynsyms...) {
+		if elf.ST_TYPE(s.Info) == elf.STT_FUNC && strings.HasSuffix(s.Name, ".(*leagueHandler).ServeHTTP") {
+			sym = s
+			break
+		}
+	}
+	if sym.Name == "" {
+		return "", nil, fmt.Errorf("%s has no leagueHandler.ServeHTTP symbol", path)
+	}
+	text := f.Section(".text")
+	if text == nil || sym.Value < text.Addr || sym.Value+sym.Size > text.Addr+text.Size {
+		return "", nil, fmt.Errorf("%s isn't in the text section", sym.Name)
+	}
+	code := make([]byte, sym.Size)
+	if _, err := text.ReadAt(code, int64(sym.Value-text.Addr)); err != nil {
+		return "", nil, err
+	}
+	var rets []uint64
+	for off := 0; off < len(code); {
+		inst, err := x86asm.Decode(code[off:], 64)
+		if err != nil {
+			return "", nil, fmt.Errorf("disassembling %s at %#x: %w", sym.Name, off, err)
+		}
+		if inst.Op == x86asm.RET {
+			rets = append(rets, uint64(off))
+		}
+		off += inst.Len
+	}
+	if len(rets) == 0 {
+		return "", nil, fmt.Errorf("%s never returns", sym.Name)
+	}
+	return sym.Name, rets, nil
+}
+
+// latencyProbe is everything AttachLatencyProbe has loaded, so it can all be
+// released again.
+type latencyProbe struct {
+	starts, events *ebpf.Map
+	entry, exit    *ebpf.Program
+	links          []link.Link
+	reader         *ringbuf.Reader
+
+	wg        sync.WaitGroup
+	closeOnce sync.Once
+}
+
+// load creates the maps and programs.
+func (p *latencyProbe) load() error {
+	var err error
+	p.starts, err = ebpf.NewMap(&ebpf.MapSpec{
+		Type:       ebpf.Hash,
+		KeySize:    8,
+		ValueSize:  latencyEvent,
+		MaxEntries: 4096,
+	})
+	if err != nil {
+		return err
+	}
+	p.events, err = ebpf.NewMap(&ebpf.MapSpec{
+		Type:       ebpf.RingBuf,
+		MaxEntries: 1 << 16,
+	})
+	if err != nil {
+		return err
+	}
+	p.entry, err = ebpf.NewProgram(&ebpf.ProgramSpec{
+		Name:         "league_entry",
+		Type:         ebpf.Kprobe,
+		License:      "GPL",
+		Instructions: entryInstructions(p.starts.FD()),
+	})
+	if err != nil {
+		return fmt.Errorf("loading entry program: %w", err)
+	}
+	p.exit, err = ebpf.NewProgram(&ebpf.ProgramSpec{
+		Name:         "league_exit",
+		Type:         ebpf.Kprobe,
+		License:      "GPL",
+		Instructions: exitInstructions(p.starts.FD(), p.events.FD()),
+	})
+	if err != nil {
+		return fmt.Errorf("loading exit program: %w", err)
+	}
+	return nil
+}
+
+// close detaches the probe first, so nothing more is written to the ring
+// buffer, then stops the reader and frees the rest. Anything that wasn't
+// loaded is nil and skipped.
+func (p *latencyProbe) close() {
+	p.closeOnce.Do(func() {
+		for _, l := range p.links {
+			l.Close()
+		}
+		if p.reader != nil {
+			p.reader.Close()
+		}
+		p.wg.Wait()
+		for _, prog := range []*ebpf.Program{p.entry, p.exit} {
+			if prog != nil {
+				prog.Close()
+			}
+		}
+		for _, m := range []*ebpf.Map{p.starts, p.events} {
+			if m != nil {
+				m.Close()
+			}
+		}
+	})
+}
+
+// entryInstructions stores the start of a request in the starts map. The
+// stack holds the key at -8, the value from -88 and scratch space below it.
+func entryInstructions(starts int) asm.Instructions {
+	insns := asm.Instructions{
+		asm.Mov.Reg(asm.R6, asm.R1),
+		asm.LoadMem(asm.R7, asm.R6, ptRegsR14, asm.DWord),
+		asm.StoreMem(asm.RFP, -8, asm.R7, asm.DWord),
+	}
+	// The verifier wants the whole value written before it's stored
+	for off := int16(-88); off < -8; off += 8 {
+		insns = append(insns, asm.StoreImm(asm.RFP, off, 0, asm.DWord))
+	}
+	return append(insns,
+		asm.FnKtimeGetNs.Call(),
+		asm.StoreMem(asm.RFP, -88, asm.R0, asm.DWord),
+
+		// The URL pointer goes to -96
+		asm.Mov.Reg(asm.R1, asm.RFP),
+		asm.Add.Imm(asm.R1, -96),
+		asm.Mov.Imm(asm.R2, 8),
+		asm.LoadMem(asm.R3, asm.R6, ptRegsRDI, asm.DWord),
+		asm.Add.Imm(asm.R3, requestURLOffset),
+		asm.FnProbeReadUser.Call(),
+		asm.JNE.Imm(asm.R0, 0, "save"),
+		// and the Path string header to -112
+		asm.Mov.Reg(asm.R1, asm.RFP),
+		asm.Add.Imm(asm.R1, -112),
+		asm.Mov.Imm(asm.R2, 16),
+		asm.LoadMem(asm.R3, asm.RFP, -96, asm.DWord),
+		asm.Add.Imm(asm.R3, urlPathOffset),
+		asm.FnProbeReadUser.Call(),
+		asm.JNE.Imm(asm.R0, 0, "save"),
+
+		asm.LoadMem(asm.R8, asm.RFP, -104, asm.DWord),
+		asm.JLE.Imm(asm.R8, maxEndpoint, "copy"),
+		asm.Mov.Imm(asm.R8, maxEndpoint),
+		asm.Mov.Reg(asm.R1, asm.RFP).WithSymbol("copy"),
+		asm.Add.Imm(asm.R1, -72),
+		asm.Mov.Reg(asm.R2, asm.R8),
+		asm.LoadMem(asm.R3, asm.RFP, -112, asm.DWord),
+		asm.FnProbeReadUser.Call(),
+		asm.JNE.Imm(asm.R0, 0, "save"),
+		asm.StoreMem(asm.RFP, -80, asm.R8, asm.DWord),
+
+		asm.LoadMapPtr(asm.R1, starts).WithSymbol("save"),
+		asm.Mov.Reg(asm.R2, asm.RFP),
+		asm.Add.Imm(asm.R2, -8),
+		asm.Mov.Reg(asm.R3, asm.RFP),
+		asm.Add.Imm(asm.R3, -88),
+		asm.Mov.Imm(asm.R4, 0),
+		asm.FnMapUpdateElem.Call(),
+		asm.Mov.Imm(asm.R0, 0),
+		asm.Return(),
+	)
+}
+
+// exitInstructions looks up the goroutine's start in the starts map, and
+// sends the time since then and the path to the events ring buffer.
+func exitInstructions(starts, events int) asm.Instructions {
+	insns := asm.Instructions{
+		asm.LoadMem(asm.R7, asm.R1, ptRegsR14, asm.DWord),
+		asm.StoreMem(asm.RFP, -8, asm.R7, asm.DWord),
+		asm.LoadMapPtr(asm.R1, starts),
+		asm.Mov.Reg(asm.R2, asm.RFP),
+		asm.Add.Imm(asm.R2, -8),
+		asm.FnMapLookupElem.Call(),
+		asm.JEq.Imm(asm.R0, 0, "out"),
+		asm.Mov.Reg(asm.R7, asm.R0),
+		asm.FnKtimeGetNs.Call(),
+		asm.LoadMem(asm.R1, asm.R7, 0, asm.DWord),
+		asm.Sub.Reg(asm.R0, asm.R1),
+		asm.StoreMem(asm.RFP, -88, asm.R0, asm.DWord),
+	}
+	for off := int16(8); off < latencyEvent; off += 8 {
+		insns = append(insns,
+			asm.LoadMem(asm.R1, asm.R7, off, asm.DWord),
+			asm.StoreMem(asm.RFP, -88+off, asm.R1, asm.DWord),
+		)
+	}
+	return append(insns,
+		asm.LoadMapPtr(asm.R1, events),
+		asm.Mov.Reg(asm.R2, asm.RFP),
+		asm.Add.Imm(asm.R2, -88),
+		asm.Mov.Imm(asm.R3, latencyEvent),
+		asm.Mov.Imm(asm.R4, 0),
+		asm.FnRingbufOutput.Call(),
+		asm.LoadMapPtr(asm.R1, starts),
+		asm.Mov.Reg(asm.R2, asm.RFP),
+		asm.Add.Imm(asm.R2, -8),
+		asm.FnMapDeleteElem.Call(),
+		asm.Mov.Imm(asm.R0, 0).WithSymbol("out"),
+		asm.Return(),
+	)
+}
@@ -0,0 +1,88 @@
+//go:build linux
+
+package main
+
+import (
+	"bufio"
+	"errors"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/cilium/ebpf"
+)
+
+// startServer builds and starts testdata/server, and returns its pid and
+// address.
+func startServer(t *testing.T) (int, string) {
+	t.Helper()
+	if testing.Short() {
+		t.Skip("building the server is slow")
+	}
+	gocmd := filepath.Join(runtime.GOROOT(), "bin", "go")
+	exe := filepath.Join(t.TempDir(), "server")
+	if output, err := exec.Command(gocmd, "build", "-o", exe, "./testdata/server").CombinedOutput(); err != nil {
+		t.Fatalf("building the server: %v\n%s", err, output)
+	}
+	cmd := exec.Command(exe)
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := cmd.Start(); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		cmd.Process.Kill()
+		cmd.Wait()
+	})
+	addr, err := bufio.NewReader(stdout).ReadString('\n')
+	if err != nil {
+		t.Fatal("reading the server's address:", err)
+	}
+	return cmd.Process.Pid, "http://" + addr[:len(addr)-1]
+}
+
+func TestAttachLatencyProbe(t *testing.T) {
+	pid, url := startServer(t)
+	samples, stop, err := AttachLatencyProbe(pid)
+	if errors.Is(err, os.ErrPermission) || errors.Is(err, ebpf.ErrNotSupported) || errors.Is(err, errors.ErrUnsupported) {
+		t.Skip("can't load eBPF programs here:", err)
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer stop()
+
+	for _, path := range []string{"/standings", "/teams/USA", "/teams/USA/players"} {
+		resp, err := http.Get(url + path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+	}
+
+	want := map[string]bool{"/standings": true, "/teams/USA": true, "/teams/USA/players": true}
+	timeout := time.After(time.Second)
+	for len(want) > 0 {
+		select {
+		case s := <-samples:
+			if s.Duration <= 0 || s.Duration > time.Second {
+				t.Errorf("%s took %v", s.Endpoint, s.Duration)
+			}
+			delete(want, s.Endpoint)
+		case <-timeout:
+			t.Fatalf("no samples within a second for %v", want)
+		}
+	}
+
+	stop()
+	for range samples {
+	}
+	// Calling it again is fine
+	stop()
+}
@@ -0,0 +1,10 @@
+//go:build !linux
+
+package main
+
+import "errors"
+
+// AttachLatencyProbe needs eBPF, so it's only implemented on Linux.
+func AttachLatencyProbe(pid int) (<-chan LatencySample, func(), error) {
+	return nil, nil, errors.ErrUnsupported
+}
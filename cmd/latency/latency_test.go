@@ -0,0 +1,51 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+	"time"
+)
+
+func TestLatencyHistogram(t *testing.T) {
+	h := LatencyHistogram{}
+	for _, s := range []LatencySample{
+		{"/standings", 500 * time.Nanosecond},
+		{"/standings", 1500 * time.Nanosecond},
+		{"/standings", 3 * time.Microsecond},
+		{"/match", 1 * time.Millisecond},
+		{"/match", 1023 * time.Microsecond},
+	} {
+		h.Add(s)
+	}
+	if got := h["/standings"]; len(got) != 2 || got[0] != 2 || got[1] != 1 {
+		t.Errorf("/standings buckets %v, want [2 1]", got)
+	}
+	// 1000µs and 1023µs are both from 512µs up to 1.024ms
+	if got := h["/match"]; len(got) != 10 || got[9] != 2 {
+		t.Errorf("/match buckets %v, want 2 in bucket 9", got)
+	}
+
+	var b bytes.Buffer
+	h.Write(&b)
+	want := `/match (2 requests)
+       512µs - 1.024ms    ######################################## 2
+/standings (3 requests)
+          0s - 2µs        ######################################## 2
+         2µs - 4µs        ####################                     1
+`
+	if b.String() != want {
+		t.Errorf("got\n%s\nwant\n%s", b.String(), want)
+	}
+}
+
+func TestPrintLatency(t *testing.T) {
+	samples := make(chan LatencySample, 2)
+	samples <- LatencySample{"/standings", time.Microsecond}
+	samples <- LatencySample{"/standings", time.Microsecond}
+	close(samples)
+	var b bytes.Buffer
+	printLatency(&b, samples, time.Hour)
+	if want := "/standings (2 requests)\n"; !bytes.HasPrefix(b.Bytes(), []byte(want)) {
+		t.Errorf("got %q, want it to start with %q", b.String(), want)
+	}
+}
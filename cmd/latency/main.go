@@ -0,0 +1,32 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"time"
+)
+
+func main() {
+	pid := flag.Int("pid", 0, "the `pid` of the league server to trace")
+	every := flag.Duration("every", time.Second, "how often to print the histogram")
+	flag.Parse()
+	if *pid == 0 {
+		fmt.Fprintln(os.Stderr, "usage: latency -pid pid")
+		flag.PrintDefaults()
+		os.Exit(2)
+	}
+	samples, stop, err := AttachLatencyProbe(*pid)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	interrupt := make(chan os.Signal, 1)
+	signal.Notify(interrupt, os.Interrupt)
+	go func() {
+		<-interrupt
+		stop()
+	}()
+	printLatency(os.Stdout, samples, *every)
+}
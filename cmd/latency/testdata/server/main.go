@@ -0,0 +1,26 @@
+// Command server stands in for the league server in TestAttachLatencyProbe.
+// It serves every path with a leagueHandler, which is what the probe looks
+// for, and prints its address once it's listening.
+package main
+
+import (
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+)
+
+type leagueHandler struct{}
+
+func (*leagueHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	fmt.Fprintln(w, r.URL.Path)
+}
+
+func main() {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println(l.Addr())
+	log.Fatal(http.Serve(l, &leagueHandler{}))
+}
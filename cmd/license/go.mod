@@ -0,0 +1,3 @@
+module license
+
+go 1.21.3
@@ -0,0 +1,173 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"flag"
+	"fmt"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"text/template"
+	"time"
+)
+
+const defaultTemplate = `Copyright {{.Year}} {{.Author}}. All rights reserved.
+Use of this source code is governed by the MIT license
+that can be found in the LICENSE file.`
+
+// headerData fills in the placeholders of a header template.
+type headerData struct {
+	Year   string
+	Author string
+}
+
+// yearMarker stands in for the year while building the pattern that matches
+// existing headers, so files written in earlier years still pass.
+const yearMarker = "\x00YEAR\x00"
+
+// renderHeader executes the template and turns each line of the result into
+// a // comment.
+func renderHeader(tmpl *template.Template, data headerData) (string, error) {
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", err
+	}
+	var out strings.Builder
+	scanner := bufio.NewScanner(&buf)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), " \t")
+		if line == "" {
+			out.WriteString("//\n")
+			continue
+		}
+		out.WriteString("// " + line + "\n")
+	}
+	return out.String(), scanner.Err()
+}
+
+// headerPattern matches the rendered header with any year, or year range, in
+// place of {{.Year}}.
+func headerPattern(tmpl *template.Template, author string) (*regexp.Regexp, error) {
+	header, err := renderHeader(tmpl, headerData{Year: yearMarker, Author: author})
+	if err != nil {
+		return nil, err
+	}
+	expr := regexp.QuoteMeta(header)
+	expr = strings.ReplaceAll(expr, yearMarker, `\d{4}(-\d{4})?`)
+	return regexp.Compile(`\A` + expr)
+}
+
+type status int
+
+const (
+	ok status = iota
+	missing
+	different
+)
+
+func (s status) String() string {
+	switch s {
+	case missing:
+		return "missing license header"
+	case different:
+		return "different license header"
+	}
+	return "ok"
+}
+
+// checkFile reports whether src starts with the header. A file that starts
+// with some other comment is treated as having a different header, unless
+// that comment is a build constraint or the package doc.
+func checkFile(src []byte, pattern *regexp.Regexp) status {
+	if pattern.Match(src) {
+		return ok
+	}
+	first, _, _ := strings.Cut(string(src), "\n")
+	switch {
+	case strings.HasPrefix(first, "//go:build"), strings.HasPrefix(first, "// Package "):
+		return missing
+	case strings.HasPrefix(first, "//"), strings.HasPrefix(first, "/*"):
+		return different
+	}
+	return missing
+}
+
+func main() {
+	fix := flag.Bool("fix", false, "add the header to files that are missing it")
+	templateFile := flag.String("template", "", "file holding the header template, supports {{.Year}} and {{.Author}}")
+	author := flag.String("author", "The LetsGo Authors", "value for {{.Author}}")
+	year := flag.Int("year", time.Now().Year(), "value for {{.Year}} when adding headers")
+	flag.Parse()
+	root := "."
+	if flag.NArg() > 0 {
+		root = flag.Arg(0)
+	}
+
+	text := defaultTemplate
+	if *templateFile != "" {
+		b, err := os.ReadFile(*templateFile)
+		if err != nil {
+			log.Fatal(err)
+		}
+		text = string(b)
+	}
+	tmpl, err := template.New("header").Option("missingkey=error").Parse(text)
+	if err != nil {
+		log.Fatal(err)
+	}
+	header, err := renderHeader(tmpl, headerData{Year: fmt.Sprint(*year), Author: *author})
+	if err != nil {
+		log.Fatal(err)
+	}
+	pattern, err := headerPattern(tmpl, *author)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	failed := false
+	err = filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() && d.Name() == ".git" {
+			return filepath.SkipDir
+		}
+		if d.IsDir() || !strings.HasSuffix(path, ".go") {
+			return nil
+		}
+		src, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		s := checkFile(src, pattern)
+		if s == ok {
+			return nil
+		}
+		// A different header is left alone, it may be a third party license
+		// that has to stay
+		if *fix && s == missing {
+			info, err := d.Info()
+			if err != nil {
+				return err
+			}
+			if err := os.WriteFile(path, append([]byte(header+"\n"), src...), info.Mode().Perm()); err != nil {
+				return err
+			}
+			fmt.Println("fixed", path)
+			return nil
+		}
+		fmt.Printf("%s: %s\n", path, s)
+		failed = true
+		return nil
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+	if failed {
+		os.Exit(1)
+	}
+}
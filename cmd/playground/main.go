@@ -0,0 +1,141 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+const (
+	playURL  = "https://go.dev/play/p/"
+	shareURL = "https://go.dev/_/share"
+	badgeURL = "https://img.shields.io/badge/Go-Run_on_Playground-00ADD8?logo=go"
+)
+
+// share uploads source to the playground and returns the snippet ID. The
+// playground can't load code straight from a URL, so every example has to be
+// shared first to get a link. It's a variable so it can be replaced without
+// talking to the real playground.
+var share = func(source []byte) (string, error) {
+	resp, err := http.Post(shareURL, "text/plain; charset=utf-8", bytes.NewReader(source))
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("share failed: %s: %s", resp.Status, body)
+	}
+	return strings.TrimSpace(string(body)), nil
+}
+
+// playgroundURL returns the link for a shared snippet ID.
+func playgroundURL(id string) string {
+	return playURL + id
+}
+
+// bundle returns the Go files and go.mod in dir in the playground's txtar
+// format, where each file is introduced by a "-- name --" line. A directory
+// with a single Go file is sent as plain source.
+func bundle(dir string) ([]byte, error) {
+	goFiles, err := filepath.Glob(filepath.Join(dir, "*.go"))
+	if err != nil {
+		return nil, err
+	}
+	var files []string
+	for _, f := range goFiles {
+		if !strings.HasSuffix(f, "_test.go") {
+			files = append(files, f)
+		}
+	}
+	sort.Strings(files)
+	if len(files) == 1 {
+		return os.ReadFile(files[0])
+	}
+	var buf bytes.Buffer
+	for _, f := range append(files, filepath.Join(dir, "go.mod")) {
+		data, err := os.ReadFile(f)
+		if err != nil {
+			return nil, err
+		}
+		fmt.Fprintf(&buf, "-- %s --\n", filepath.Base(f))
+		buf.Write(data)
+	}
+	return buf.Bytes(), nil
+}
+
+// exampleDirs returns the directories under root that hold a runnable
+// module, that is a go.mod next to Go files declaring package main.
+func exampleDirs(root string) ([]string, error) {
+	var dirs []string
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() && d.Name() == ".git" {
+			return filepath.SkipDir
+		}
+		if d.IsDir() || d.Name() != "go.mod" {
+			return nil
+		}
+		dir := filepath.Dir(path)
+		goFiles, err := filepath.Glob(filepath.Join(dir, "*.go"))
+		if err != nil {
+			return err
+		}
+		for _, f := range goFiles {
+			data, err := os.ReadFile(f)
+			if err != nil {
+				return err
+			}
+			if bytes.Contains(data, []byte("package main")) && bytes.Contains(data, []byte("func main()")) {
+				dirs = append(dirs, dir)
+				break
+			}
+		}
+		return nil
+	})
+	return dirs, err
+}
+
+func main() {
+	root := flag.String("root", ".", "directory to search for examples")
+	out := flag.String("o", "EXAMPLES.md", "markdown file to append the badges to")
+	flag.Parse()
+
+	dirs, err := exampleDirs(*root)
+	if err != nil {
+		log.Fatal(err)
+	}
+	f, err := os.OpenFile(*out, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer f.Close()
+	for _, dir := range dirs {
+		source, err := bundle(dir)
+		if err != nil {
+			log.Fatal(err)
+		}
+		id, err := share(source)
+		if err != nil {
+			log.Fatalf("sharing %s: %v", dir, err)
+		}
+		rel, err := filepath.Rel(*root, dir)
+		if err != nil {
+			rel = dir
+		}
+		fmt.Fprintf(f, "## %s\n\n[![Run on Playground](%s)](%s)\n\n", filepath.ToSlash(rel), badgeURL, playgroundURL(id))
+	}
+}
@@ -0,0 +1,178 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+const (
+	green = "\033[32m"
+	gray  = "\033[90m"
+	reset = "\033[0m"
+
+	barWidth = 20
+)
+
+// Entry is what's recorded for a single exercise.
+type Entry struct {
+	Completed bool   `json:"completed"`
+	Notes     string `json:"notes,omitempty"`
+}
+
+// Progress maps an exercise path such as "07/ex3" to its entry.
+type Progress map[string]Entry
+
+func load(path string) (Progress, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return Progress{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	p := Progress{}
+	if err := json.Unmarshal(data, &p); err != nil {
+		return nil, fmt.Errorf("reading %s: %w", path, err)
+	}
+	return p, nil
+}
+
+// save writes the progress to a temporary file next to path and renames it
+// into place, so an interrupted save never leaves a half written file.
+func save(path string, p Progress) error {
+	data, err := json.MarshalIndent(p, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(append(data, '\n')); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// mark sets the completed state of an exercise. Notes are only replaced when
+// notes isn't empty.
+func mark(p Progress, exercise string, completed bool, notes string) {
+	e := p[exercise]
+	e.Completed = completed
+	if notes != "" {
+		e.Notes = notes
+	}
+	p[exercise] = e
+}
+
+// discover adds an empty entry for every exercise module under root that
+// isn't tracked yet, so chapters show how much is left to do.
+func discover(p Progress, root string) error {
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || d.Name() != "go.mod" {
+			return nil
+		}
+		rel, err := filepath.Rel(root, filepath.Dir(path))
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		if _, ok := p[rel]; !ok {
+			p[rel] = Entry{}
+		}
+		return nil
+	})
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	return err
+}
+
+func bar(done, total int) string {
+	filled := 0
+	if total > 0 {
+		filled = done * barWidth / total
+	}
+	return green + strings.Repeat("#", filled) + gray + strings.Repeat("-", barWidth-filled) + reset
+}
+
+// show prints a progress bar for each chapter, the part of the exercise path
+// before the first slash.
+func show(w io.Writer, p Progress) {
+	type counts struct{ done, total int }
+	chapters := map[string]*counts{}
+	for exercise, e := range p {
+		chapter, _, _ := strings.Cut(exercise, "/")
+		c, ok := chapters[chapter]
+		if !ok {
+			c = &counts{}
+			chapters[chapter] = c
+		}
+		c.total++
+		if e.Completed {
+			c.done++
+		}
+	}
+	names := make([]string, 0, len(chapters))
+	for name := range chapters {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		c := chapters[name]
+		fmt.Fprintf(w, "%-4s [%s] %d/%d\n", name, bar(c.done, c.total), c.done, c.total)
+	}
+}
+
+func main() {
+	file := flag.String("file", "progress.json", "file that stores your progress")
+	root := flag.String("root", "learning_go/exercises", "directory containing the exercises")
+	notes := flag.String("notes", "", "notes to save with mark or unmark")
+	flag.Usage = func() {
+		fmt.Fprintln(flag.CommandLine.Output(), "usage: progress [flags] [mark|unmark <exercise>]")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	p, err := load(*file)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	switch cmd := flag.Arg(0); cmd {
+	case "":
+		if err := discover(p, *root); err != nil {
+			log.Fatal(err)
+		}
+		show(os.Stdout, p)
+	case "mark", "unmark":
+		if flag.NArg() != 2 {
+			flag.Usage()
+			os.Exit(2)
+		}
+		mark(p, flag.Arg(1), cmd == "mark", *notes)
+		if err := save(*file, p); err != nil {
+			log.Fatal(err)
+		}
+	default:
+		flag.Usage()
+		os.Exit(2)
+	}
+}
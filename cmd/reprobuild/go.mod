@@ -0,0 +1,3 @@
+module reprobuild
+
+go 1.21.3
@@ -0,0 +1,162 @@
+package main
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"flag"
+	"fmt"
+	"io/fs"
+	"log"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// result is the outcome of building one binary twice.
+type result struct {
+	Binary string
+	Hashes [2][sha256.Size]byte
+	// Offset is the first byte that differs between the two builds, or -1
+	// if they're identical
+	Offset int
+}
+
+// modules returns the path, relative to root, of every module under root.
+func modules(root string) ([]string, error) {
+	var out []string
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() && d.Name() == ".git" {
+			return filepath.SkipDir
+		}
+		if d.IsDir() || d.Name() != "go.mod" {
+			return nil
+		}
+		rel, err := filepath.Rel(root, filepath.Dir(path))
+		if err != nil {
+			return err
+		}
+		out = append(out, rel)
+		return nil
+	})
+	sort.Strings(out)
+	return out, err
+}
+
+// build compiles the module in dir into out. -trimpath keeps the checkout
+// location out of the binary, which would otherwise differ between machines.
+func build(dir, out, cache string, epoch int64, ldflags string) error {
+	var stderr bytes.Buffer
+	cmd := exec.Command("go", "build", "-trimpath", "-ldflags", ldflags, "-o", out+string(filepath.Separator), "./...")
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), "SOURCE_DATE_EPOCH="+strconv.FormatInt(epoch, 10))
+	if cache != "" {
+		cmd.Env = append(cmd.Env, "GOCACHE="+cache)
+	}
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("%w: %s", err, bytes.TrimSpace(stderr.Bytes()))
+	}
+	return nil
+}
+
+// firstDiff returns the offset of the first byte that differs between a and
+// b, or -1 if they're equal.
+func firstDiff(a, b []byte) int {
+	n := min(len(a), len(b))
+	for i := 0; i < n; i++ {
+		if a[i] != b[i] {
+			return i
+		}
+	}
+	if len(a) != len(b) {
+		return n
+	}
+	return -1
+}
+
+// check builds the module in dir twice and compares every binary it
+// produces. The second build uses a separate build cache, so it can't just
+// reuse the output of the first.
+func check(dir, cache string, epoch int64, ldflags string) ([]result, error) {
+	var outs [2]string
+	caches := [2]string{"", cache}
+	for i := range outs {
+		out, err := os.MkdirTemp("", "reprobuild")
+		if err != nil {
+			return nil, err
+		}
+		defer os.RemoveAll(out)
+		if err := build(dir, out, caches[i], epoch, ldflags); err != nil {
+			return nil, err
+		}
+		outs[i] = out
+	}
+	entries, err := os.ReadDir(outs[0])
+	if err != nil {
+		return nil, err
+	}
+	var results []result
+	for _, e := range entries {
+		first, err := os.ReadFile(filepath.Join(outs[0], e.Name()))
+		if err != nil {
+			return nil, err
+		}
+		second, err := os.ReadFile(filepath.Join(outs[1], e.Name()))
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, result{
+			Binary: e.Name(),
+			Hashes: [2][sha256.Size]byte{sha256.Sum256(first), sha256.Sum256(second)},
+			Offset: firstDiff(first, second),
+		})
+	}
+	return results, nil
+}
+
+func main() {
+	root := flag.String("root", ".", "directory to search for modules")
+	epoch := flag.Int64("epoch", time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC).Unix(), "value for SOURCE_DATE_EPOCH")
+	ldflags := flag.String("ldflags", "", "extra flags passed to the linker, for stamping versions into binaries")
+	flag.Parse()
+
+	mods, err := modules(*root)
+	if err != nil {
+		log.Fatal(err)
+	}
+	// The cache for the second builds is shared between modules, so the
+	// standard library is only rebuilt once
+	cache, err := os.MkdirTemp("", "reprobuild-cache")
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer os.RemoveAll(cache)
+
+	failed := false
+	for _, m := range mods {
+		results, err := check(filepath.Join(*root, m), cache, *epoch, *ldflags)
+		if err != nil {
+			fmt.Printf("%s: build failed: %v\n", m, err)
+			failed = true
+			continue
+		}
+		for _, r := range results {
+			if r.Offset < 0 {
+				fmt.Printf("%s/%s: reproducible %x\n", m, r.Binary, r.Hashes[0])
+				continue
+			}
+			fmt.Printf("%s/%s: NOT reproducible, %x != %x, first difference at byte %d\n", m, r.Binary, r.Hashes[0], r.Hashes[1], r.Offset)
+			failed = true
+		}
+	}
+	if failed {
+		os.RemoveAll(cache)
+		os.Exit(1)
+	}
+}
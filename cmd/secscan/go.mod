@@ -0,0 +1,3 @@
+module secscan
+
+go 1.21.3
@@ -0,0 +1,166 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"flag"
+	"fmt"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+type pattern struct {
+	Name string
+	Re   *regexp.Regexp
+}
+
+var patterns = []pattern{
+	{"AWS access key", regexp.MustCompile(`\b(AKIA|ASIA)[0-9A-Z]{16}\b`)},
+	{"AWS secret key", regexp.MustCompile(`(?i)aws.{0,20}(secret|key).{0,10}["'][0-9a-zA-Z/+]{40}["']`)},
+	{"private key", regexp.MustCompile(`-----BEGIN ([A-Z]+ )?PRIVATE KEY-----`)},
+	{"password", regexp.MustCompile(`(?i)(password|passwd|pwd)\w*\s*(:=|=|:)\s*["'][^"']{4,}["']`)},
+}
+
+// Finding is a line that looks like it contains a secret.
+type Finding struct {
+	Path    string
+	Line    int
+	Pattern string
+}
+
+// ignoreList holds the entries of a .secscanignore file. Each line is either
+// a file glob, which skips whole files, or path:line, which skips a single
+// finding. Blank lines and lines starting with # are ignored.
+type ignoreList struct {
+	globs []string
+	lines map[string]bool
+}
+
+func loadIgnore(path string) (ignoreList, error) {
+	ig := ignoreList{lines: map[string]bool{}}
+	f, err := os.Open(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return ig, nil
+	}
+	if err != nil {
+		return ig, err
+	}
+	defer f.Close()
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		entry := strings.TrimSpace(scanner.Text())
+		if entry == "" || strings.HasPrefix(entry, "#") {
+			continue
+		}
+		if i := strings.LastIndex(entry, ":"); i >= 0 {
+			if _, err := strconv.Atoi(entry[i+1:]); err == nil {
+				ig.lines[filepath.ToSlash(entry)] = true
+				continue
+			}
+		}
+		ig.globs = append(ig.globs, filepath.ToSlash(entry))
+	}
+	return ig, scanner.Err()
+}
+
+func (ig ignoreList) skipFile(rel string) bool {
+	for _, g := range ig.globs {
+		if ok, _ := filepath.Match(g, rel); ok {
+			return true
+		}
+		if ok, _ := filepath.Match(g, filepath.Base(rel)); ok {
+			return true
+		}
+	}
+	return false
+}
+
+func (ig ignoreList) skipLine(rel string, line int) bool {
+	return ig.lines[rel+":"+strconv.Itoa(line)]
+}
+
+// scanFile reports every line in path that matches one of the patterns.
+func scanFile(path string) ([]Finding, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	var findings []Finding
+	scanner := bufio.NewScanner(f)
+	for line := 1; scanner.Scan(); line++ {
+		text := scanner.Text()
+		for _, p := range patterns {
+			if p.Re.MatchString(text) {
+				findings = append(findings, Finding{Path: path, Line: line, Pattern: p.Name})
+			}
+		}
+	}
+	return findings, scanner.Err()
+}
+
+// scan checks every .go file under root, leaving out anything in ig. Paths in
+// the results are relative to root.
+func scan(root string, ig ignoreList) ([]Finding, error) {
+	var all []Finding
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() && d.Name() == ".git" {
+			return filepath.SkipDir
+		}
+		if d.IsDir() || !strings.HasSuffix(path, ".go") {
+			return nil
+		}
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		if ig.skipFile(rel) {
+			return nil
+		}
+		found, err := scanFile(path)
+		if err != nil {
+			return err
+		}
+		for _, f := range found {
+			if !ig.skipLine(rel, f.Line) {
+				f.Path = rel
+				all = append(all, f)
+			}
+		}
+		return nil
+	})
+	return all, err
+}
+
+func main() {
+	ignoreFile := flag.String("ignore", ".secscanignore", "file listing known false positives, relative to the scanned directory")
+	flag.Parse()
+	root := "."
+	if flag.NArg() > 0 {
+		root = flag.Arg(0)
+	}
+
+	ig, err := loadIgnore(filepath.Join(root, *ignoreFile))
+	if err != nil {
+		log.Fatal(err)
+	}
+	findings, err := scan(root, ig)
+	if err != nil {
+		log.Fatal(err)
+	}
+	for _, f := range findings {
+		fmt.Printf("%s:%d: possible %s\n", f.Path, f.Line, f.Pattern)
+	}
+	if len(findings) > 0 {
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,3 @@
+module sizecheck
+
+go 1.21.3
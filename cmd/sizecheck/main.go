@@ -0,0 +1,163 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io/fs"
+	"log"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+)
+
+// modules returns the path, relative to root, of every module under root.
+func modules(root string) ([]string, error) {
+	var out []string
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() && d.Name() == ".git" {
+			return filepath.SkipDir
+		}
+		if d.IsDir() || d.Name() != "go.mod" {
+			return nil
+		}
+		rel, err := filepath.Rel(root, filepath.Dir(path))
+		if err != nil {
+			return err
+		}
+		out = append(out, rel)
+		return nil
+	})
+	sort.Strings(out)
+	return out, err
+}
+
+// binarySizes builds the module in dir and returns the size of each binary,
+// keyed by module/binary.
+func binarySizes(root, module string) (map[string]int64, error) {
+	out, err := os.MkdirTemp("", "sizecheck")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(out)
+	var stderr bytes.Buffer
+	cmd := exec.Command("go", "build", "-trimpath", "-o", out+string(filepath.Separator), "./...")
+	cmd.Dir = filepath.Join(root, module)
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("%w: %s", err, bytes.TrimSpace(stderr.Bytes()))
+	}
+	entries, err := os.ReadDir(out)
+	if err != nil {
+		return nil, err
+	}
+	sizes := map[string]int64{}
+	for _, e := range entries {
+		info, err := e.Info()
+		if err != nil {
+			return nil, err
+		}
+		sizes[filepath.ToSlash(filepath.Join(module, e.Name()))] = info.Size()
+	}
+	return sizes, nil
+}
+
+func loadSizes(path string) (map[string]int64, error) {
+	sizes := map[string]int64{}
+	b, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return sizes, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(b, &sizes); err != nil {
+		return nil, fmt.Errorf("reading %s: %w", path, err)
+	}
+	return sizes, nil
+}
+
+// saveSizes writes the sizes to a temporary file first and renames it, so an
+// interrupted run can't leave a half written baseline.
+func saveSizes(path string, sizes map[string]int64) error {
+	b, err := json.MarshalIndent(sizes, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, append(b, '\n'), 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+func main() {
+	root := flag.String("root", ".", "directory to search for modules")
+	baseline := flag.String("baseline", "sizes.json", "file holding the sizes from the last run")
+	maxGrowth := flag.Float64("max-growth", 10, "largest allowed growth in percent")
+	update := flag.Bool("update", false, "accept the current sizes as the new baseline, even if some grew too much")
+	flag.Parse()
+
+	old, err := loadSizes(*baseline)
+	if err != nil {
+		log.Fatal(err)
+	}
+	mods, err := modules(*root)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	current := map[string]int64{}
+	failed := false
+	for _, m := range mods {
+		sizes, err := binarySizes(*root, m)
+		if err != nil {
+			// A module that doesn't build can't be measured, but that's
+			// for the build checks to report
+			fmt.Printf("%s: skipped, %v\n", m, err)
+			continue
+		}
+		for name, size := range sizes {
+			current[name] = size
+		}
+	}
+
+	names := make([]string, 0, len(current))
+	for name := range current {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		size := current[name]
+		prev, ok := old[name]
+		if !ok || prev == 0 {
+			fmt.Printf("%s: %d bytes (new)\n", name, size)
+			continue
+		}
+		growth := float64(size-prev) / float64(prev) * 100
+		status := "ok"
+		if growth > *maxGrowth {
+			status = "FAIL"
+			failed = true
+		}
+		fmt.Printf("%s: %d bytes, was %d (%+.1f%%) %s\n", name, size, prev, growth, status)
+	}
+
+	// A failed run keeps the old baseline, so the check keeps failing until
+	// the growth is accepted with -update
+	if !failed || *update {
+		if err := saveSizes(*baseline, current); err != nil {
+			log.Fatal(err)
+		}
+	}
+	if failed && !*update {
+		fmt.Printf("binaries grew more than %.0f%%, run with -update to accept the new sizes\n", *maxGrowth)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,3 @@
+module startupbench
+
+go 1.21.3
@@ -0,0 +1,182 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"log"
+	"math"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// errNoOutput means the binary exited or timed out without writing anything
+// to stdout, which happens with servers and programs waiting for input.
+var errNoOutput = errors.New("no output on stdout")
+
+// stats summarises the startup times of one binary.
+type stats struct {
+	Mean   time.Duration
+	StdDev time.Duration
+	P95    time.Duration
+}
+
+// modules returns the path, relative to root, of every module under root.
+func modules(root string) ([]string, error) {
+	var out []string
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() && d.Name() == ".git" {
+			return filepath.SkipDir
+		}
+		if d.IsDir() || d.Name() != "go.mod" {
+			return nil
+		}
+		rel, err := filepath.Rel(root, filepath.Dir(path))
+		if err != nil {
+			return err
+		}
+		out = append(out, rel)
+		return nil
+	})
+	sort.Strings(out)
+	return out, err
+}
+
+// build compiles the module in dir into out and returns the paths of the
+// binaries.
+func build(dir, out string) ([]string, error) {
+	var stderr bytes.Buffer
+	cmd := exec.Command("go", "build", "-o", out+string(filepath.Separator), "./...")
+	cmd.Dir = dir
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("%w: %s", err, bytes.TrimSpace(stderr.Bytes()))
+	}
+	entries, err := os.ReadDir(out)
+	if err != nil {
+		return nil, err
+	}
+	var bins []string
+	for _, e := range entries {
+		bins = append(bins, filepath.Join(out, e.Name()))
+	}
+	return bins, nil
+}
+
+// timeToFirstByte starts the binary and returns how long it took to write
+// its first byte to stdout. The process is killed once it has been timed.
+func timeToFirstByte(bin string, timeout time.Duration) (time.Duration, error) {
+	cmd := exec.Command(bin)
+	cmd.Dir = filepath.Dir(bin)
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return 0, err
+	}
+	start := time.Now()
+	if err := cmd.Start(); err != nil {
+		return 0, err
+	}
+	defer cmd.Wait()
+	defer cmd.Process.Kill()
+
+	first := make(chan error, 1)
+	go func() {
+		_, err := stdout.Read(make([]byte, 1))
+		first <- err
+	}()
+	select {
+	case err := <-first:
+		elapsed := time.Since(start)
+		if errors.Is(err, io.EOF) {
+			return 0, errNoOutput
+		}
+		return elapsed, err
+	case <-time.After(timeout):
+		return 0, errNoOutput
+	}
+}
+
+func summarise(times []time.Duration) stats {
+	sort.Slice(times, func(i, j int) bool { return times[i] < times[j] })
+	var sum float64
+	for _, t := range times {
+		sum += float64(t)
+	}
+	mean := sum / float64(len(times))
+	var sq float64
+	for _, t := range times {
+		sq += (float64(t) - mean) * (float64(t) - mean)
+	}
+	// Nearest rank percentile
+	p95 := times[int(math.Ceil(0.95*float64(len(times))))-1]
+	return stats{
+		Mean:   time.Duration(mean),
+		StdDev: time.Duration(math.Sqrt(sq / float64(len(times)))),
+		P95:    p95,
+	}
+}
+
+func main() {
+	root := flag.String("root", ".", "directory to search for modules")
+	runs := flag.Int("runs", 100, "number of times to start each binary")
+	maxP95 := flag.Duration("max-p95", 100*time.Millisecond, "slowest allowed 95th percentile startup time")
+	timeout := flag.Duration("timeout", 2*time.Second, "how long to wait for a binary's first output")
+	flag.Parse()
+	if *runs < 1 {
+		log.Fatal("-runs must be at least 1")
+	}
+
+	mods, err := modules(*root)
+	if err != nil {
+		log.Fatal(err)
+	}
+	out, err := os.MkdirTemp("", "startupbench")
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer os.RemoveAll(out)
+
+	failed := false
+	for _, m := range mods {
+		bins, err := build(filepath.Join(*root, m), filepath.Join(out, m))
+		if err != nil {
+			fmt.Printf("%s: skipped, %v\n", m, err)
+			continue
+		}
+		for _, bin := range bins {
+			name := filepath.ToSlash(filepath.Join(m, filepath.Base(bin)))
+			times := make([]time.Duration, 0, *runs)
+			for i := 0; i < *runs; i++ {
+				t, err := timeToFirstByte(bin, *timeout)
+				if err != nil {
+					break
+				}
+				times = append(times, t)
+			}
+			if len(times) < *runs {
+				fmt.Printf("%s: skipped, %v\n", name, errNoOutput)
+				continue
+			}
+			s := summarise(times)
+			status := "ok"
+			if s.P95 > *maxP95 {
+				status = "SLOW"
+				failed = true
+			}
+			fmt.Printf("%s: %v ± %v, p95 %v %s\n", name, s.Mean.Round(time.Microsecond), s.StdDev.Round(time.Microsecond), s.P95.Round(time.Microsecond), status)
+		}
+	}
+	if failed {
+		os.RemoveAll(out)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,3 @@
+module strace
+
+go 1.21.3
@@ -0,0 +1,135 @@
+//go:build linux
+
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// syscallCount is one row of the strace -c summary.
+type syscallCount struct {
+	Name  string
+	Calls int
+}
+
+// parseSummary reads the table strace -c prints when the traced program
+// exits. The rows sit between two dashed lines, and the last row is the
+// total, which comes after the second one. Anything else in the output,
+// such as the program's own stderr, is skipped.
+func parseSummary(r io.Reader) ([]syscallCount, error) {
+	var out []syscallCount
+	inTable := false
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if strings.HasPrefix(line, "------") {
+			if inTable {
+				break
+			}
+			inTable = true
+			continue
+		}
+		if !inTable {
+			continue
+		}
+		// % time, seconds, usecs/call, calls, errors (may be blank), syscall
+		fields := strings.Fields(line)
+		if len(fields) < 5 {
+			return nil, fmt.Errorf("unexpected summary line: %q", line)
+		}
+		calls, err := strconv.Atoi(fields[3])
+		if err != nil {
+			return nil, fmt.Errorf("unexpected summary line: %q", line)
+		}
+		out = append(out, syscallCount{Name: fields[len(fields)-1], Calls: calls})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if !inTable {
+		return nil, fmt.Errorf("no syscall summary in strace output")
+	}
+	return out, nil
+}
+
+// binaryFor returns the binary to trace. A directory is treated as a module
+// and built into out first.
+func binaryFor(path, out string) (string, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return "", err
+	}
+	if !info.IsDir() {
+		return path, nil
+	}
+	bin := filepath.Join(out, filepath.Base(path))
+	var stderr bytes.Buffer
+	cmd := exec.Command("go", "build", "-o", bin, ".")
+	cmd.Dir = path
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("%w: %s", err, bytes.TrimSpace(stderr.Bytes()))
+	}
+	return bin, nil
+}
+
+func main() {
+	top := flag.Int("top", 5, "number of syscalls to report")
+	flag.Usage = func() {
+		fmt.Fprintln(flag.CommandLine.Output(), "usage: strace [-top n] binary-or-module-dir [args...]")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() < 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+	if _, err := exec.LookPath("strace"); err != nil {
+		log.Fatal("strace isn't installed: ", err)
+	}
+
+	out, err := os.MkdirTemp("", "strace")
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer os.RemoveAll(out)
+	bin, err := binaryFor(flag.Arg(0), out)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	var stderr bytes.Buffer
+	args := append([]string{"-c", "-f", bin}, flag.Args()[1:]...)
+	cmd := exec.Command("strace", args...)
+	cmd.Stdout = io.Discard
+	cmd.Stderr = &stderr
+	// The program failing doesn't stop strace from printing the summary, so
+	// the error is only reported if there's nothing to parse
+	runErr := cmd.Run()
+	counts, err := parseSummary(&stderr)
+	if err != nil {
+		if runErr != nil {
+			log.Fatal(runErr)
+		}
+		log.Fatal(err)
+	}
+
+	sort.SliceStable(counts, func(i, j int) bool { return counts[i].Calls > counts[j].Calls })
+	for i, c := range counts {
+		if i == *top {
+			break
+		}
+		fmt.Printf("%d. %s %d\n", i+1, c.Name, c.Calls)
+	}
+}
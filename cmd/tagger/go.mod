@@ -0,0 +1,3 @@
+module tagger
+
+go 1.21.3
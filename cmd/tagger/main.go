@@ -0,0 +1,166 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// features counts the constructs that make an exercise harder to follow.
+type features struct {
+	Goroutines int
+	Interfaces int
+	Recursion  int
+	ChannelOps int
+	ErrorPaths int
+}
+
+// points weighs each feature, concurrency and recursion are harder to
+// reason about than checking an error.
+func (f features) points() int {
+	return f.Goroutines*3 + f.ChannelOps*2 + f.Interfaces*2 + f.Recursion*2 + f.ErrorPaths
+}
+
+// difficulty turns points into a score from 1 to 5.
+func (f features) difficulty() int {
+	switch p := f.points(); {
+	case p == 0:
+		return 1
+	case p < 5:
+		return 2
+	case p < 10:
+		return 3
+	case p < 20:
+		return 4
+	default:
+		return 5
+	}
+}
+
+func isErrCheck(n ast.Expr) bool {
+	bin, ok := n.(*ast.BinaryExpr)
+	if !ok || bin.Op != token.NEQ {
+		return false
+	}
+	x, xOk := bin.X.(*ast.Ident)
+	y, yOk := bin.Y.(*ast.Ident)
+	return xOk && yOk && strings.HasSuffix(strings.ToLower(x.Name), "err") && y.Name == "nil"
+}
+
+// isRecursive reports whether fn calls itself. Methods are matched on the
+// method name alone, which is close enough for these small programs.
+func isRecursive(fn *ast.FuncDecl) bool {
+	if fn.Body == nil {
+		return false
+	}
+	found := false
+	ast.Inspect(fn.Body, func(n ast.Node) bool {
+		call, ok := n.(*ast.CallExpr)
+		if !ok {
+			return !found
+		}
+		switch f := call.Fun.(type) {
+		case *ast.Ident:
+			found = found || (fn.Recv == nil && f.Name == fn.Name.Name)
+		case *ast.SelectorExpr:
+			found = found || (fn.Recv != nil && f.Sel.Name == fn.Name.Name)
+		}
+		return !found
+	})
+	return found
+}
+
+func countFeatures(f *ast.File, into *features) {
+	for _, decl := range f.Decls {
+		if fn, ok := decl.(*ast.FuncDecl); ok && isRecursive(fn) {
+			into.Recursion++
+		}
+	}
+	ast.Inspect(f, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.GoStmt:
+			into.Goroutines++
+		case *ast.InterfaceType:
+			into.Interfaces++
+		case *ast.SendStmt:
+			into.ChannelOps++
+		case *ast.UnaryExpr:
+			if n.Op == token.ARROW {
+				into.ChannelOps++
+			}
+		case *ast.IfStmt:
+			if isErrCheck(n.Cond) {
+				into.ErrorPaths++
+			}
+		}
+		return true
+	})
+}
+
+// tagExercises scores every module under root, keyed by its path relative
+// to root.
+func tagExercises(root string) (map[string]int, error) {
+	tags := map[string]int{}
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() && d.Name() == ".git" {
+			return filepath.SkipDir
+		}
+		if d.IsDir() || d.Name() != "go.mod" {
+			return nil
+		}
+		dir := filepath.Dir(path)
+		files, err := filepath.Glob(filepath.Join(dir, "*.go"))
+		if err != nil {
+			return err
+		}
+		var feat features
+		fset := token.NewFileSet()
+		for _, name := range files {
+			if strings.HasSuffix(name, "_test.go") {
+				continue
+			}
+			f, err := parser.ParseFile(fset, name, nil, 0)
+			if err != nil {
+				return err
+			}
+			countFeatures(f, &feat)
+		}
+		rel, err := filepath.Rel(root, dir)
+		if err != nil {
+			return err
+		}
+		tags[filepath.ToSlash(rel)] = feat.difficulty()
+		return nil
+	})
+	return tags, err
+}
+
+func main() {
+	out := flag.String("o", "tags.json", "file to write the scores to")
+	flag.Parse()
+	root := "."
+	if flag.NArg() > 0 {
+		root = flag.Arg(0)
+	}
+	tags, err := tagExercises(root)
+	if err != nil {
+		log.Fatal(err)
+	}
+	data, err := json.MarshalIndent(tags, "", "  ")
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := os.WriteFile(*out, append(data, '\n'), 0644); err != nil {
+		log.Fatal(err)
+	}
+}
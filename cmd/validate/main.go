@@ -0,0 +1,126 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"io/fs"
+	"log"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+)
+
+// result is the outcome of checking a single exercise.
+type result struct {
+	Exercise string
+	Status   string
+	Detail   string
+}
+
+// runExercise builds and runs the module in dir, returning what it wrote to
+// stdout.
+func runExercise(dir string) ([]byte, error) {
+	var stdout, stderr bytes.Buffer
+	cmd := exec.Command("go", "run", ".")
+	cmd.Dir = dir
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("%w: %s", err, bytes.TrimSpace(stderr.Bytes()))
+	}
+	return stdout.Bytes(), nil
+}
+
+// exercises returns the path, relative to root, of every module under root.
+func exercises(root string) ([]string, error) {
+	var out []string
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || d.Name() != "go.mod" {
+			return nil
+		}
+		rel, err := filepath.Rel(root, filepath.Dir(path))
+		if err != nil {
+			return err
+		}
+		out = append(out, rel)
+		return nil
+	})
+	sort.Strings(out)
+	return out, err
+}
+
+// check runs one exercise and compares its output with the expected output
+// in testdata. With update set, the expected output is overwritten instead.
+func check(root, testdata, exercise string, update bool) result {
+	r := result{Exercise: filepath.ToSlash(exercise)}
+	expectedFile := filepath.Join(testdata, exercise, "expected_output.txt")
+	expected, err := os.ReadFile(expectedFile)
+	if errors.Is(err, fs.ErrNotExist) && !update {
+		r.Status = "SKIP"
+		r.Detail = "no expected output"
+		return r
+	}
+	if err != nil && !update {
+		r.Status, r.Detail = "ERROR", err.Error()
+		return r
+	}
+
+	got, err := runExercise(filepath.Join(root, exercise))
+	if err != nil {
+		r.Status, r.Detail = "FAIL", err.Error()
+		return r
+	}
+
+	if update {
+		if err := os.MkdirAll(filepath.Dir(expectedFile), 0755); err != nil {
+			r.Status, r.Detail = "ERROR", err.Error()
+			return r
+		}
+		if err := os.WriteFile(expectedFile, got, 0644); err != nil {
+			r.Status, r.Detail = "ERROR", err.Error()
+			return r
+		}
+		r.Status = "UPDATED"
+		return r
+	}
+	if !bytes.Equal(got, expected) {
+		r.Status = "FAIL"
+		r.Detail = fmt.Sprintf("output differs from %s", expectedFile)
+		return r
+	}
+	r.Status = "PASS"
+	return r
+}
+
+func main() {
+	root := flag.String("root", "learning_go/exercises", "directory containing the exercises")
+	testdata := flag.String("testdata", "testdata", "directory containing <exercise>/expected_output.txt files")
+	update := flag.Bool("update-expected", false, "overwrite the expected output with each exercise's current output")
+	flag.Parse()
+
+	list, err := exercises(*root)
+	if err != nil {
+		log.Fatal(err)
+	}
+	failed := false
+	for _, ex := range list {
+		r := check(*root, *testdata, ex, *update)
+		if r.Detail != "" {
+			fmt.Printf("%-8s %s: %s\n", r.Status, r.Exercise, r.Detail)
+		} else {
+			fmt.Printf("%-8s %s\n", r.Status, r.Exercise)
+		}
+		if r.Status == "FAIL" || r.Status == "ERROR" {
+			failed = true
+		}
+	}
+	if failed {
+		os.Exit(1)
+	}
+}
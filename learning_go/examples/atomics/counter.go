@@ -0,0 +1,92 @@
+package main
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Counter is an int64 that's safe to update from many goroutines at once.
+// The zero value is a counter at zero.
+type Counter struct {
+	v atomic.Int64
+}
+
+// Inc adds one and returns the new value.
+func (c *Counter) Inc() int64 { return c.v.Add(1) }
+
+// Dec subtracts one and returns the new value.
+func (c *Counter) Dec() int64 { return c.v.Add(-1) }
+
+// Add adds n and returns the new value.
+func (c *Counter) Add(n int64) int64 { return c.v.Add(n) }
+
+// Load returns the current value.
+func (c *Counter) Load() int64 { return c.v.Load() }
+
+// Reset sets the counter back to zero and returns the value it had.
+func (c *Counter) Reset() int64 { return c.v.Swap(0) }
+
+// CompareAndSwap sets the counter to new only if it's currently old, and
+// reports whether it did.
+func (c *Counter) CompareAndSwap(old, new int64) bool {
+	return c.v.CompareAndSwap(old, new)
+}
+
+// rateSlots is how many slots a RateCounter's window is split into. Events
+// move out of the window a slot at a time, so more slots give a smoother
+// rate.
+const rateSlots = 10
+
+// RateCounter counts events over a sliding window of time and reports them
+// as a rate per second. Use NewRateCounter to make one.
+type RateCounter struct {
+	mu     sync.Mutex
+	window time.Duration
+	width  time.Duration
+	counts [rateSlots]int64
+	// ids[i] says which slot of time counts[i] holds, so slots left over
+	// from an earlier trip round the ring can be told apart and cleared
+	ids [rateSlots]int64
+}
+
+// NewRateCounter returns a RateCounter that averages over window. Events
+// older than window stop counting a tenth of a window at a time.
+func NewRateCounter(window time.Duration) *RateCounter {
+	width := max(window/rateSlots, 1)
+	return &RateCounter{window: width * rateSlots, width: width}
+}
+
+// Inc records one event.
+func (r *RateCounter) Inc() { r.Add(1) }
+
+// Add records n events.
+func (r *RateCounter) Add(n int64) {
+	id := time.Now().UnixNano() / int64(r.width)
+	i := id % rateSlots
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.ids[i] != id {
+		r.ids[i], r.counts[i] = id, 0
+	}
+	r.counts[i] += n
+}
+
+// Count returns how many events were recorded in the last window.
+func (r *RateCounter) Count() int64 {
+	id := time.Now().UnixNano() / int64(r.width)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var total int64
+	for i, slot := range r.ids {
+		if id-slot < rateSlots {
+			total += r.counts[i]
+		}
+	}
+	return total
+}
+
+// Rate returns the number of events per second over the last window.
+func (r *RateCounter) Rate() float64 {
+	return float64(r.Count()) / r.window.Seconds()
+}
@@ -0,0 +1,132 @@
+package main
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestCounterConcurrent(t *testing.T) {
+	var c Counter
+	var wg sync.WaitGroup
+	for i := 0; i < 100; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 1000; j++ {
+				c.Inc()
+			}
+		}()
+	}
+	wg.Wait()
+	if got := c.Load(); got != 100000 {
+		t.Fatalf("after 100 x 1000 Inc, Load() = %d, want 100000", got)
+	}
+
+	// Every Inc and Dec pair cancels out however they interleave
+	for i := 0; i < 100; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 1000; j++ {
+				c.Inc()
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 1000; j++ {
+				c.Dec()
+			}
+		}()
+	}
+	wg.Wait()
+	if got := c.Load(); got != 100000 {
+		t.Errorf("after balanced Inc and Dec, Load() = %d, want 100000", got)
+	}
+}
+
+func TestCounter(t *testing.T) {
+	var c Counter
+	if got := c.Inc(); got != 1 {
+		t.Errorf("Inc() = %d, want 1", got)
+	}
+	if got := c.Add(10); got != 11 {
+		t.Errorf("Add(10) = %d, want 11", got)
+	}
+	if got := c.Dec(); got != 10 {
+		t.Errorf("Dec() = %d, want 10", got)
+	}
+	if c.CompareAndSwap(5, 6) {
+		t.Error("CompareAndSwap(5, 6) swapped a counter at 10")
+	}
+	if !c.CompareAndSwap(10, 20) || c.Load() != 20 {
+		t.Errorf("CompareAndSwap(10, 20) didn't swap, Load() = %d", c.Load())
+	}
+	if got := c.Reset(); got != 20 || c.Load() != 0 {
+		t.Errorf("Reset() = %d then Load() = %d, want 20 then 0", got, c.Load())
+	}
+}
+
+func TestCounterCompareAndSwapConcurrent(t *testing.T) {
+	// Incrementing with a CompareAndSwap loop loses no updates either
+	var c Counter
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 1000; j++ {
+				for {
+					old := c.Load()
+					if c.CompareAndSwap(old, old+1) {
+						break
+					}
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	if got := c.Load(); got != 50000 {
+		t.Errorf("Load() = %d, want 50000", got)
+	}
+}
+
+func TestRateCounter(t *testing.T) {
+	r := NewRateCounter(time.Hour)
+	var wg sync.WaitGroup
+	for i := 0; i < 100; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				r.Inc()
+			}
+		}()
+	}
+	wg.Wait()
+	r.Add(800)
+	if got := r.Count(); got != 10800 {
+		t.Errorf("Count() = %d, want 10800", got)
+	}
+	if got := r.Rate(); got != 3 {
+		t.Errorf("Rate() = %v, want 3 per second", got)
+	}
+}
+
+func TestRateCounterWindow(t *testing.T) {
+	r := NewRateCounter(50 * time.Millisecond)
+	r.Add(5)
+	if got := r.Count(); got != 5 {
+		t.Fatalf("Count() = %d, want 5", got)
+	}
+	// Long enough for every slot to have moved out of the window and then
+	// round the ring again, so stale slots have to be told apart
+	time.Sleep(120 * time.Millisecond)
+	if got := r.Count(); got != 0 {
+		t.Errorf("Count() after the window = %d, want 0", got)
+	}
+	r.Inc()
+	if got := r.Count(); got != 1 {
+		t.Errorf("Count() = %d, want 1", got)
+	}
+}
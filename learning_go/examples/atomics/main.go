@@ -0,0 +1,76 @@
+package main
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// match is a result waiting to be recorded, like the ones passed to
+// League.MatchResult in exercise 07/ex3.
+type match struct {
+	team1, team2   string
+	score1, score2 int
+}
+
+func main() {
+	var c Counter
+	var wg sync.WaitGroup
+	for i := 0; i < 100; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 1000; j++ {
+				c.Inc()
+			}
+		}()
+	}
+	wg.Wait()
+	fmt.Println(c.Load())
+	fmt.Println(c.Reset(), c.Load())
+	fmt.Println(c.CompareAndSwap(0, 5), c.CompareAndSwap(0, 6), c.Load())
+
+	// Several workers record results into a shared wins table. The table
+	// needs a mutex, but the progress counters don't.
+	teams := []string{"USA", "Canada", "Serbia", "Germany"}
+	results := make(chan match)
+	var mu sync.Mutex
+	wins := map[string]int{}
+	var processed, draws Counter
+	rate := NewRateCounter(time.Second)
+	for w := 0; w < 4; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for m := range results {
+				switch {
+				case m.score1 == m.score2:
+					draws.Inc()
+				case m.score1 > m.score2:
+					mu.Lock()
+					wins[m.team1]++
+					mu.Unlock()
+				default:
+					mu.Lock()
+					wins[m.team2]++
+					mu.Unlock()
+				}
+				processed.Inc()
+				rate.Inc()
+			}
+		}()
+	}
+	for i := 0; i < 10_000; i++ {
+		results <- match{
+			team1:  teams[i%4],
+			team2:  teams[(i+1)%4],
+			score1: i % 7,
+			score2: i % 5,
+		}
+	}
+	close(results)
+	wg.Wait()
+	fmt.Println(processed.Load(), draws.Load(), wins)
+	fmt.Println(rate.Count())
+	fmt.Println(rate.Rate() > 0)
+}
@@ -0,0 +1,19 @@
+package main
+
+// useAVX2 is set when both the CPU and the OS support 256 bit AVX2
+// registers.
+var useAVX2 = hasAVX2()
+
+// hasAVX2 is implemented in bstsearch_amd64.s.
+func hasAVX2() bool
+
+// containsAVX2 is implemented in bstsearch_amd64.s. It compares eight values
+// at a time with VPCMPEQD and checks any left over one by one.
+func containsAVX2(s []int32, target int32) bool
+
+func containsBlock(s []int32, target int32) bool {
+	if useAVX2 {
+		return containsAVX2(s, target)
+	}
+	return containsLinear(s, target)
+}
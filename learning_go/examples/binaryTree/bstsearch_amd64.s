@@ -0,0 +1,73 @@
+#include "textflag.h"
+
+// func hasAVX2() bool
+TEXT ·hasAVX2(SB), NOSPLIT, $0-1
+	// CPUID leaf 1: ECX bit 27 is OSXSAVE and bit 28 is AVX
+	MOVL $1, AX
+	XORL CX, CX
+	CPUID
+	MOVL CX, DX
+	ANDL $0x18000000, DX
+	CMPL DX, $0x18000000
+	JNE  no
+
+	// XGETBV: the OS must save the XMM and YMM registers (bits 1 and 2)
+	XORL CX, CX
+	XGETBV
+	ANDL $6, AX
+	CMPL AX, $6
+	JNE  no
+
+	// CPUID leaf 7: EBX bit 5 is AVX2
+	MOVL $7, AX
+	XORL CX, CX
+	CPUID
+	BTL  $5, BX
+	JCC  no
+	MOVB $1, ret+0(FP)
+	RET
+
+no:
+	MOVB $0, ret+0(FP)
+	RET
+
+// func containsAVX2(s []int32, target int32) bool
+TEXT ·containsAVX2(SB), NOSPLIT, $0-33
+	MOVQ s_base+0(FP), SI
+	MOVQ s_len+8(FP), CX
+	MOVL target+24(FP), AX
+
+	// Put target in all eight lanes of Y0
+	MOVQ         AX, X0
+	VPBROADCASTD X0, Y0
+
+loop8:
+	CMPQ     CX, $8
+	JB       tail
+	VPCMPEQD (SI), Y0, Y1
+	VPTEST   Y1, Y1
+	JNZ      found
+	ADDQ     $32, SI
+	SUBQ     $8, CX
+	JMP      loop8
+
+tail:
+	TESTQ CX, CX
+	JZ    notfound
+
+tailloop:
+	CMPL (SI), AX
+	JEQ  found
+	ADDQ $4, SI
+	DECQ CX
+	JNZ  tailloop
+
+notfound:
+	VZEROUPPER
+	MOVB $0, ret+32(FP)
+	RET
+
+found:
+	VZEROUPPER
+	MOVB $1, ret+32(FP)
+	RET
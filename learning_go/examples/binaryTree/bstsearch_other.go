@@ -0,0 +1,10 @@
+//go:build !amd64
+
+package main
+
+// useAVX2 is always false, the assembly is only written for amd64.
+const useAVX2 = false
+
+func containsBlock(s []int32, target int32) bool {
+	return containsLinear(s, target)
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"math"
+	"math/rand"
+	"sort"
+	"testing"
+)
+
+func TestBinarySearchSIMD(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	// Lengths around the block size and the eight value AVX2 width, so
+	// every path through the search and the leftover scan is covered
+	for _, n := range []int{0, 1, 7, 8, 9, 63, 64, 65, 129, 1000, 4097} {
+		s := make([]int32, n)
+		seen := map[int32]bool{}
+		for i := range s {
+			s[i] = int32(r.Intn(4*n+1)) - int32(2*n)
+		}
+		sort.Slice(s, func(i, j int) bool { return s[i] < s[j] })
+		for _, v := range s {
+			seen[v] = true
+		}
+		for target := int32(-2*n - 2); target <= int32(2*n+2); target++ {
+			if got := BinarySearchSIMD(s, target); got != seen[target] {
+				t.Fatalf("n = %d: BinarySearchSIMD(%d) = %t, want %t", n, target, got, seen[target])
+			}
+		}
+	}
+
+	extremes := []int32{math.MinInt32, -1, 0, 1, math.MaxInt32}
+	for _, target := range extremes {
+		if !BinarySearchSIMD(extremes, target) {
+			t.Errorf("BinarySearchSIMD(%d) = false in %v", target, extremes)
+		}
+	}
+}
+
+// TestContainsBlock checks the AVX2 and plain Go scans agree, with the
+// target at every position of the block.
+func TestContainsBlock(t *testing.T) {
+	if !useAVX2 {
+		t.Log("no AVX2, only the plain Go scan is tested")
+	}
+	for n := 0; n <= simdBlock+1; n++ {
+		s := make([]int32, n)
+		for i := range s {
+			s[i] = int32(i * 3)
+		}
+		for target := int32(-1); target <= int32(3*n); target++ {
+			want := target >= 0 && target%3 == 0 && target < int32(3*n)
+			if got := containsLinear(s, target); got != want {
+				t.Fatalf("containsLinear(%d values, %d) = %t, want %t", n, target, got, want)
+			}
+			if got := containsBlock(s, target); got != want {
+				t.Fatalf("containsBlock(%d values, %d) = %t, want %t", n, target, got, want)
+			}
+		}
+	}
+}
+
+const benchSearchLen = 1_000_000
+
+// The search benchmarks look up a million even numbers, half the targets
+// are odd and so aren't there.
+
+func BenchmarkBinarySearchSIMD(b *testing.B) {
+	s := make([]int32, benchSearchLen)
+	for i := range s {
+		s[i] = int32(2 * i)
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		BinarySearchSIMD(s, int32(i%(2*benchSearchLen)))
+	}
+}
+
+func BenchmarkSearchInts(b *testing.B) {
+	s := make([]int, benchSearchLen)
+	for i := range s {
+		s[i] = 2 * i
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		target := i % (2 * benchSearchLen)
+		j := sort.SearchInts(s, target)
+		_ = j < len(s) && s[j] == target
+	}
+}
@@ -7,14 +7,29 @@ type IntTree struct {
 	val         int
 }
 
-func (it *IntTree) Insert(val int) *IntTree {
+func (it *IntTree) Insert(val int, opts ...TreeOption) *IntTree {
+	var p *NodePool
+	for _, opt := range opts {
+		if opt.pool != nil {
+			p = opt.pool
+		}
+	}
+	return it.insert(val, p)
+}
+
+func (it *IntTree) insert(val int, p *NodePool) *IntTree {
 	if it == nil {
-		return &IntTree{val: val}
+		if p == nil {
+			return &IntTree{val: val}
+		}
+		n := p.Get()
+		n.val = val
+		return n
 	}
 	if val < it.val {
-		it.left = it.left.Insert(val)
+		it.left = it.left.insert(val, p)
 	} else if val > it.val {
-		it.right = it.right.Insert(val)
+		it.right = it.right.insert(val, p)
 	}
 	return it
 }
@@ -32,6 +47,44 @@ func (it *IntTree) Contains(val int) bool {
 	}
 }
 
+// LevelOrder returns the values in the tree breadth first, level by level
+// from the root.
+func (it *IntTree) LevelOrder() []int {
+	if it == nil {
+		return nil
+	}
+	var out []int
+	queue := []*IntTree{it}
+	for len(queue) > 0 {
+		node := queue[0]
+		queue = queue[1:]
+		out = append(out, node.val)
+		if node.left != nil {
+			queue = append(queue, node.left)
+		}
+		if node.right != nil {
+			queue = append(queue, node.right)
+		}
+	}
+	return out
+}
+
+// InOrder returns the values in the tree in ascending order.
+func (it *IntTree) InOrder() []int {
+	var out []int
+	var walk func(*IntTree)
+	walk = func(n *IntTree) {
+		if n == nil {
+			return
+		}
+		walk(n.left)
+		out = append(out, n.val)
+		walk(n.right)
+	}
+	walk(it)
+	return out
+}
+
 func main() {
 	it := &IntTree{}
 	it = it.Insert(5)
@@ -43,4 +96,21 @@ func main() {
 	fmt.Println(it.Contains(5))  // true
 	fmt.Println(it.Contains(10)) // true
 	fmt.Println(it.Contains(12)) // false
+	fmt.Println(it.LevelOrder()) // [0 5 3 10 2]
+	fmt.Println(it.InOrder())    // [0 2 3 5 10]
+
+	var sorted []int32
+	for _, v := range it.InOrder() {
+		sorted = append(sorted, int32(v))
+	}
+	fmt.Println(BinarySearchSIMD(sorted, 3))  // true
+	fmt.Println(BinarySearchSIMD(sorted, 12)) // false
+
+	var p NodePool
+	var pooled *IntTree
+	for _, v := range []int{5, 3, 10, 2} {
+		pooled = pooled.Insert(v, WithPool(&p))
+	}
+	fmt.Println(pooled.InOrder()) // [2 3 5 10]
+	p.PutTree(pooled)
 }
@@ -0,0 +1,46 @@
+package main
+
+import "sync"
+
+// NodePool keeps IntTree nodes that are no longer needed so Insert can reuse
+// them instead of allocating. The zero value is ready to use.
+type NodePool struct {
+	sync.Pool
+}
+
+// Get returns a cleared node from the pool, or a new one if it's empty.
+func (p *NodePool) Get() *IntTree {
+	if n, ok := p.Pool.Get().(*IntTree); ok {
+		return n
+	}
+	return &IntTree{}
+}
+
+// Put clears n and returns it to the pool. n must not be used afterwards.
+func (p *NodePool) Put(n *IntTree) {
+	*n = IntTree{}
+	p.Pool.Put(n)
+}
+
+// PutTree returns every node of the tree to the pool.
+func (p *NodePool) PutTree(it *IntTree) {
+	if it == nil {
+		return
+	}
+	p.PutTree(it.left)
+	p.PutTree(it.right)
+	p.Put(it)
+}
+
+// TreeOption configures Insert. Unlike the func options used elsewhere it's
+// a plain struct, because Insert is called once per value and calling a func
+// option would make the options escape to the heap on every call, costing
+// more than the pool saves.
+type TreeOption struct {
+	pool *NodePool
+}
+
+// WithPool makes Insert take new nodes from p.
+func WithPool(p *NodePool) TreeOption {
+	return TreeOption{pool: p}
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"math/rand"
+	"slices"
+	"testing"
+)
+
+func TestNodePool(t *testing.T) {
+	var p NodePool
+	n := p.Get()
+	if *n != (IntTree{}) {
+		t.Fatalf("Get returned %+v, want a cleared node", *n)
+	}
+	n.val = 7
+	n.left = &IntTree{}
+	p.Put(n)
+	if *n != (IntTree{}) {
+		t.Errorf("Put left the node as %+v, want it cleared", *n)
+	}
+}
+
+// TestInsertWithPool builds the same trees with and without a pool, and
+// with nodes handed back from an earlier tree.
+func TestInsertWithPool(t *testing.T) {
+	vals := rand.New(rand.NewSource(1)).Perm(1000)
+	build := func(opts ...TreeOption) *IntTree {
+		var it *IntTree
+		for _, v := range vals {
+			it = it.Insert(v, opts...)
+		}
+		return it
+	}
+	want := build()
+	var p NodePool
+	for round := 0; round < 3; round++ {
+		got := build(WithPool(&p))
+		if !slices.Equal(got.LevelOrder(), want.LevelOrder()) {
+			t.Fatalf("round %d: pooled tree has a different shape", round)
+		}
+		p.PutTree(got)
+	}
+	// PutTree on an empty tree is fine
+	p.PutTree(nil)
+}
+
+// The insert benchmarks build a tree of a million values. The pooled one
+// hands its nodes back to the pool for the next run, so what it still
+// allocates is mostly sync.Pool's own storage for a million spare nodes.
+
+var benchTreeVals = rand.New(rand.NewSource(1)).Perm(1_000_000)
+
+func buildTree(opts ...TreeOption) *IntTree {
+	var it *IntTree
+	for _, v := range benchTreeVals {
+		it = it.Insert(v, opts...)
+	}
+	return it
+}
+
+func BenchmarkInsert(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		buildTree()
+	}
+}
+
+func BenchmarkInsertPool(b *testing.B) {
+	var p NodePool
+	withPool := WithPool(&p)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		p.PutTree(buildTree(withPool))
+	}
+}
@@ -0,0 +1,98 @@
+package main
+
+import "fmt"
+
+type node[K comparable, V any] struct {
+	key        K
+	val        V
+	prev, next *node[K, V]
+}
+
+// LRUCache holds up to a fixed number of entries and drops the least
+// recently used one to make room for a new one. Entries are kept in a doubly
+// linked list from most to least recently used, and the map points straight
+// at each entry's node, so Get and Put don't have to search.
+type LRUCache[K comparable, V any] struct {
+	capacity int
+	items    map[K]*node[K, V]
+	// head and tail are sentinels, the entries are the nodes between them
+	head, tail *node[K, V]
+}
+
+// NewLRUCache returns an empty cache that holds up to capacity entries. It
+// returns an error if capacity is less than 1.
+func NewLRUCache[K comparable, V any](capacity int) (*LRUCache[K, V], error) {
+	if capacity < 1 {
+		return nil, fmt.Errorf("invalid capacity %d: must be at least 1", capacity)
+	}
+	c := &LRUCache[K, V]{
+		capacity: capacity,
+		items:    make(map[K]*node[K, V], capacity),
+		head:     &node[K, V]{},
+		tail:     &node[K, V]{},
+	}
+	c.head.next = c.tail
+	c.tail.prev = c.head
+	return c, nil
+}
+
+// Get returns the value stored for key and marks it as the most recently
+// used entry.
+func (c *LRUCache[K, V]) Get(key K) (V, bool) {
+	n, ok := c.items[key]
+	if !ok {
+		var zero V
+		return zero, false
+	}
+	c.unlink(n)
+	c.pushFront(n)
+	return n.val, true
+}
+
+// Put stores val for key as the most recently used entry, evicting the least
+// recently used entry if the cache is full.
+func (c *LRUCache[K, V]) Put(key K, val V) {
+	if n, ok := c.items[key]; ok {
+		n.val = val
+		c.unlink(n)
+		c.pushFront(n)
+		return
+	}
+	if len(c.items) == c.capacity {
+		oldest := c.tail.prev
+		c.unlink(oldest)
+		delete(c.items, oldest.key)
+	}
+	n := &node[K, V]{key: key, val: val}
+	c.items[key] = n
+	c.pushFront(n)
+}
+
+func (c *LRUCache[K, V]) Len() int {
+	return len(c.items)
+}
+
+func (c *LRUCache[K, V]) Cap() int {
+	return c.capacity
+}
+
+// Keys returns the keys from most to least recently used.
+func (c *LRUCache[K, V]) Keys() []K {
+	keys := make([]K, 0, len(c.items))
+	for n := c.head.next; n != c.tail; n = n.next {
+		keys = append(keys, n.key)
+	}
+	return keys
+}
+
+func (c *LRUCache[K, V]) unlink(n *node[K, V]) {
+	n.prev.next = n.next
+	n.next.prev = n.prev
+}
+
+func (c *LRUCache[K, V]) pushFront(n *node[K, V]) {
+	n.prev = c.head
+	n.next = c.head.next
+	c.head.next.prev = n
+	c.head.next = n
+}
@@ -0,0 +1,115 @@
+package main
+
+import (
+	"math/rand"
+	"slices"
+	"testing"
+)
+
+func newCache(t testing.TB, capacity int) *LRUCache[string, int] {
+	t.Helper()
+	c, err := NewLRUCache[string, int](capacity)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c
+}
+
+func TestLRUEviction(t *testing.T) {
+	c := newCache(t, 3)
+	c.Put("a", 1)
+	c.Put("b", 2)
+	c.Put("c", 3)
+	if c.Len() != 3 || c.Cap() != 3 {
+		t.Fatalf("Len, Cap = %d, %d, want 3, 3", c.Len(), c.Cap())
+	}
+
+	// Getting the oldest entry makes b the least recently used
+	if v, ok := c.Get("a"); !ok || v != 1 {
+		t.Fatalf("Get(a) = %d, %t, want 1, true", v, ok)
+	}
+	c.Put("d", 4)
+	if _, ok := c.Get("b"); ok {
+		t.Error("b is still cached, want it evicted")
+	}
+	for key, want := range map[string]int{"a": 1, "c": 3, "d": 4} {
+		if v, ok := c.Get(key); !ok || v != want {
+			t.Errorf("Get(%s) = %d, %t, want %d, true", key, v, ok, want)
+		}
+	}
+	if c.Len() != 3 {
+		t.Errorf("Len = %d, want 3", c.Len())
+	}
+}
+
+func TestLRUKeys(t *testing.T) {
+	c := newCache(t, 3)
+	c.Put("a", 1)
+	c.Put("b", 2)
+	c.Put("c", 3)
+	c.Get("a")
+	// Updating an existing key moves it to the front without evicting
+	c.Put("b", 20)
+	if got, want := c.Keys(), []string{"b", "a", "c"}; !slices.Equal(got, want) {
+		t.Errorf("Keys = %v, want %v", got, want)
+	}
+	if v, _ := c.Get("b"); v != 20 {
+		t.Errorf("Get(b) = %d, want 20", v)
+	}
+}
+
+func TestLRUCapacityOne(t *testing.T) {
+	c := newCache(t, 1)
+	c.Put("a", 1)
+	c.Put("b", 2)
+	if _, ok := c.Get("a"); ok {
+		t.Error("a is still cached")
+	}
+	if got := c.Keys(); !slices.Equal(got, []string{"b"}) {
+		t.Errorf("Keys = %v, want [b]", got)
+	}
+}
+
+func TestNewLRUCacheInvalidCapacity(t *testing.T) {
+	for _, capacity := range []int{0, -1} {
+		if _, err := NewLRUCache[string, int](capacity); err == nil {
+			t.Errorf("NewLRUCache(%d) succeeded", capacity)
+		}
+	}
+}
+
+// BenchmarkLRU makes 1M accesses to keys picked at random from a set 1.25
+// times the cache's size, which gives an 80% hit rate once the cache is full.
+// A miss puts the key.
+func BenchmarkLRU(b *testing.B) {
+	const (
+		capacity = 8000
+		keySpace = capacity * 5 / 4
+		accesses = 1_000_000
+	)
+	rng := rand.New(rand.NewSource(1))
+	keys := make([]int, accesses)
+	for i := range keys {
+		keys[i] = rng.Intn(keySpace)
+	}
+	c, err := NewLRUCache[int, int](capacity)
+	if err != nil {
+		b.Fatal(err)
+	}
+	for k := 0; k < capacity; k++ {
+		c.Put(k, k)
+	}
+
+	hits := 0
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for _, k := range keys {
+			if _, ok := c.Get(k); ok {
+				hits++
+			} else {
+				c.Put(k, k)
+			}
+		}
+	}
+	b.ReportMetric(float64(hits)/float64(b.N*accesses), "hit-rate")
+}
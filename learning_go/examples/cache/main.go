@@ -0,0 +1,21 @@
+package main
+
+import "fmt"
+
+func main() {
+	c, err := NewLRUCache[string, int](3)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	c.Put("a", 1)
+	c.Put("b", 2)
+	c.Put("c", 3)
+	fmt.Println(c.Get("a")) // 1 true, a is now the most recently used
+	c.Put("d", 4)           // evicts b
+	fmt.Println(c.Get("b"))
+	fmt.Println(c.Keys(), c.Len(), c.Cap())
+
+	_, err = NewLRUCache[string, int](0)
+	fmt.Println(err)
+}
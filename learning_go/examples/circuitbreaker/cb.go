@@ -0,0 +1,146 @@
+package main
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrCircuitOpen is returned by Do without calling the function while the
+// circuit is open.
+var ErrCircuitOpen = errors.New("circuit breaker is open")
+
+type State int
+
+const (
+	// Closed lets every call through.
+	Closed State = iota
+	// Open rejects every call until the timeout has passed.
+	Open
+	// HalfOpen lets a single probe call through to see if the failures have
+	// stopped.
+	HalfOpen
+)
+
+func (s State) String() string {
+	switch s {
+	case Closed:
+		return "closed"
+	case Open:
+		return "open"
+	case HalfOpen:
+		return "half-open"
+	}
+	return "unknown"
+}
+
+// CircuitBreaker stops calling a function that keeps failing, so a flaky
+// dependency such as a network mounted filesystem isn't hammered with calls
+// that are going to fail anyway.
+type CircuitBreaker struct {
+	threshold int
+	timeout   time.Duration
+	// now is time.Now, except in tests
+	now func() time.Time
+
+	mu       sync.Mutex
+	state    State
+	failures int
+	openedAt time.Time
+	// probing is set while the half-open probe call is running
+	probing bool
+	// generation counts state changes, so a call that finishes after the
+	// state it was let through in has ended can be ignored
+	generation uint64
+}
+
+// NewCircuitBreaker returns a closed breaker that opens after threshold
+// failures in a row and allows a probe call timeout after opening.
+func NewCircuitBreaker(threshold int, timeout time.Duration) *CircuitBreaker {
+	return &CircuitBreaker{threshold: max(threshold, 1), timeout: timeout, now: time.Now}
+}
+
+// State returns the breaker's current state. An open breaker whose timeout
+// has passed reports HalfOpen.
+func (cb *CircuitBreaker) State() State {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+	cb.checkTimeout()
+	return cb.state
+}
+
+func (cb *CircuitBreaker) checkTimeout() {
+	if cb.state == Open && cb.now().Sub(cb.openedAt) >= cb.timeout {
+		cb.setState(HalfOpen)
+	}
+}
+
+func (cb *CircuitBreaker) setState(s State) {
+	cb.state = s
+	cb.generation++
+	cb.failures = 0
+	if s == Open {
+		cb.openedAt = cb.now()
+	}
+}
+
+// Do calls fn unless the circuit is open, and returns fn's error. When half
+// open only one probe call is let through at a time, the rest get
+// ErrCircuitOpen. If the probe succeeds the circuit closes, if it fails the
+// circuit opens again. Only the probe decides that: a slow call let through
+// while the circuit was closed doesn't change the state once the circuit
+// has opened since. A call that panics counts as a failure, and the panic
+// carries on to Do's caller.
+func (cb *CircuitBreaker) Do(fn func() error) error {
+	cb.mu.Lock()
+	cb.checkTimeout()
+	if cb.state == Open || (cb.state == HalfOpen && cb.probing) {
+		cb.mu.Unlock()
+		return ErrCircuitOpen
+	}
+	probe, generation := cb.state == HalfOpen, cb.generation
+	if probe {
+		cb.probing = true
+	}
+	cb.mu.Unlock()
+
+	// Record the result in a defer so a panicking probe still clears
+	// probing, otherwise the circuit would stay half open for good
+	err, returned := errPanicked, false
+	defer func() {
+		if !returned {
+			cb.record(probe, generation, err)
+		}
+	}()
+	err = fn()
+	returned = true
+	cb.record(probe, generation, err)
+	return err
+}
+
+// errPanicked is recorded for a call that panicked instead of returning.
+var errPanicked = errors.New("call panicked")
+
+// record updates the state with the result of a call let through by Do.
+func (cb *CircuitBreaker) record(probe bool, generation uint64, err error) {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+	switch {
+	case probe:
+		cb.probing = false
+		if err == nil {
+			cb.setState(Closed)
+		} else {
+			cb.setState(Open)
+		}
+	case generation != cb.generation:
+		// The circuit has opened since fn was called
+	case err == nil:
+		cb.failures = 0
+	default:
+		cb.failures++
+		if cb.failures >= cb.threshold {
+			cb.setState(Open)
+		}
+	}
+}
@@ -0,0 +1,219 @@
+package main
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+var errTest = errors.New("failed")
+
+// fakeClock is a CircuitBreaker clock that only moves when told to.
+type fakeClock struct {
+	mu sync.Mutex
+	t  time.Time
+}
+
+func (c *fakeClock) now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.t
+}
+
+func (c *fakeClock) advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.t = c.t.Add(d)
+}
+
+func newTestBreaker(threshold int) (*CircuitBreaker, *fakeClock) {
+	clock := &fakeClock{t: time.Unix(0, 0)}
+	cb := NewCircuitBreaker(threshold, time.Second)
+	cb.now = clock.now
+	return cb, clock
+}
+
+func succeed() error { return nil }
+func fail() error    { return errTest }
+
+// blocked starts cb.Do with a call that doesn't return until release is
+// closed, and waits until the call has started. The result goes to done.
+func blocked(cb *CircuitBreaker, result error) (release chan struct{}, done chan error) {
+	release, done = make(chan struct{}), make(chan error, 1)
+	started := make(chan struct{})
+	go func() {
+		done <- cb.Do(func() error {
+			close(started)
+			<-release
+			return result
+		})
+	}()
+	<-started
+	return release, done
+}
+
+func TestCircuitBreakerStates(t *testing.T) {
+	cb, clock := newTestBreaker(3)
+	steps := []struct {
+		name    string
+		advance time.Duration
+		fn      func() error
+		wantErr error
+		want    State
+	}{
+		{"success", 0, succeed, nil, Closed},
+		{"first failure", 0, fail, errTest, Closed},
+		{"second failure", 0, fail, errTest, Closed},
+		{"success resets the count", 0, succeed, nil, Closed},
+		{"failure 1 of 3", 0, fail, errTest, Closed},
+		{"failure 2 of 3", 0, fail, errTest, Closed},
+		{"failure 3 of 3 opens", 0, fail, errTest, Open},
+		{"open rejects", 0, succeed, ErrCircuitOpen, Open},
+		{"open before the timeout", 999 * time.Millisecond, succeed, ErrCircuitOpen, Open},
+		{"failed probe reopens", time.Millisecond, fail, errTest, Open},
+		{"reopened rejects", 500 * time.Millisecond, succeed, ErrCircuitOpen, Open},
+		{"successful probe closes", 500 * time.Millisecond, succeed, nil, Closed},
+		{"closed again needs 3 failures", 0, fail, errTest, Closed},
+	}
+	for _, s := range steps {
+		clock.advance(s.advance)
+		called := false
+		err := cb.Do(func() error {
+			called = true
+			return s.fn()
+		})
+		if !errors.Is(err, s.wantErr) {
+			t.Fatalf("%s: Do() = %v, want %v", s.name, err, s.wantErr)
+		}
+		if called != (s.wantErr != ErrCircuitOpen) {
+			t.Errorf("%s: fn called = %v", s.name, called)
+		}
+		if got := cb.State(); got != s.want {
+			t.Fatalf("%s: state %v, want %v", s.name, got, s.want)
+		}
+	}
+}
+
+func TestCircuitBreakerHalfOpenState(t *testing.T) {
+	cb, clock := newTestBreaker(1)
+	cb.Do(fail)
+	clock.advance(time.Second)
+	if got := cb.State(); got != HalfOpen {
+		t.Fatalf("state %v after the timeout, want half-open", got)
+	}
+}
+
+// TestCircuitBreakerOneProbe checks only one call gets through while half
+// open, however many are made at once.
+func TestCircuitBreakerOneProbe(t *testing.T) {
+	cb, clock := newTestBreaker(1)
+	cb.Do(fail)
+	clock.advance(time.Second)
+	release, done := blocked(cb, nil)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := cb.Do(succeed); err != ErrCircuitOpen {
+				t.Errorf("second call while probing: Do() = %v, want ErrCircuitOpen", err)
+			}
+		}()
+	}
+	wg.Wait()
+	close(release)
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+	if got := cb.State(); got != Closed {
+		t.Errorf("state %v after the probe, want closed", got)
+	}
+}
+
+// TestCircuitBreakerSlowCall has a call let through while closed finish
+// after the circuit has opened.
+func TestCircuitBreakerSlowCall(t *testing.T) {
+	t.Run("success while open", func(t *testing.T) {
+		cb, _ := newTestBreaker(1)
+		release, done := blocked(cb, nil)
+		cb.Do(fail)
+		close(release)
+		<-done
+		if got := cb.State(); got != Open {
+			t.Errorf("state %v, want open", got)
+		}
+	})
+
+	t.Run("success while probing", func(t *testing.T) {
+		cb, clock := newTestBreaker(1)
+		slowRelease, slowDone := blocked(cb, nil)
+		cb.Do(fail)
+		clock.advance(time.Second)
+		probeRelease, probeDone := blocked(cb, errTest)
+
+		close(slowRelease)
+		<-slowDone
+		if got := cb.State(); got != HalfOpen {
+			t.Errorf("state %v after the slow call, want half-open", got)
+		}
+		if err := cb.Do(succeed); err != ErrCircuitOpen {
+			t.Errorf("a second probe got through: Do() = %v", err)
+		}
+		close(probeRelease)
+		<-probeDone
+		if got := cb.State(); got != Open {
+			t.Errorf("state %v after the failed probe, want open", got)
+		}
+	})
+
+	t.Run("failure after closing again", func(t *testing.T) {
+		cb, clock := newTestBreaker(1)
+		release, done := blocked(cb, errTest)
+		cb.Do(fail)
+		clock.advance(time.Second)
+		cb.Do(succeed)
+		close(release)
+		<-done
+		if got := cb.State(); got != Closed {
+			t.Errorf("state %v, want closed", got)
+		}
+	})
+}
+
+// doPanic calls cb.Do with a function that panics, and returns what Do
+// panicked with.
+func doPanic(cb *CircuitBreaker) (v any) {
+	defer func() { v = recover() }()
+	cb.Do(func() error { panic("boom") })
+	return nil
+}
+
+func TestCircuitBreakerPanic(t *testing.T) {
+	cb, clock := newTestBreaker(2)
+	for i := 0; i < 2; i++ {
+		if v := doPanic(cb); v != "boom" {
+			t.Fatalf("Do panicked with %v, want boom", v)
+		}
+	}
+	if s := cb.State(); s != Open {
+		t.Fatalf("state after 2 panics is %v, want open", s)
+	}
+
+	// A panicking probe reopens the circuit instead of leaving it probing
+	clock.advance(time.Second)
+	if v := doPanic(cb); v != "boom" {
+		t.Fatalf("probe panicked with %v, want boom", v)
+	}
+	if s := cb.State(); s != Open {
+		t.Fatalf("state after a panicking probe is %v, want open", s)
+	}
+	clock.advance(time.Second)
+	if err := cb.Do(succeed); err != nil {
+		t.Fatalf("probe after the timeout: %v", err)
+	}
+	if s := cb.State(); s != Closed {
+		t.Errorf("state after a successful probe is %v, want closed", s)
+	}
+}
@@ -0,0 +1,43 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"time"
+)
+
+// flakyReadFile fails the first few times it's called, like a file on a
+// network mount that has dropped out for a moment.
+func flakyReadFile(failures int) func(string) ([]byte, error) {
+	calls := 0
+	return func(name string) ([]byte, error) {
+		calls++
+		if calls <= failures {
+			return nil, errors.New("network filesystem unavailable")
+		}
+		return os.ReadFile(name)
+	}
+}
+
+func main() {
+	cb := NewCircuitBreaker(3, 50*time.Millisecond)
+	readFile := flakyReadFile(4)
+	read := func() error {
+		data, err := readFile("go.mod")
+		if err == nil {
+			fmt.Printf("read %d bytes\n", len(data))
+		}
+		return err
+	}
+
+	for i := 0; i < 5; i++ {
+		err := cb.Do(read)
+		fmt.Println(err, cb.State())
+	}
+	time.Sleep(60 * time.Millisecond)
+	// The probe fails, so the circuit opens again
+	fmt.Println(cb.Do(read), cb.State())
+	time.Sleep(60 * time.Millisecond)
+	fmt.Println(cb.Do(read), cb.State())
+}
@@ -0,0 +1,3 @@
+module cursor
+
+go 1.21.3
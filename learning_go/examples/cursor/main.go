@@ -0,0 +1,121 @@
+package main
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+)
+
+type Match struct {
+	ID        string
+	Timestamp time.Time
+	Team1     string
+	Score1    int
+	Team2     string
+	Score2    int
+}
+
+// Cursor marks the last match a client has seen. Matches are ordered by
+// Timestamp, and by ID when two matches share a timestamp, so a cursor keeps
+// pointing at the same place even when new matches are added.
+type Cursor struct {
+	ID        string
+	Timestamp time.Time
+}
+
+// before reports whether m sorts before (or at) the cursor.
+func (c Cursor) before(m Match) bool {
+	if !m.Timestamp.Equal(c.Timestamp) {
+		return m.Timestamp.Before(c.Timestamp)
+	}
+	return m.ID <= c.ID
+}
+
+// Encode turns the cursor into an opaque string that can be handed to a
+// client.
+func (c Cursor) Encode() (string, error) {
+	data, err := json.Marshal(c)
+	if err != nil {
+		return "", err
+	}
+	return base64.URLEncoding.EncodeToString(data), nil
+}
+
+// DecodeCursor parses a string produced by Cursor.Encode.
+func DecodeCursor(s string) (Cursor, error) {
+	data, err := base64.URLEncoding.DecodeString(s)
+	if err != nil {
+		return Cursor{}, fmt.Errorf("invalid cursor: %w", err)
+	}
+	var c Cursor
+	if err := json.Unmarshal(data, &c); err != nil {
+		return Cursor{}, fmt.Errorf("invalid cursor: %w", err)
+	}
+	return c, nil
+}
+
+// After returns up to n matches that come after the cursor, the cursor for
+// the last match returned, and whether there are more matches after that.
+// The zero Cursor starts from the beginning. matches doesn't need to be
+// sorted and isn't modified.
+func After(matches []Match, c Cursor, n int) ([]Match, Cursor, bool) {
+	fromStart := c.Timestamp.IsZero() && c.ID == ""
+	var page []Match
+	for _, m := range matches {
+		if fromStart || !c.before(m) {
+			page = append(page, m)
+		}
+	}
+	sort.Slice(page, func(i, j int) bool {
+		if !page[i].Timestamp.Equal(page[j].Timestamp) {
+			return page[i].Timestamp.Before(page[j].Timestamp)
+		}
+		return page[i].ID < page[j].ID
+	})
+	if n < 0 {
+		n = 0
+	}
+	more := len(page) > n
+	if more {
+		page = page[:n]
+	}
+	if len(page) == 0 {
+		return nil, c, false
+	}
+	last := page[len(page)-1]
+	return page, Cursor{ID: last.ID, Timestamp: last.Timestamp}, more
+}
+
+func main() {
+	start := time.Date(2023, 12, 1, 18, 0, 0, 0, time.UTC)
+	matches := []Match{
+		{ID: "m1", Timestamp: start, Team1: "USA", Score1: 50, Team2: "Canada", Score2: 70},
+		{ID: "m2", Timestamp: start.Add(time.Hour), Team1: "Serbia", Score1: 85, Team2: "Germany", Score2: 80},
+		{ID: "m3", Timestamp: start.Add(2 * time.Hour), Team1: "USA", Score1: 60, Team2: "Serbia", Score2: 55},
+	}
+
+	page, c, more := After(matches, Cursor{}, 2)
+	for _, m := range page {
+		fmt.Println(m.ID, m.Team1, m.Team2)
+	}
+	token, err := c.Encode()
+	if err != nil {
+		panic(err)
+	}
+	fmt.Println("next:", token, more)
+
+	// A match recorded between requests doesn't shift the next page
+	matches = append(matches, Match{ID: "m4", Timestamp: start.Add(3 * time.Hour), Team1: "Canada", Score1: 100, Team2: "Germany", Score2: 110})
+
+	c, err = DecodeCursor(token)
+	if err != nil {
+		panic(err)
+	}
+	page, _, more = After(matches, c, 2)
+	for _, m := range page {
+		fmt.Println(m.ID, m.Team1, m.Team2)
+	}
+	fmt.Println("more:", more)
+}
@@ -0,0 +1,124 @@
+package main
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+var start = time.Date(2023, 12, 1, 18, 0, 0, 0, time.UTC)
+
+func match(id string, minutes int) Match {
+	return Match{ID: id, Timestamp: start.Add(time.Duration(minutes) * time.Minute)}
+}
+
+// TestAfterInterleavedInserts pages through matches three at a time,
+// passing the cursor through Encode and DecodeCursor, and records new
+// matches between pages. Every match at or after the cursor's position must
+// be returned exactly once.
+func TestAfterInterleavedInserts(t *testing.T) {
+	var matches []Match
+	for i := 0; i < 10; i++ {
+		matches = append(matches, match(fmt.Sprintf("m%02d", i), i*10))
+	}
+	// Recorded after each page. Some share a timestamp with a match that has
+	// already been returned, some go at the end, and m01b goes before the
+	// cursor.
+	inserts := [][]Match{
+		{match("m99", 200), match("m02b", 20), match("m01b", 15)},
+		{match("m05b", 50), match("m98", 150)},
+		{match("m07a", 75)},
+		{match("m97", 300)},
+	}
+
+	seen := map[string]int{}
+	var order []Match
+	token := ""
+	for page := 0; ; page++ {
+		c := Cursor{}
+		if token != "" {
+			var err error
+			if c, err = DecodeCursor(token); err != nil {
+				t.Fatal(err)
+			}
+		}
+		got, next, more := After(matches, c, 3)
+		for _, m := range got {
+			seen[m.ID]++
+			order = append(order, m)
+		}
+		if page < len(inserts) {
+			matches = append(matches, inserts[page]...)
+		}
+		if !more && page >= len(inserts) {
+			break
+		}
+		var err error
+		if token, err = next.Encode(); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for i := 1; i < len(order); i++ {
+		prev := Cursor{ID: order[i-1].ID, Timestamp: order[i-1].Timestamp}
+		if prev.before(order[i]) {
+			t.Errorf("%s came after %s", order[i].ID, order[i-1].ID)
+		}
+	}
+	for _, m := range matches {
+		if n := seen[m.ID]; n > 1 {
+			t.Errorf("%s was returned %d times", m.ID, n)
+		}
+	}
+	// m01b sorts before m02, which was last on the first page, so the
+	// cursor has already passed it. Everything else, including m02b which
+	// shares m02's timestamp, is after the cursor when it's added.
+	for _, m := range matches {
+		if m.ID != "m01b" && seen[m.ID] != 1 {
+			t.Errorf("%s was skipped", m.ID)
+		}
+	}
+	if seen["m01b"] != 0 {
+		t.Error("m01b was returned after the cursor had passed it")
+	}
+}
+
+func TestAfter(t *testing.T) {
+	matches := []Match{match("b", 10), match("a", 10), match("c", 0)}
+	page, c, more := After(matches, Cursor{}, 2)
+	if len(page) != 2 || page[0].ID != "c" || page[1].ID != "a" || !more {
+		t.Fatalf("first page = %v, %t, want c and a with more", page, more)
+	}
+	if c.ID != "a" || !c.Timestamp.Equal(start.Add(10*time.Minute)) {
+		t.Errorf("cursor = %+v, want a", c)
+	}
+	page, c, more = After(matches, c, 2)
+	if len(page) != 1 || page[0].ID != "b" || more {
+		t.Fatalf("second page = %v, %t, want b without more", page, more)
+	}
+	// Past the end the cursor stays where it was
+	page, end, more := After(matches, c, 2)
+	if page != nil || end != c || more {
+		t.Errorf("after the end = %v, %+v, %t, want nothing and the same cursor", page, end, more)
+	}
+	if page, _, _ := After(matches, Cursor{}, -1); page != nil {
+		t.Errorf("After with n = -1 returned %v", page)
+	}
+}
+
+func TestCursorEncoding(t *testing.T) {
+	c := Cursor{ID: "m1", Timestamp: start}
+	token, err := c.Encode()
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := DecodeCursor(token)
+	if err != nil || got.ID != c.ID || !got.Timestamp.Equal(c.Timestamp) {
+		t.Errorf("DecodeCursor(Encode(%+v)) = %+v, %v", c, got, err)
+	}
+	for _, bad := range []string{"not base64!", "bm90IGpzb24="} {
+		if _, err := DecodeCursor(bad); err == nil {
+			t.Errorf("DecodeCursor(%q) succeeded", bad)
+		}
+	}
+}
@@ -0,0 +1,42 @@
+package main
+
+import (
+	"sync"
+	"time"
+)
+
+// Debounce returns a function that calls fn once wait has passed since it
+// was last called. Calling it again before then starts the wait over, so a
+// burst of calls ends up as a single call to fn after the burst. fn runs on
+// its own goroutine.
+func Debounce(fn func(), wait time.Duration) func() {
+	var mu sync.Mutex
+	var timer *time.Timer
+	return func() {
+		mu.Lock()
+		defer mu.Unlock()
+		if timer != nil {
+			timer.Stop()
+		}
+		timer = time.AfterFunc(wait, fn)
+	}
+}
+
+// Throttle returns a function that calls fn at most once per interval. The
+// first call goes through straight away and any calls in the following
+// interval are dropped. fn runs on the caller's goroutine.
+func Throttle(fn func(), interval time.Duration) func() {
+	var mu sync.Mutex
+	var last time.Time
+	return func() {
+		mu.Lock()
+		now := time.Now()
+		if !last.IsZero() && now.Sub(last) < interval {
+			mu.Unlock()
+			return
+		}
+		last = now
+		mu.Unlock()
+		fn()
+	}
+}
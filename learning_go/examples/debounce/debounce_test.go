@@ -0,0 +1,91 @@
+package main
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestDebounce(t *testing.T) {
+	var calls atomic.Int32
+	done := make(chan struct{}, 10)
+	f := Debounce(func() {
+		calls.Add(1)
+		done <- struct{}{}
+	}, 50*time.Millisecond)
+
+	// Two bursts, far enough apart that each ends in one call
+	for burst := 1; burst <= 2; burst++ {
+		for i := 0; i < 10; i++ {
+			f()
+			time.Sleep(time.Millisecond)
+		}
+		if n := calls.Load(); n != int32(burst-1) {
+			t.Fatalf("fn called %d times during burst %d", n, burst)
+		}
+		<-done
+		time.Sleep(100 * time.Millisecond)
+		if n := calls.Load(); n != int32(burst) {
+			t.Fatalf("fn called %d times after burst %d, want %d", n, burst, burst)
+		}
+	}
+}
+
+func TestDebounceConcurrent(t *testing.T) {
+	var calls atomic.Int32
+	f := Debounce(func() { calls.Add(1) }, 50*time.Millisecond)
+	var wg sync.WaitGroup
+	for i := 0; i < 100; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			f()
+		}()
+	}
+	wg.Wait()
+	time.Sleep(200 * time.Millisecond)
+	if n := calls.Load(); n != 1 {
+		t.Errorf("fn called %d times, want 1", n)
+	}
+}
+
+func TestThrottle(t *testing.T) {
+	calls := 0
+	f := Throttle(func() { calls++ }, time.Hour)
+	for i := 0; i < 100; i++ {
+		f()
+	}
+	// The first call runs straight away and the rest are dropped
+	if calls != 1 {
+		t.Errorf("fn called %d times, want 1", calls)
+	}
+
+	calls = 0
+	f = Throttle(func() { calls++ }, 20*time.Millisecond)
+	f()
+	f()
+	time.Sleep(30 * time.Millisecond)
+	f()
+	f()
+	if calls != 2 {
+		t.Errorf("fn called %d times across two intervals, want 2", calls)
+	}
+}
+
+func TestThrottleConcurrent(t *testing.T) {
+	var calls atomic.Int32
+	f := Throttle(func() { calls.Add(1) }, time.Hour)
+	var wg sync.WaitGroup
+	for i := 0; i < 100; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			f()
+		}()
+	}
+	wg.Wait()
+	if n := calls.Load(); n != 1 {
+		t.Errorf("fn called %d times, want 1", n)
+	}
+}
@@ -0,0 +1,3 @@
+module debounce
+
+go 1.21.3
@@ -0,0 +1,35 @@
+package main
+
+import (
+	"fmt"
+	"sync/atomic"
+	"time"
+)
+
+func main() {
+	// Pretend someone is typing "2 + 3" into the calculator REPL and we only
+	// want to evaluate once they pause
+	var evaluations atomic.Int32
+	done := make(chan struct{})
+	evaluate := Debounce(func() {
+		evaluations.Add(1)
+		done <- struct{}{}
+	}, 30*time.Millisecond)
+	for range "2 + 3" {
+		evaluate()
+		time.Sleep(5 * time.Millisecond)
+	}
+	<-done
+	fmt.Println("debounced evaluations:", evaluations.Load())
+
+	// Redraw the screen at most once every 20ms, however often it's asked
+	// for
+	redraws := 0
+	redraw := Throttle(func() { redraws++ }, 20*time.Millisecond)
+	start := time.Now()
+	for time.Since(start) < 50*time.Millisecond {
+		redraw()
+		time.Sleep(time.Millisecond)
+	}
+	fmt.Println("throttled redraws:", redraws)
+}
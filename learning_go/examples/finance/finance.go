@@ -0,0 +1,34 @@
+// Package finance has the time value of money formulas: growing an amount
+// at compound interest and discounting it back again.
+package finance
+
+import "math"
+
+// FutureValue returns what present is worth after growing at rate for the
+// given number of compounding periods.
+func FutureValue(present, rate float64, periods int) float64 {
+	return present * math.Pow(1+rate, float64(periods))
+}
+
+// PresentValue discounts an amount received after the given number of periods
+// back to what it is worth today.
+func PresentValue(future, rate float64, periods int) float64 {
+	return future / math.Pow(1+rate, float64(periods))
+}
+
+// CompoundInterest returns only the interest earned on principal, not the
+// principal itself.
+func CompoundInterest(principal, rate float64, periods int) float64 {
+	return FutureValue(principal, rate, periods) - principal
+}
+
+// NPV returns the net present value of a series of cash flows. The first cash
+// flow happens now (period 0) and isn't discounted, each one after that is
+// one period later than the last.
+func NPV(rate float64, cashflows []float64) float64 {
+	var total float64
+	for i, cf := range cashflows {
+		total += PresentValue(cf, rate, i)
+	}
+	return total
+}
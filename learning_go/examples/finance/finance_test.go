@@ -0,0 +1,99 @@
+package finance
+
+import (
+	"fmt"
+	"math"
+	"testing"
+)
+
+// within reports whether got is no more than tol away from want.
+func within(got, want, tol float64) bool {
+	return math.Abs(got-want) <= tol
+}
+
+func TestFutureValue(t *testing.T) {
+	tests := []struct {
+		present, rate float64
+		periods       int
+		want          float64
+	}{
+		{1000, 0.05, 10, 1628.89},
+		{1000, 0.05, 0, 1000},
+		{1000, 0, 10, 1000},
+		{500, 0.1, 2, 605},
+		{1000, -0.5, 1, 500},
+	}
+	for _, tt := range tests {
+		if got := FutureValue(tt.present, tt.rate, tt.periods); !within(got, tt.want, 0.01) {
+			t.Errorf("FutureValue(%v, %v, %d) = %v, want %v", tt.present, tt.rate, tt.periods, got, tt.want)
+		}
+	}
+}
+
+func TestCompoundInterest(t *testing.T) {
+	if got := CompoundInterest(1000, 0.05, 10); !within(got, 628.89, 0.01) {
+		t.Errorf("CompoundInterest(1000, 0.05, 10) = %v, want 628.89", got)
+	}
+	if got := CompoundInterest(1000, 0.05, 0); got != 0 {
+		t.Errorf("CompoundInterest(1000, 0.05, 0) = %v, want 0", got)
+	}
+}
+
+func TestPresentValue(t *testing.T) {
+	tests := []struct {
+		future, rate float64
+		periods      int
+		want         float64
+	}{
+		{1628.89, 0.05, 10, 1000},
+		{605, 0.1, 2, 500},
+		{1000, 0.05, 0, 1000},
+		{1000, 0, 5, 1000},
+	}
+	for _, tt := range tests {
+		if got := PresentValue(tt.future, tt.rate, tt.periods); !within(got, tt.want, 0.01) {
+			t.Errorf("PresentValue(%v, %v, %d) = %v, want %v", tt.future, tt.rate, tt.periods, got, tt.want)
+		}
+	}
+	// Discounting undoes growing
+	for periods := 0; periods < 50; periods++ {
+		if got := PresentValue(FutureValue(1234.56, 0.07, periods), 0.07, periods); !within(got, 1234.56, 1e-9) {
+			t.Fatalf("PresentValue(FutureValue(1234.56)) over %d periods = %v", periods, got)
+		}
+	}
+}
+
+func TestNPV(t *testing.T) {
+	tests := []struct {
+		rate      float64
+		cashflows []float64
+		want      float64
+	}{
+		{0.05, nil, 0},
+		// The first cash flow isn't discounted
+		{0.05, []float64{-1000}, -1000},
+		{0, []float64{-1000, 400, 400, 400}, 200},
+		{0.05, []float64{-1000, 400, 400, 400}, 89.30},
+		{0.1, []float64{-100, 110}, 0},
+		{0.1, []float64{-1000, 0, 0, 1331}, 0},
+	}
+	for _, tt := range tests {
+		if got := NPV(tt.rate, tt.cashflows); !within(got, tt.want, 0.01) {
+			t.Errorf("NPV(%v, %v) = %v, want %v", tt.rate, tt.cashflows, got, tt.want)
+		}
+	}
+}
+
+func Example() {
+	prize := 1000.0
+	fmt.Printf("future value: %.2f\n", FutureValue(prize, 0.05, 10))
+	fmt.Printf("interest earned: %.2f\n", CompoundInterest(prize, 0.05, 10))
+	fmt.Printf("present value: %.2f\n", PresentValue(1628.89, 0.05, 10))
+	// Pay an entry fee now and collect prize money over the next three seasons
+	fmt.Printf("npv: %.2f\n", NPV(0.05, []float64{-1000, 400, 400, 400}))
+	// Output:
+	// future value: 1628.89
+	// interest earned: 628.89
+	// present value: 1000.00
+	// npv: 89.30
+}
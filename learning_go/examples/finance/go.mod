@@ -0,0 +1,3 @@
+module finance
+
+go 1.21.3
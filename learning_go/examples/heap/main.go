@@ -0,0 +1,25 @@
+package main
+
+import "fmt"
+
+func main() {
+	h := NewMinHeap(5, 2, 8, 1)
+	h.Push(3)
+	v, ok := h.Peek()
+	fmt.Println(v, ok, h.Len())
+	for h.Len() > 0 {
+		v, _ := h.Pop()
+		fmt.Print(v, " ")
+	}
+	fmt.Println()
+	_, ok = h.Pop()
+	fmt.Println(ok)
+
+	mh := NewMaxHeap(5, 2, 8, 1)
+	mh.Push(9)
+	for mh.Len() > 0 {
+		v, _ := mh.Pop()
+		fmt.Print(v, " ")
+	}
+	fmt.Println()
+}
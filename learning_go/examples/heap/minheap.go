@@ -0,0 +1,106 @@
+package main
+
+import "container/heap"
+
+// minInts implements heap.Interface with the smallest value on top.
+type minInts []int
+
+func (h minInts) Len() int           { return len(h) }
+func (h minInts) Less(i, j int) bool { return h[i] < h[j] }
+func (h minInts) Swap(i, j int)      { h[i], h[j] = h[j], h[i] }
+
+func (h *minInts) Push(x any) {
+	*h = append(*h, x.(int))
+}
+
+func (h *minInts) Pop() any {
+	old := *h
+	v := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return v
+}
+
+// maxInts is minInts with Less inverted, so the largest value is on top.
+type maxInts struct {
+	minInts
+}
+
+func (h maxInts) Less(i, j int) bool { return h.minInts[i] > h.minInts[j] }
+
+// MinHeap is a priority queue of ints that always returns the smallest value
+// first. Its methods call the container/heap functions, so callers can't
+// break the heap by using the slice directly.
+type MinHeap struct {
+	vals minInts
+}
+
+// NewMinHeap returns a heap holding vals. Building the heap in one go with
+// heap.Init is O(n), pushing the values one at a time would be O(n log n).
+func NewMinHeap(vals ...int) *MinHeap {
+	h := &MinHeap{vals: append(minInts(nil), vals...)}
+	heap.Init(&h.vals)
+	return h
+}
+
+func (h *MinHeap) Push(v int) {
+	heap.Push(&h.vals, v)
+}
+
+// Pop removes and returns the smallest value. It returns false if the heap
+// is empty.
+func (h *MinHeap) Pop() (int, bool) {
+	if len(h.vals) == 0 {
+		return 0, false
+	}
+	return heap.Pop(&h.vals).(int), true
+}
+
+// Peek returns the smallest value without removing it. It returns false if
+// the heap is empty.
+func (h *MinHeap) Peek() (int, bool) {
+	if len(h.vals) == 0 {
+		return 0, false
+	}
+	return h.vals[0], true
+}
+
+func (h *MinHeap) Len() int {
+	return len(h.vals)
+}
+
+// MaxHeap is like MinHeap but returns the largest value first.
+type MaxHeap struct {
+	vals maxInts
+}
+
+func NewMaxHeap(vals ...int) *MaxHeap {
+	h := &MaxHeap{vals: maxInts{append(minInts(nil), vals...)}}
+	heap.Init(&h.vals)
+	return h
+}
+
+func (h *MaxHeap) Push(v int) {
+	heap.Push(&h.vals, v)
+}
+
+// Pop removes and returns the largest value. It returns false if the heap is
+// empty.
+func (h *MaxHeap) Pop() (int, bool) {
+	if len(h.vals.minInts) == 0 {
+		return 0, false
+	}
+	return heap.Pop(&h.vals).(int), true
+}
+
+// Peek returns the largest value without removing it. It returns false if
+// the heap is empty.
+func (h *MaxHeap) Peek() (int, bool) {
+	if len(h.vals.minInts) == 0 {
+		return 0, false
+	}
+	return h.vals.minInts[0], true
+}
+
+func (h *MaxHeap) Len() int {
+	return len(h.vals.minInts)
+}
@@ -0,0 +1,122 @@
+package main
+
+import (
+	"math/rand"
+	"slices"
+	"sort"
+	"testing"
+)
+
+func TestMinHeap(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	vals := make([]int, 500)
+	for i := range vals {
+		vals[i] = r.Intn(100) - 50
+	}
+	// Half the values go in through NewMinHeap, the rest through Push
+	h := NewMinHeap(vals[:250]...)
+	for _, v := range vals[250:] {
+		h.Push(v)
+	}
+	if h.Len() != len(vals) {
+		t.Fatalf("Len = %d, want %d", h.Len(), len(vals))
+	}
+	want := slices.Clone(vals)
+	sort.Ints(want)
+	for i, w := range want {
+		if v, ok := h.Peek(); !ok || v != w {
+			t.Fatalf("Peek %d = %d, %t, want %d", i, v, ok, w)
+		}
+		if v, ok := h.Pop(); !ok || v != w {
+			t.Fatalf("Pop %d = %d, %t, want %d", i, v, ok, w)
+		}
+	}
+	if _, ok := h.Pop(); ok {
+		t.Error("Pop on an empty heap succeeded")
+	}
+	if _, ok := h.Peek(); ok {
+		t.Error("Peek on an empty heap succeeded")
+	}
+}
+
+func TestMaxHeap(t *testing.T) {
+	h := NewMaxHeap(3, 1, 4, 1, 5)
+	h.Push(9)
+	h.Push(2)
+	for _, want := range []int{9, 5, 4, 3, 2, 1, 1} {
+		if v, ok := h.Pop(); !ok || v != want {
+			t.Fatalf("Pop = %d, %t, want %d", v, ok, want)
+		}
+	}
+	if _, ok := h.Pop(); ok || h.Len() != 0 {
+		t.Error("heap isn't empty")
+	}
+}
+
+func TestNewMinHeapCopies(t *testing.T) {
+	vals := []int{3, 2, 1}
+	h := NewMinHeap(vals...)
+	h.Pop()
+	if !slices.Equal(vals, []int{3, 2, 1}) {
+		t.Errorf("NewMinHeap changed its argument to %v", vals)
+	}
+}
+
+const benchHeapSize = 100_000
+
+func benchVals() []int {
+	r := rand.New(rand.NewSource(1))
+	vals := make([]int, benchHeapSize)
+	for i := range vals {
+		vals[i] = r.Int()
+	}
+	return vals
+}
+
+// Sorting everything is what sort.Ints is for, a heap pays for a Pop per
+// value.
+
+func BenchmarkMinHeapPopAll(b *testing.B) {
+	vals := benchVals()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		h := NewMinHeap(vals...)
+		for h.Len() > 0 {
+			h.Pop()
+		}
+	}
+}
+
+func BenchmarkSortInts(b *testing.B) {
+	vals := benchVals()
+	s := make([]int, len(vals))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		copy(s, vals)
+		sort.Ints(s)
+	}
+}
+
+// Only wanting the smallest few values is where the O(n) heap build wins.
+
+func BenchmarkMinHeapSmallest10(b *testing.B) {
+	vals := benchVals()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		h := NewMinHeap(vals...)
+		for j := 0; j < 10; j++ {
+			h.Pop()
+		}
+	}
+}
+
+func BenchmarkSortIntsSmallest10(b *testing.B) {
+	vals := benchVals()
+	s := make([]int, len(vals))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		copy(s, vals)
+		sort.Ints(s)
+		_ = s[:10]
+	}
+}
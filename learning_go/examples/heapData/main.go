@@ -6,6 +6,10 @@ import (
 	"time"
 )
 
+// This example is about memory the runtime allocates on the heap. The heap
+// data structure, a priority queue built on container/heap, is in the heap
+// example next to this one.
+
 type A struct {
 	b *B
 }
@@ -0,0 +1,3 @@
+module linkedlist
+
+go 1.21.3
@@ -0,0 +1,98 @@
+package main
+
+type Node[T comparable] struct {
+	Val  T
+	Next *Node[T]
+}
+
+// LinkedList is a singly linked list. It keeps a pointer to the last node so
+// Append doesn't have to walk the list.
+type LinkedList[T comparable] struct {
+	head *Node[T]
+	tail *Node[T]
+	n    int
+}
+
+// FromSlice returns a list holding the values of s in order.
+func FromSlice[T comparable](s []T) *LinkedList[T] {
+	l := &LinkedList[T]{}
+	for _, v := range s {
+		l.Append(v)
+	}
+	return l
+}
+
+func (l *LinkedList[T]) Append(val T) {
+	node := &Node[T]{Val: val}
+	if l.tail == nil {
+		l.head = node
+	} else {
+		l.tail.Next = node
+	}
+	l.tail = node
+	l.n++
+}
+
+func (l *LinkedList[T]) Prepend(val T) {
+	l.head = &Node[T]{Val: val, Next: l.head}
+	if l.tail == nil {
+		l.tail = l.head
+	}
+	l.n++
+}
+
+// Remove deletes the first node holding val. It returns false if val isn't
+// in the list.
+func (l *LinkedList[T]) Remove(val T) bool {
+	var prev *Node[T]
+	for cur := l.head; cur != nil; prev, cur = cur, cur.Next {
+		if cur.Val != val {
+			continue
+		}
+		if prev == nil {
+			l.head = cur.Next
+		} else {
+			prev.Next = cur.Next
+		}
+		if cur == l.tail {
+			l.tail = prev
+		}
+		l.n--
+		return true
+	}
+	return false
+}
+
+func (l *LinkedList[T]) Contains(val T) bool {
+	for cur := l.head; cur != nil; cur = cur.Next {
+		if cur.Val == val {
+			return true
+		}
+	}
+	return false
+}
+
+func (l *LinkedList[T]) Len() int {
+	return l.n
+}
+
+func (l *LinkedList[T]) ToSlice() []T {
+	out := make([]T, 0, l.n)
+	for cur := l.head; cur != nil; cur = cur.Next {
+		out = append(out, cur.Val)
+	}
+	return out
+}
+
+// Reverse reverses the list in place by turning each Next pointer around.
+func (l *LinkedList[T]) Reverse() {
+	var prev *Node[T]
+	cur := l.head
+	l.tail = l.head
+	for cur != nil {
+		next := cur.Next
+		cur.Next = prev
+		prev, cur = cur, next
+	}
+	l.head = prev
+}
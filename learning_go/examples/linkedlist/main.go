@@ -0,0 +1,15 @@
+package main
+
+import "fmt"
+
+func main() {
+	l := FromSlice([]int{1, 2, 3})
+	l.Reverse()
+	fmt.Println(l.ToSlice()) // [3 2 1]
+	l.Prepend(4)
+	l.Append(0)
+	fmt.Println(l.ToSlice(), l.Len())
+	fmt.Println(l.Remove(2), l.Remove(7))
+	fmt.Println(l.Contains(2), l.Contains(3))
+	fmt.Println(l.ToSlice(), l.Len())
+}
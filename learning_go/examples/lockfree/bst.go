@@ -0,0 +1,103 @@
+package main
+
+import (
+	"cmp"
+	"sync/atomic"
+)
+
+// node is a key in the tree. Once a node is linked in it's never moved or
+// unlinked, Delete only sets its mark, so a key always lives in the same
+// node and inserting it again just clears the mark.
+type node[T cmp.Ordered] struct {
+	key         T
+	left, right atomic.Pointer[node[T]]
+	// deleted is the mark bit, a marked node is in the tree but its key
+	// isn't in the set
+	deleted atomic.Bool
+}
+
+// BST is a set of ordered values that's safe to use from many goroutines
+// without locks. New keys are linked in with a compare and swap on the nil
+// child pointer where they belong, and a goroutine that loses the race just
+// carries on down from the node that won. Deletes are logical: the node is
+// marked and stays in the tree, so memory isn't given back and a tree with
+// a lot of churn over distinct keys keeps growing. The tree isn't balanced,
+// so keys inserted in order make it a linked list. The zero value is an
+// empty tree.
+type BST[T cmp.Ordered] struct {
+	root atomic.Pointer[node[T]]
+}
+
+// find returns the node holding key, marked or not, or nil.
+func (t *BST[T]) find(key T) *node[T] {
+	n := t.root.Load()
+	for n != nil {
+		switch c := cmp.Compare(key, n.key); {
+		case c < 0:
+			n = n.left.Load()
+		case c > 0:
+			n = n.right.Load()
+		default:
+			return n
+		}
+	}
+	return nil
+}
+
+// Insert adds key and reports whether it wasn't already in the set.
+func (t *BST[T]) Insert(key T) bool {
+	var fresh *node[T]
+	link := &t.root
+	for {
+		n := link.Load()
+		if n == nil {
+			if fresh == nil {
+				fresh = &node[T]{key: key}
+			}
+			if link.CompareAndSwap(nil, fresh) {
+				return true
+			}
+			// Another goroutine linked a node here first, look at it
+			continue
+		}
+		switch c := cmp.Compare(key, n.key); {
+		case c < 0:
+			link = &n.left
+		case c > 0:
+			link = &n.right
+		default:
+			return n.deleted.CompareAndSwap(true, false)
+		}
+	}
+}
+
+// Delete removes key and reports whether it was in the set.
+func (t *BST[T]) Delete(key T) bool {
+	n := t.find(key)
+	return n != nil && n.deleted.CompareAndSwap(false, true)
+}
+
+// Contains reports whether key is in the set.
+func (t *BST[T]) Contains(key T) bool {
+	n := t.find(key)
+	return n != nil && !n.deleted.Load()
+}
+
+// Keys returns the keys in ascending order. Changes made while it runs may
+// or may not be included.
+func (t *BST[T]) Keys() []T {
+	var out []T
+	var walk func(*node[T])
+	walk = func(n *node[T]) {
+		if n == nil {
+			return
+		}
+		walk(n.left.Load())
+		if !n.deleted.Load() {
+			out = append(out, n.key)
+		}
+		walk(n.right.Load())
+	}
+	walk(t.root.Load())
+	return out
+}
@@ -0,0 +1,234 @@
+package main
+
+import (
+	"math/rand"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+// TestBST checks the set against a map, one operation at a time.
+func TestBST(t *testing.T) {
+	var tree BST[int]
+	want := map[int]bool{}
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 10000; i++ {
+		k := r.Intn(100)
+		switch r.Intn(3) {
+		case 0:
+			if got := tree.Insert(k); got != !want[k] {
+				t.Fatalf("Insert(%d) = %v with the key present %v", k, got, want[k])
+			}
+			want[k] = true
+		case 1:
+			if got := tree.Delete(k); got != want[k] {
+				t.Fatalf("Delete(%d) = %v with the key present %v", k, got, want[k])
+			}
+			delete(want, k)
+		default:
+			if got := tree.Contains(k); got != want[k] {
+				t.Fatalf("Contains(%d) = %v, want %v", k, got, want[k])
+			}
+		}
+	}
+	var keys []int
+	for k := range want {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	if got := tree.Keys(); !slices.Equal(got, keys) {
+		t.Errorf("Keys() = %v, want %v", got, keys)
+	}
+}
+
+// TestBSTConcurrent has 100 goroutines insert and delete overlapping keys.
+// Every successful Insert of a key has to be followed by a successful Delete
+// before another Insert of it can succeed, so per key the successes can
+// differ by at most one, and that has to agree with what's left in the tree.
+// Run it with -race to have the race detector check the CASes too.
+func TestBSTConcurrent(t *testing.T) {
+	const keys = 64
+	var tree BST[int]
+	var added, removed [keys]atomic.Int64
+	var wg sync.WaitGroup
+	for g := 0; g < 100; g++ {
+		wg.Add(1)
+		go func(seed int64) {
+			defer wg.Done()
+			r := rand.New(rand.NewSource(seed))
+			for i := 0; i < 1000; i++ {
+				k := r.Intn(keys)
+				if r.Intn(2) == 0 {
+					if tree.Insert(k) {
+						added[k].Add(1)
+					}
+				} else if tree.Delete(k) {
+					removed[k].Add(1)
+				}
+			}
+		}(int64(g))
+	}
+	wg.Wait()
+
+	var present []int
+	for k := 0; k < keys; k++ {
+		switch n := added[k].Load() - removed[k].Load(); n {
+		case 0:
+		case 1:
+			present = append(present, k)
+		default:
+			t.Errorf("key %d: %d successful inserts and %d deletes", k, added[k].Load(), removed[k].Load())
+		}
+		if got, want := tree.Contains(k), added[k].Load() > removed[k].Load(); got != want {
+			t.Errorf("Contains(%d) = %v, want %v", k, got, want)
+		}
+	}
+	if got := tree.Keys(); !slices.Equal(got, present) {
+		t.Errorf("Keys() = %v, want %v", got, present)
+	}
+}
+
+// op is one call made in TestBSTLinearizable. call and ret are ticks of a
+// shared clock taken just before the call and just after it returned, so op
+// a happened before op b if a.ret < b.call.
+type op struct {
+	kind      int // opInsert, opDelete or opContains
+	result    bool
+	call, ret int64
+}
+
+const (
+	opInsert = iota
+	opDelete
+	opContains
+)
+
+// apply returns what o returns on a set where the key is present or not,
+// and whether the key is present afterwards.
+func (o op) apply(present bool) (result, after bool) {
+	switch o.kind {
+	case opInsert:
+		return !present, true
+	case opDelete:
+		return present, false
+	default:
+		return present, present
+	}
+}
+
+// linearizable reports whether the ops on one key can be put in an order
+// that respects real time and in which each gives the result it did on a
+// set that starts without the key. It tries each op that could go next, as
+// in Wing and Gong's algorithm, remembering orders that already failed.
+func linearizable(ops []op) bool {
+	failed := map[[2]uint64]bool{}
+	var search func(done uint64, present bool) bool
+	search = func(done uint64, present bool) bool {
+		if done == 1<<len(ops)-1 {
+			return true
+		}
+		state := [2]uint64{done, 0}
+		if present {
+			state[1] = 1
+		}
+		if failed[state] {
+			return false
+		}
+		// Only an op called before every other remaining op returned can
+		// be the next to take effect
+		first := int64(-1)
+		for i, o := range ops {
+			if done&(1<<i) == 0 && (first < 0 || o.ret < first) {
+				first = o.ret
+			}
+		}
+		for i, o := range ops {
+			if done&(1<<i) != 0 || o.call > first {
+				continue
+			}
+			if result, after := o.apply(present); result == o.result && search(done|1<<i, after) {
+				return true
+			}
+		}
+		failed[state] = true
+		return false
+	}
+	return search(0, false)
+}
+
+// TestBSTLinearizable records short concurrent histories of Insert, Delete
+// and Contains on a few keys and checks each key's history is linearizable.
+// Every key is independent in a set, so checking them one at a time is
+// enough.
+func TestBSTLinearizable(t *testing.T) {
+	const (
+		goroutines = 6
+		calls      = 8
+		keys       = 2
+	)
+	rounds := 500
+	if testing.Short() {
+		rounds = 50
+	}
+	for round := 0; round < rounds; round++ {
+		var tree BST[int]
+		var clock atomic.Int64
+		history := make([][keys][]op, goroutines)
+		var wg sync.WaitGroup
+		for g := 0; g < goroutines; g++ {
+			wg.Add(1)
+			go func(g int) {
+				defer wg.Done()
+				r := rand.New(rand.NewSource(int64(round*goroutines + g)))
+				for i := 0; i < calls; i++ {
+					k := r.Intn(keys)
+					o := op{kind: r.Intn(3), call: clock.Add(1)}
+					switch o.kind {
+					case opInsert:
+						o.result = tree.Insert(k)
+					case opDelete:
+						o.result = tree.Delete(k)
+					default:
+						o.result = tree.Contains(k)
+					}
+					o.ret = clock.Add(1)
+					history[g][k] = append(history[g][k], o)
+				}
+			}(g)
+		}
+		wg.Wait()
+		for k := 0; k < keys; k++ {
+			var ops []op
+			for g := range history {
+				ops = append(ops, history[g][k]...)
+			}
+			if !linearizable(ops) {
+				t.Fatalf("round %d: history of key %d isn't linearizable: %+v", round, k, ops)
+			}
+		}
+	}
+}
+
+// TestLinearizable checks the checker, with histories that a set can't
+// produce.
+func TestLinearizable(t *testing.T) {
+	tests := []struct {
+		name string
+		ops  []op
+		want bool
+	}{
+		{"empty", nil, true},
+		{"insert then delete", []op{{opInsert, true, 1, 2}, {opDelete, true, 3, 4}}, true},
+		{"delete before insert", []op{{opDelete, true, 1, 2}, {opInsert, true, 3, 4}}, false},
+		{"overlapping delete and insert", []op{{opDelete, true, 1, 4}, {opInsert, true, 2, 3}}, true},
+		{"two inserts succeed", []op{{opInsert, true, 1, 4}, {opInsert, true, 2, 3}}, false},
+		{"contains sees a finished insert", []op{{opInsert, true, 1, 2}, {opContains, false, 3, 4}}, false},
+		{"contains races an insert", []op{{opInsert, true, 1, 4}, {opContains, false, 2, 3}}, true},
+	}
+	for _, tt := range tests {
+		if got := linearizable(tt.ops); got != tt.want {
+			t.Errorf("%s: linearizable() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
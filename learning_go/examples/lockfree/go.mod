@@ -0,0 +1,3 @@
+module lockfree
+
+go 1.21.3
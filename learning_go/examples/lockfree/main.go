@@ -0,0 +1,54 @@
+package main
+
+import (
+	"fmt"
+	"math/rand"
+	"sync"
+	"sync/atomic"
+)
+
+func main() {
+	var t BST[string]
+	for _, team := range []string{"USA", "Canada", "Serbia", "Germany"} {
+		t.Insert(team)
+	}
+	fmt.Println(t.Insert("USA"), t.Delete("Canada"), t.Delete("Canada"), t.Contains("Canada"))
+	fmt.Println(t.Keys())
+
+	// 100 goroutines insert and delete overlapping keys. Every successful
+	// Insert of a key has to be matched by a successful Delete before it can
+	// succeed again, so the tallies have to agree with what's left in the
+	// tree. Run with -race to have the race detector check the CASes too.
+	const keys = 64
+	var ints BST[int]
+	var added, removed [keys]atomic.Int64
+	var wg sync.WaitGroup
+	for g := 0; g < 100; g++ {
+		wg.Add(1)
+		go func(seed int64) {
+			defer wg.Done()
+			r := rand.New(rand.NewSource(seed))
+			for i := 0; i < 1000; i++ {
+				k := r.Intn(keys)
+				if r.Intn(2) == 0 {
+					if ints.Insert(k) {
+						added[k].Add(1)
+					}
+				} else if ints.Delete(k) {
+					removed[k].Add(1)
+				}
+			}
+		}(int64(g))
+	}
+	wg.Wait()
+
+	ok := true
+	for k := 0; k < keys; k++ {
+		present := added[k].Load() - removed[k].Load()
+		if present != 0 && present != 1 || (present == 1) != ints.Contains(k) {
+			fmt.Println("inconsistent key", k, added[k].Load(), removed[k].Load(), ints.Contains(k))
+			ok = false
+		}
+	}
+	fmt.Println("consistent:", ok)
+}
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+// League is the part of the league from exercise 07/ex3 that the demo loads.
+type League struct {
+	Name  string
+	Teams []string
+}
+
+func loadLeague(path string) (*League, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var l League
+	if err := json.Unmarshal(data, &l); err != nil {
+		return nil, err
+	}
+	return &l, nil
+}
+
+func main() {
+	bench := flag.Bool("bench", false, "benchmark Do once the value is cached")
+	flag.Parse()
+	if *bench {
+		var o Once[int]
+		r := testing.Benchmark(func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				o.Do(func() (int, error) { return 42, nil })
+			}
+		})
+		fmt.Println("Once.Do cached", r)
+		return
+	}
+
+	dir, err := os.MkdirTemp("", "once")
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "league.json")
+
+	var loads atomic.Int32
+	var league Once[*League]
+	get := func() (*League, error) {
+		return league.Do(func() (*League, error) {
+			loads.Add(1)
+			return loadLeague(path)
+		})
+	}
+
+	// The file isn't there yet, so the failure isn't cached
+	_, err = get()
+	fmt.Println(os.IsNotExist(err))
+	os.WriteFile(path, []byte(`{"Name": "Big League", "Teams": ["USA", "Canada"]}`), 0644)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := get(); err != nil {
+				fmt.Println(err)
+			}
+		}()
+	}
+	wg.Wait()
+	l, err := get()
+	fmt.Println(l.Name, l.Teams, err)
+	fmt.Println("loads:", loads.Load())
+}
@@ -0,0 +1,39 @@
+package main
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// Once is like sync.Once, but for a function that returns a value. The first
+// successful call's value is kept and returned by every later call without
+// running f again. A call that fails isn't kept, so the next Do tries again.
+// The zero value is ready to use.
+type Once[T any] struct {
+	// done is checked without the lock so calls after the first success
+	// don't have to wait for each other
+	done atomic.Bool
+	mu   sync.Mutex
+	val  T
+}
+
+// Do calls f if no earlier call has succeeded and returns its result, or
+// returns the value kept from the call that did. Calls made while f is
+// running wait for it to finish.
+func (o *Once[T]) Do(f func() (T, error)) (T, error) {
+	if o.done.Load() {
+		return o.val, nil
+	}
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if o.done.Load() {
+		return o.val, nil
+	}
+	v, err := f()
+	if err != nil {
+		return v, err
+	}
+	o.val = v
+	o.done.Store(true)
+	return v, nil
+}
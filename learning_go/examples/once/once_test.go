@@ -0,0 +1,144 @@
+package main
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+func TestOnceCaches(t *testing.T) {
+	var o Once[string]
+	calls := 0
+	f := func() (string, error) {
+		calls++
+		return "config", nil
+	}
+	for i := 0; i < 3; i++ {
+		if v, err := o.Do(f); err != nil || v != "config" {
+			t.Fatalf("Do() = %q, %v, want config", v, err)
+		}
+	}
+	if calls != 1 {
+		t.Errorf("f called %d times, want 1", calls)
+	}
+	// Later calls get the kept value even when passed a different f
+	v, err := o.Do(func() (string, error) { return "other", errors.New("not called") })
+	if err != nil || v != "config" {
+		t.Errorf("Do(other) = %q, %v, want config", v, err)
+	}
+}
+
+func TestOnceRetriesAfterError(t *testing.T) {
+	var o Once[int]
+	errFail := errors.New("fail")
+	calls := 0
+	f := func() (int, error) {
+		calls++
+		if calls < 3 {
+			return -1, errFail
+		}
+		return 42, nil
+	}
+	for i := 0; i < 2; i++ {
+		if v, err := o.Do(f); !errors.Is(err, errFail) || v != -1 {
+			t.Errorf("Do() call %d = %d, %v, want -1, fail", i+1, v, err)
+		}
+	}
+	for i := 0; i < 2; i++ {
+		if v, err := o.Do(f); err != nil || v != 42 {
+			t.Errorf("Do() after failures = %d, %v, want 42", v, err)
+		}
+	}
+	if calls != 3 {
+		t.Errorf("f called %d times, want 3", calls)
+	}
+}
+
+func TestOnceConcurrent(t *testing.T) {
+	var o Once[int]
+	var calls atomic.Int32
+	release := make(chan struct{})
+	f := func() (int, error) {
+		calls.Add(1)
+		<-release
+		return 7, nil
+	}
+	var wg sync.WaitGroup
+	results := make(chan int, 100)
+	for i := 0; i < 100; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			v, err := o.Do(f)
+			if err != nil {
+				t.Error(err)
+			}
+			results <- v
+		}()
+	}
+	close(release)
+	wg.Wait()
+	close(results)
+	for v := range results {
+		if v != 7 {
+			t.Errorf("Do() = %d, want 7", v)
+		}
+	}
+	if n := calls.Load(); n != 1 {
+		t.Errorf("f called %d times, want 1", n)
+	}
+}
+
+func TestOnceConcurrentErrors(t *testing.T) {
+	// Failed calls run one at a time, and once one succeeds no more do
+	var o Once[int]
+	var running, calls atomic.Int32
+	f := func() (int, error) {
+		if running.Add(1) != 1 {
+			t.Error("f called while another call was running")
+		}
+		defer running.Add(-1)
+		if calls.Add(1) <= 10 {
+			return 0, errors.New("fail")
+		}
+		return 1, nil
+	}
+	var wg sync.WaitGroup
+	for i := 0; i < 100; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				if _, err := o.Do(f); err == nil {
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	if n := calls.Load(); n != 11 {
+		t.Errorf("f called %d times, want 10 failures and 1 success", n)
+	}
+}
+
+func BenchmarkOnce(b *testing.B) {
+	var o Once[int]
+	f := func() (int, error) { return 1, nil }
+	o.Do(f)
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			o.Do(f)
+		}
+	})
+}
+
+func BenchmarkSyncOnceValue(b *testing.B) {
+	f := sync.OnceValue(func() int { return 1 })
+	f()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			f()
+		}
+	})
+}
@@ -0,0 +1,3 @@
+module pagination
+
+go 1.21.3
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Page is one page of a larger collection. Number starts at 1.
+type Page[T any] struct {
+	Items  []T
+	Total  int
+	Number int
+	Size   int
+}
+
+// Pages returns how many pages of the given size are needed to hold total
+// items. An empty collection still has a single (empty) page.
+func Pages[T any](total, size int) int {
+	if size < 1 || total <= 0 {
+		return 1
+	}
+	return (total + size - 1) / size
+}
+
+// Paginate returns the requested page of items. The returned Items share
+// the backing array of items.
+func Paginate[T any](items []T, page, size int) (Page[T], error) {
+	if size < 1 {
+		return Page[T]{}, errors.New("page size must be at least 1")
+	}
+	if page < 1 {
+		return Page[T]{}, errors.New("page number must be at least 1")
+	}
+	if last := Pages[T](len(items), size); page > last {
+		return Page[T]{}, fmt.Errorf("page %d is out of range, there are %d pages", page, last)
+	}
+	start := (page - 1) * size
+	end := start + size
+	if end > len(items) {
+		end = len(items)
+	}
+	return Page[T]{
+		Items:  items[start:end],
+		Total:  len(items),
+		Number: page,
+		Size:   size,
+	}, nil
+}
+
+func main() {
+	teams := []string{"USA", "Canada", "Serbia", "Germany", "Spain"}
+	for i := 1; i <= Pages[string](len(teams), 2); i++ {
+		p, err := Paginate(teams, i, 2)
+		if err != nil {
+			fmt.Println(err)
+			continue
+		}
+		fmt.Println(p.Number, p.Items)
+	}
+
+	// The last page is partially filled
+	p, _ := Paginate(teams, 3, 2)
+	fmt.Println(p)
+
+	// A size larger than the collection puts everything on the first page
+	p, _ = Paginate(teams, 1, 10)
+	fmt.Println(p)
+
+	// Empty input gives a single empty page
+	empty, _ := Paginate([]string{}, 1, 10)
+	fmt.Println(empty)
+
+	_, err := Paginate(teams, 4, 2)
+	fmt.Println(err)
+}
@@ -0,0 +1,28 @@
+package main
+
+import "fmt"
+
+type Task struct {
+	Priority int
+	Name     string
+}
+
+func main() {
+	// Higher priorities run first
+	tasks := NewPriorityQueue(func(a, b Task) bool {
+		return a.Priority > b.Priority
+	})
+	tasks.Push(Task{Priority: 2, Name: "write report"})
+	tasks.Push(Task{Priority: 5, Name: "fix outage"})
+	tasks.Push(Task{Priority: 1, Name: "water plants"})
+	tasks.Push(Task{Priority: 3, Name: "review PR"})
+
+	next, _ := tasks.Peek()
+	fmt.Println("next up:", next.Name)
+	for tasks.Len() > 0 {
+		t, _ := tasks.Pop()
+		fmt.Printf("%d %s\n", t.Priority, t.Name)
+	}
+	_, ok := tasks.Pop()
+	fmt.Println(ok)
+}
@@ -0,0 +1,63 @@
+package main
+
+import "container/heap"
+
+// items implements heap.Interface for any type, ordered by less.
+type items[T any] struct {
+	vals []T
+	less func(a, b T) bool
+}
+
+func (h *items[T]) Len() int           { return len(h.vals) }
+func (h *items[T]) Less(i, j int) bool { return h.less(h.vals[i], h.vals[j]) }
+func (h *items[T]) Swap(i, j int)      { h.vals[i], h.vals[j] = h.vals[j], h.vals[i] }
+
+func (h *items[T]) Push(x any) {
+	h.vals = append(h.vals, x.(T))
+}
+
+func (h *items[T]) Pop() any {
+	v := h.vals[len(h.vals)-1]
+	var zero T
+	h.vals[len(h.vals)-1] = zero
+	h.vals = h.vals[:len(h.vals)-1]
+	return v
+}
+
+// PriorityQueue returns values in the order given by less: Pop returns a
+// value for which less(v, other) holds for every other value in the queue.
+type PriorityQueue[T any] struct {
+	h items[T]
+}
+
+func NewPriorityQueue[T any](less func(a, b T) bool) *PriorityQueue[T] {
+	return &PriorityQueue[T]{h: items[T]{less: less}}
+}
+
+func (pq *PriorityQueue[T]) Push(val T) {
+	heap.Push(&pq.h, val)
+}
+
+// Pop removes and returns the first value. It returns false if the queue is
+// empty.
+func (pq *PriorityQueue[T]) Pop() (T, bool) {
+	if pq.h.Len() == 0 {
+		var zero T
+		return zero, false
+	}
+	return heap.Pop(&pq.h).(T), true
+}
+
+// Peek returns the first value without removing it. It returns false if the
+// queue is empty.
+func (pq *PriorityQueue[T]) Peek() (T, bool) {
+	if pq.h.Len() == 0 {
+		var zero T
+		return zero, false
+	}
+	return pq.h.vals[0], true
+}
+
+func (pq *PriorityQueue[T]) Len() int {
+	return pq.h.Len()
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func byPriority(a, b Task) bool { return a.Priority > b.Priority }
+
+func TestPriorityQueueOrder(t *testing.T) {
+	tasks := NewPriorityQueue(byPriority)
+	for _, task := range []Task{
+		{Priority: 2, Name: "write report"},
+		{Priority: 5, Name: "fix outage"},
+		{Priority: 1, Name: "water plants"},
+		{Priority: 4, Name: "deploy"},
+		{Priority: 3, Name: "review PR"},
+	} {
+		tasks.Push(task)
+	}
+	want := []string{"fix outage", "deploy", "review PR", "write report", "water plants"}
+	if tasks.Len() != len(want) {
+		t.Fatalf("Len = %d, want %d", tasks.Len(), len(want))
+	}
+	for _, name := range want {
+		if next, ok := tasks.Peek(); !ok || next.Name != name {
+			t.Fatalf("Peek = %v, %t, want %s", next, ok, name)
+		}
+		if task, ok := tasks.Pop(); !ok || task.Name != name {
+			t.Fatalf("Pop = %v, %t, want %s", task, ok, name)
+		}
+	}
+	if _, ok := tasks.Pop(); ok {
+		t.Error("Pop on an empty queue succeeded")
+	}
+	if _, ok := tasks.Peek(); ok {
+		t.Error("Peek on an empty queue succeeded")
+	}
+}
+
+// TestPriorityQueueStrict pushes and pops at random, and checks every Pop
+// returns a task with no higher priority left in the queue.
+func TestPriorityQueueStrict(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	tasks := NewPriorityQueue(byPriority)
+	queued := map[int]int{}
+	for i := 0; i < 2000; i++ {
+		if r.Intn(3) > 0 || tasks.Len() == 0 {
+			p := r.Intn(50)
+			tasks.Push(Task{Priority: p})
+			queued[p]++
+			continue
+		}
+		task, ok := tasks.Pop()
+		if !ok {
+			t.Fatal("Pop on a non-empty queue failed")
+		}
+		queued[task.Priority]--
+		for p, n := range queued {
+			if n > 0 && p > task.Priority {
+				t.Fatalf("popped priority %d with %d still queued", task.Priority, p)
+			}
+		}
+	}
+	// Draining what's left never goes up in priority
+	last := 1 << 30
+	for tasks.Len() > 0 {
+		task, _ := tasks.Pop()
+		if task.Priority > last {
+			t.Fatalf("popped priority %d after %d", task.Priority, last)
+		}
+		last = task.Priority
+	}
+}
@@ -0,0 +1,38 @@
+package main
+
+import "fmt"
+
+// levelOrder visits the tree breadth first, starting from root. It's the same
+// walk as IntTree.LevelOrder in the binaryTree example, but over a tree kept
+// as a map from each node to its children.
+func levelOrder(children map[string][]string, root string) []string {
+	var out []string
+	q := NewQueue[string](len(children))
+	q.Enqueue(root)
+	for !q.IsEmpty() {
+		name, _ := q.Dequeue()
+		out = append(out, name)
+		for _, c := range children[name] {
+			q.Enqueue(c)
+		}
+	}
+	return out
+}
+
+func main() {
+	q := NewQueue[int](2)
+	for i := 1; i <= 5; i++ {
+		q.Enqueue(i)
+	}
+	v, ok := q.Dequeue()
+	fmt.Println(v, ok)
+	v, ok = q.Peek()
+	fmt.Println(v, ok, q.Len())
+
+	tree := map[string][]string{
+		"5":  {"3", "10"},
+		"3":  {"2", "4"},
+		"10": {"12"},
+	}
+	fmt.Println(levelOrder(tree, "5"))
+}
@@ -0,0 +1,69 @@
+package main
+
+// Queue is a first in, first out collection. Values are kept in a ring
+// buffer, so Dequeue doesn't have to shift the rest of the values down.
+type Queue[T any] struct {
+	vals []T
+	head int
+	n    int
+}
+
+// NewQueue returns a queue with room for capacity values before it needs to
+// grow.
+func NewQueue[T any](capacity int) *Queue[T] {
+	if capacity < 1 {
+		capacity = 1
+	}
+	return &Queue[T]{vals: make([]T, capacity)}
+}
+
+func (q *Queue[T]) Enqueue(val T) {
+	if q.n == len(q.vals) {
+		q.grow()
+	}
+	q.vals[(q.head+q.n)%len(q.vals)] = val
+	q.n++
+}
+
+// Dequeue removes and returns the value at the front of the queue. It returns
+// false if the queue is empty.
+func (q *Queue[T]) Dequeue() (T, bool) {
+	var zero T
+	if q.n == 0 {
+		return zero, false
+	}
+	val := q.vals[q.head]
+	q.vals[q.head] = zero
+	q.head = (q.head + 1) % len(q.vals)
+	q.n--
+	return val, true
+}
+
+// Peek returns the value at the front of the queue without removing it. It
+// returns false if the queue is empty.
+func (q *Queue[T]) Peek() (T, bool) {
+	if q.n == 0 {
+		var zero T
+		return zero, false
+	}
+	return q.vals[q.head], true
+}
+
+func (q *Queue[T]) Len() int {
+	return q.n
+}
+
+func (q *Queue[T]) IsEmpty() bool {
+	return q.n == 0
+}
+
+// grow doubles the buffer and moves the values to the start of it, so they
+// are in order again.
+func (q *Queue[T]) grow() {
+	vals := make([]T, max(2*len(q.vals), 1))
+	for i := 0; i < q.n; i++ {
+		vals[i] = q.vals[(q.head+i)%len(q.vals)]
+	}
+	q.vals = vals
+	q.head = 0
+}
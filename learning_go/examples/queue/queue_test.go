@@ -0,0 +1,130 @@
+package main
+
+import "testing"
+
+func TestQueueFIFO(t *testing.T) {
+	q := NewQueue[int](4)
+	if !q.IsEmpty() {
+		t.Fatal("new queue isn't empty")
+	}
+	if _, ok := q.Dequeue(); ok {
+		t.Error("Dequeue on an empty queue succeeded")
+	}
+	if _, ok := q.Peek(); ok {
+		t.Error("Peek on an empty queue succeeded")
+	}
+	for i := 1; i <= 3; i++ {
+		q.Enqueue(i)
+	}
+	if v, ok := q.Peek(); !ok || v != 1 {
+		t.Errorf("Peek = %d, %t, want 1, true", v, ok)
+	}
+	if q.Len() != 3 {
+		t.Errorf("Len = %d, want 3", q.Len())
+	}
+	for want := 1; want <= 3; want++ {
+		if v, ok := q.Dequeue(); !ok || v != want {
+			t.Errorf("Dequeue = %d, %t, want %d, true", v, ok, want)
+		}
+	}
+	if !q.IsEmpty() {
+		t.Errorf("queue has %d values left, want none", q.Len())
+	}
+}
+
+// TestQueueWraparound moves the head around the buffer several times
+// without growing it.
+func TestQueueWraparound(t *testing.T) {
+	q := NewQueue[int](4)
+	next, want := 0, 0
+	for round := 0; round < 10; round++ {
+		for q.Len() < 3 {
+			q.Enqueue(next)
+			next++
+		}
+		for q.Len() > 1 {
+			if v, _ := q.Dequeue(); v != want {
+				t.Fatalf("round %d: Dequeue = %d, want %d", round, v, want)
+			}
+			want++
+		}
+	}
+	if len(q.vals) != 4 {
+		t.Errorf("buffer grew to %d, want 4", len(q.vals))
+	}
+}
+
+// TestQueueGrow fills a queue whose values wrap around the end of the
+// buffer, so grow has to put them back in order.
+func TestQueueGrow(t *testing.T) {
+	for _, capacity := range []int{0, 1, 4} {
+		q := NewQueue[int](capacity)
+		// Move the head partway along first
+		for i := 0; i < 3; i++ {
+			q.Enqueue(-1)
+			q.Dequeue()
+		}
+		for i := 0; i < 100; i++ {
+			q.Enqueue(i)
+		}
+		if q.Len() != 100 {
+			t.Fatalf("capacity %d: Len = %d, want 100", capacity, q.Len())
+		}
+		for want := 0; want < 100; want++ {
+			if v, ok := q.Dequeue(); !ok || v != want {
+				t.Fatalf("capacity %d: Dequeue = %d, %t, want %d, true", capacity, v, ok, want)
+			}
+		}
+	}
+}
+
+// sliceQueue is the naive queue the ring buffer replaces: Dequeue shifts
+// the rest of the values down.
+type sliceQueue[T any] struct {
+	vals []T
+}
+
+func (q *sliceQueue[T]) Enqueue(val T) {
+	q.vals = append(q.vals, val)
+}
+
+func (q *sliceQueue[T]) Dequeue() (T, bool) {
+	var zero T
+	if len(q.vals) == 0 {
+		return zero, false
+	}
+	val := q.vals[0]
+	copy(q.vals, q.vals[1:])
+	q.vals[len(q.vals)-1] = zero
+	q.vals = q.vals[:len(q.vals)-1]
+	return val, true
+}
+
+const benchQueueLen = 100_000
+
+// The benchmarks keep 100K values queued, and each op dequeues one and
+// enqueues another.
+
+func BenchmarkQueue(b *testing.B) {
+	q := NewQueue[int](benchQueueLen)
+	for i := 0; i < benchQueueLen; i++ {
+		q.Enqueue(i)
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		v, _ := q.Dequeue()
+		q.Enqueue(v)
+	}
+}
+
+func BenchmarkSliceQueue(b *testing.B) {
+	q := &sliceQueue[int]{vals: make([]int, 0, benchQueueLen)}
+	for i := 0; i < benchQueueLen; i++ {
+		q.Enqueue(i)
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		v, _ := q.Dequeue()
+		q.Enqueue(v)
+	}
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// fileLen returns the number of bytes in the file, like fileLen in exercise
+// 05/ex2.
+func fileLen(file string) (int, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+	n, err := io.Copy(io.Discard, f)
+	return int(n), err
+}
+
+func main() {
+	dir, err := os.MkdirTemp("", "retry")
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	defer os.RemoveAll(dir)
+	name := filepath.Join(dir, "report.txt")
+
+	// Another process writes the file a little while after we start looking
+	// for it
+	go func() {
+		time.Sleep(25 * time.Millisecond)
+		os.WriteFile(name, []byte("quarterly numbers\n"), 0644)
+	}()
+
+	calls := 0
+	var size int
+	err = Retry(5, 10*time.Millisecond, func() error {
+		calls++
+		size, err = fileLen(name)
+		return err
+	}, WithJitter(20))
+	fmt.Println(size, err, calls > 1)
+
+	// This file never appears, so every attempt fails
+	calls = 0
+	err = Retry(3, time.Millisecond, func() error {
+		calls++
+		_, err := fileLen(filepath.Join(dir, "missing.txt"))
+		return err
+	})
+	fmt.Println(calls, os.IsNotExist(err))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	err = RetryWithContext(ctx, 10, 50*time.Millisecond, func() error {
+		_, err := fileLen(filepath.Join(dir, "missing.txt"))
+		return err
+	})
+	fmt.Println(err)
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"time"
+)
+
+// maxBackoff caps the wait between two attempts.
+const maxBackoff = 30 * time.Second
+
+type retryOptions struct {
+	jitter float64
+}
+
+// RetryOption configures Retry and RetryWithContext.
+type RetryOption func(*retryOptions)
+
+// WithJitter randomly moves each wait by up to maxJitterPercent percent of
+// its length in either direction, so clients that failed together don't all
+// retry at the same moment.
+func WithJitter(maxJitterPercent float64) RetryOption {
+	return func(o *retryOptions) {
+		o.jitter = min(max(maxJitterPercent, 0), 100) / 100
+	}
+}
+
+// Retry calls fn until it succeeds or has been called attempts times, and
+// returns the last error. It waits backoff before the first retry and doubles
+// the wait each time after that, up to 30 seconds. fn is always called at
+// least once.
+func Retry(attempts int, backoff time.Duration, fn func() error, opts ...RetryOption) error {
+	return RetryWithContext(context.Background(), attempts, backoff, fn, opts...)
+}
+
+// RetryWithContext is like Retry but gives up as soon as ctx is done, without
+// waiting out the current backoff. The returned error then wraps both the
+// context's error and the last error from fn.
+func RetryWithContext(ctx context.Context, attempts int, backoff time.Duration, fn func() error, opts ...RetryOption) error {
+	var o retryOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	attempts = max(attempts, 1)
+
+	var err error
+	for attempt := 0; attempt < attempts; attempt++ {
+		if err = fn(); err == nil {
+			return nil
+		}
+		if attempt == attempts-1 {
+			break
+		}
+		timer := time.NewTimer(delay(backoff, attempt, o.jitter))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return fmt.Errorf("%w, last error: %w", ctx.Err(), err)
+		case <-timer.C:
+		}
+	}
+	return err
+}
+
+// delay returns how long to wait after the given failed attempt, counting
+// from zero.
+func delay(backoff time.Duration, attempt int, jitter float64) time.Duration {
+	d := backoff
+	for i := 0; i < attempt && d < maxBackoff; i++ {
+		d *= 2
+	}
+	d = min(d, maxBackoff)
+	if jitter > 0 {
+		d += time.Duration((rand.Float64()*2 - 1) * jitter * float64(d))
+	}
+	return max(d, 0)
+}
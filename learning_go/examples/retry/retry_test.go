@@ -0,0 +1,111 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+// failing returns an fn that fails with its call number until it has been
+// called succeedOn times, and never succeeds if succeedOn is 0.
+func failing(succeedOn int) (fn func() error, calls *int) {
+	calls = new(int)
+	return func() error {
+		*calls++
+		if *calls == succeedOn {
+			return nil
+		}
+		return fmt.Errorf("call %d failed", *calls)
+	}, calls
+}
+
+func TestRetryAttempts(t *testing.T) {
+	for _, attempts := range []int{1, 2, 5} {
+		fn, calls := failing(0)
+		err := Retry(attempts, time.Millisecond, fn)
+		if *calls != attempts {
+			t.Errorf("Retry(%d): fn called %d times", attempts, *calls)
+		}
+		if want := fmt.Sprintf("call %d failed", attempts); err == nil || err.Error() != want {
+			t.Errorf("Retry(%d) = %v, want the last error %q", attempts, err, want)
+		}
+	}
+	// fn is always called at least once
+	for _, attempts := range []int{0, -1} {
+		fn, calls := failing(0)
+		if err := Retry(attempts, time.Millisecond, fn); err == nil || *calls != 1 {
+			t.Errorf("Retry(%d): fn called %d times, err %v, want 1 call and an error", attempts, *calls, err)
+		}
+	}
+}
+
+func TestRetrySucceeds(t *testing.T) {
+	fn, calls := failing(3)
+	if err := Retry(5, time.Millisecond, fn); err != nil {
+		t.Fatal(err)
+	}
+	if *calls != 3 {
+		t.Errorf("fn called %d times, want 3", *calls)
+	}
+}
+
+func TestRetryWithContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	fn, calls := failing(0)
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		cancel()
+	}()
+	start := time.Now()
+	// The backoff is far longer than the test, so it only ends if the
+	// cancellation cuts it short
+	err := RetryWithContext(ctx, 5, time.Hour, fn)
+	if time.Since(start) > 5*time.Second {
+		t.Fatal("RetryWithContext waited out the backoff")
+	}
+	if !errors.Is(err, context.Canceled) || err.Error() != "context canceled, last error: call 1 failed" {
+		t.Errorf("RetryWithContext = %v, want context.Canceled and the last error", err)
+	}
+	if *calls != 1 {
+		t.Errorf("fn called %d times, want 1", *calls)
+	}
+}
+
+func TestDelay(t *testing.T) {
+	want := []time.Duration{
+		100 * time.Millisecond,
+		200 * time.Millisecond,
+		400 * time.Millisecond,
+		800 * time.Millisecond,
+	}
+	for attempt, w := range want {
+		if got := delay(100*time.Millisecond, attempt, 0); got != w {
+			t.Errorf("delay(attempt %d) = %v, want %v", attempt, got, w)
+		}
+	}
+	for _, attempt := range []int{9, 10, 100, 1000} {
+		if got := delay(100*time.Millisecond, attempt, 0); got != maxBackoff {
+			t.Errorf("delay(attempt %d) = %v, want the %v cap", attempt, got, maxBackoff)
+		}
+	}
+	for i := 0; i < 1000; i++ {
+		if got := delay(time.Second, 0, 0.1); got < 900*time.Millisecond || got > 1100*time.Millisecond {
+			t.Fatalf("delay with 10%% jitter = %v, want 0.9s to 1.1s", got)
+		}
+		if got := delay(time.Second, 0, 1); got < 0 || got > 2*time.Second {
+			t.Fatalf("delay with 100%% jitter = %v, want 0s to 2s", got)
+		}
+	}
+}
+
+func TestWithJitter(t *testing.T) {
+	for percent, want := range map[float64]float64{10: 0.1, -5: 0, 250: 1} {
+		var o retryOptions
+		WithJitter(percent)(&o)
+		if o.jitter != want {
+			t.Errorf("WithJitter(%v) set %v, want %v", percent, o.jitter, want)
+		}
+	}
+}
@@ -0,0 +1,3 @@
+module shmem
+
+go 1.21.3
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+)
+
+// standing is the slice of League state the scorer shares with the display.
+type standing struct {
+	Team string
+	Wins int
+}
+
+func main() {
+	name := fmt.Sprintf("league-%d", os.Getpid())
+	scorer, err := Create(name, 4096)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	defer Remove(name)
+	defer scorer.Close()
+
+	// The display maps the region separately, just as a second process would
+	display, err := Open(name)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	defer display.Close()
+
+	results := [][]standing{
+		{{"Canada", 1}, {"USA", 0}},
+		{{"Canada", 1}, {"Serbia", 1}, {"USA", 0}},
+		{{"Serbia", 2}, {"Canada", 1}, {"USA", 0}},
+	}
+	// The channels only say when there's something new, the table itself
+	// goes through the shared memory
+	updated := make(chan bool)
+	shown := make(chan bool)
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		defer close(updated)
+		for _, table := range results {
+			data, _ := json.Marshal(table)
+			if err := scorer.Write(data); err != nil {
+				fmt.Println(err)
+				return
+			}
+			updated <- true
+			<-shown
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		buf := make([]byte, display.Size())
+		for range updated {
+			var table []standing
+			n, err := display.Read(buf)
+			if err == nil {
+				err = json.Unmarshal(buf[:n], &table)
+			}
+			if err != nil {
+				fmt.Println(err)
+			} else {
+				fmt.Println(table)
+			}
+			shown <- true
+		}
+	}()
+	wg.Wait()
+
+	fmt.Println(scorer.Write(make([]byte, 5000)))
+	_, err = display.Read(make([]byte, 4))
+	fmt.Println(err)
+}
@@ -0,0 +1,177 @@
+//go:build linux
+
+package main
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync/atomic"
+	"syscall"
+	"unsafe"
+)
+
+// shmDir is where Linux keeps POSIX shared memory objects, shm_open is just
+// an open of a file in here.
+const shmDir = "/dev/shm"
+
+// headerSize is the bytes at the start of a region used for the lock word
+// and the length of the data stored after it.
+const headerSize = 8
+
+// Region is a named block of shared memory that other processes can map with
+// Open. Every mapping of the same name sees the same bytes. Write and Read
+// take a spin lock stored in the region itself, so they're safe to use from
+// any number of processes and goroutines at once.
+type Region struct {
+	name string
+	mem  []byte
+}
+
+func shmPath(name string) (string, error) {
+	name = strings.TrimPrefix(name, "/")
+	if name == "" || strings.Contains(name, "/") {
+		return "", fmt.Errorf("invalid shared memory name %q", name)
+	}
+	return filepath.Join(shmDir, name), nil
+}
+
+// Create makes a new shared memory object that can hold size bytes of data
+// and maps it. It fails if name already exists.
+func Create(name string, size int) (*Region, error) {
+	if size <= 0 {
+		return nil, fmt.Errorf("size must be positive, got %d", size)
+	}
+	path, err := shmPath(name)
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE|os.O_EXCL, 0600)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	// The new file is all zeros, which is an unlocked region holding no data
+	if err := f.Truncate(int64(headerSize + size)); err != nil {
+		os.Remove(path)
+		return nil, err
+	}
+	r, err := mapRegion(name, f, headerSize+size)
+	if err != nil {
+		os.Remove(path)
+	}
+	return r, err
+}
+
+// Open maps an existing shared memory object made by Create.
+func Open(name string) (*Region, error) {
+	path, err := shmPath(name)
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.OpenFile(path, os.O_RDWR, 0)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	fi, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+	if fi.Size() <= headerSize {
+		return nil, fmt.Errorf("%s is too small to be a shared memory region", name)
+	}
+	return mapRegion(name, f, int(fi.Size()))
+}
+
+func mapRegion(name string, f *os.File, length int) (*Region, error) {
+	mem, err := syscall.Mmap(int(f.Fd()), 0, length, syscall.PROT_READ|syscall.PROT_WRITE, syscall.MAP_SHARED)
+	if err != nil {
+		return nil, fmt.Errorf("mmap %s: %w", name, err)
+	}
+	return &Region{name: name, mem: mem}, nil
+}
+
+// Size returns the largest amount of data the region can hold.
+func (r *Region) Size() int {
+	return len(r.mem) - headerSize
+}
+
+// lockWord is the first four bytes of the mapping. mmap returns page aligned
+// memory so it's safe to use atomically.
+func (r *Region) lockWord() *uint32 {
+	return (*uint32)(unsafe.Pointer(&r.mem[0]))
+}
+
+func (r *Region) lock() {
+	for !atomic.CompareAndSwapUint32(r.lockWord(), 0, 1) {
+		runtime.Gosched()
+	}
+}
+
+func (r *Region) unlock() {
+	atomic.StoreUint32(r.lockWord(), 0)
+}
+
+// Write replaces the region's contents with data.
+func (r *Region) Write(data []byte) error {
+	if r.mem == nil {
+		return errors.New("region is closed")
+	}
+	if len(data) > r.Size() {
+		return fmt.Errorf("%d bytes doesn't fit in a %d byte region", len(data), r.Size())
+	}
+	r.lock()
+	defer r.unlock()
+	copy(r.mem[headerSize:], data)
+	binary.LittleEndian.PutUint32(r.mem[4:], uint32(len(data)))
+	return nil
+}
+
+// Read copies the region's contents into buf and returns how many bytes
+// were copied. If buf is too small it's filled and io.ErrShortBuffer is
+// returned. The stored length comes from shared memory, so any process with
+// the region mapped can corrupt it, and Read returns an error if it's bigger
+// than the region.
+func (r *Region) Read(buf []byte) (int, error) {
+	if r.mem == nil {
+		return 0, errors.New("region is closed")
+	}
+	r.lock()
+	defer r.unlock()
+	length := int(binary.LittleEndian.Uint32(r.mem[4:]))
+	if length > r.Size() {
+		return 0, fmt.Errorf("stored length %d is bigger than the %d byte region", length, r.Size())
+	}
+	n := copy(buf, r.mem[headerSize:headerSize+length])
+	if n < length {
+		return n, io.ErrShortBuffer
+	}
+	return n, nil
+}
+
+// Close unmaps the region. The shared memory object itself stays around for
+// other processes until Remove is called.
+func (r *Region) Close() error {
+	if r.mem == nil {
+		return nil
+	}
+	err := syscall.Munmap(r.mem)
+	r.mem = nil
+	return err
+}
+
+// Remove deletes the named shared memory object. Processes that already
+// have it mapped can keep using it.
+func Remove(name string) error {
+	path, err := shmPath(name)
+	if err != nil {
+		return err
+	}
+	return os.Remove(path)
+}
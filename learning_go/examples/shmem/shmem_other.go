@@ -0,0 +1,23 @@
+//go:build !linux
+
+package main
+
+import "errors"
+
+// Region is only implemented on Linux, where shared memory objects live in
+// /dev/shm.
+type Region struct{}
+
+func Create(name string, size int) (*Region, error) { return nil, errors.ErrUnsupported }
+
+func Open(name string) (*Region, error) { return nil, errors.ErrUnsupported }
+
+func Remove(name string) error { return errors.ErrUnsupported }
+
+func (r *Region) Size() int { return 0 }
+
+func (r *Region) Write(data []byte) error { return errors.ErrUnsupported }
+
+func (r *Region) Read(buf []byte) (int, error) { return 0, errors.ErrUnsupported }
+
+func (r *Region) Close() error { return nil }
@@ -0,0 +1,139 @@
+//go:build linux
+
+package main
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// testRegion creates a region for t and maps it a second time, the way
+// another process would.
+func testRegion(t *testing.T, size int) (w, r *Region) {
+	t.Helper()
+	name := fmt.Sprintf("shmem-test-%d-%s", os.Getpid(), t.Name())
+	w, err := Create(name, size)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		w.Close()
+		Remove(name)
+	})
+	r, err = Open(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { r.Close() })
+	return w, r
+}
+
+func TestRegion(t *testing.T) {
+	w, r := testRegion(t, 64)
+	if r.Size() != 64 {
+		t.Errorf("Size() = %d, want 64", r.Size())
+	}
+	buf := make([]byte, 64)
+	if n, err := r.Read(buf); n != 0 || err != nil {
+		t.Errorf("new region: Read() = %d, %v, want 0, nil", n, err)
+	}
+	for _, data := range []string{"hello", "hi", strings.Repeat("x", 64), ""} {
+		if err := w.Write([]byte(data)); err != nil {
+			t.Fatal(err)
+		}
+		n, err := r.Read(buf)
+		if err != nil || string(buf[:n]) != data {
+			t.Errorf("Read() = %q, %v, want %q", buf[:n], err, data)
+		}
+	}
+}
+
+func TestRegionErrors(t *testing.T) {
+	w, r := testRegion(t, 16)
+	if err := w.Write(make([]byte, 17)); err == nil {
+		t.Error("Write of 17 bytes to a 16 byte region worked")
+	}
+	if err := w.Write([]byte("0123456789")); err != nil {
+		t.Fatal(err)
+	}
+	buf := make([]byte, 4)
+	if n, err := r.Read(buf); n != 4 || !errors.Is(err, io.ErrShortBuffer) || string(buf) != "0123" {
+		t.Errorf("short Read() = %d %q, %v, want 4 \"0123\", io.ErrShortBuffer", n, buf, err)
+	}
+	if _, err := Create(fmt.Sprintf("shmem-test-%d-%s", os.Getpid(), t.Name()), 16); err == nil {
+		t.Error("Create of an existing name worked")
+	}
+	for _, name := range []string{"", "/", "a/b"} {
+		if _, err := Create(name, 16); err == nil {
+			t.Errorf("Create(%q) worked", name)
+		}
+	}
+	if _, err := Create("shmem-test-size", 0); err == nil {
+		t.Error("Create with size 0 worked")
+	}
+
+	r.Close()
+	if _, err := r.Read(buf); err == nil {
+		t.Error("Read after Close worked")
+	}
+}
+
+// TestReadCorruptLength stores a length bigger than the region, as a buggy
+// or hostile process could.
+func TestReadCorruptLength(t *testing.T) {
+	w, r := testRegion(t, 16)
+	for _, length := range []uint32{17, 1 << 31, 1<<32 - 1} {
+		binary.LittleEndian.PutUint32(w.mem[4:], length)
+		if _, err := r.Read(make([]byte, 64)); err == nil {
+			t.Errorf("Read with a stored length of %d worked", length)
+		}
+	}
+}
+
+// TestRegionConcurrent has a writer and a reader on separate mappings, as a
+// scorer and display process would be. Every write fills the data with one
+// byte value and sets the length to match it, so a torn read shows up as a
+// mix of values or the wrong length.
+func TestRegionConcurrent(t *testing.T) {
+	w, r := testRegion(t, 256)
+	const writes = 10000
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < writes; i++ {
+			v := byte(i%255 + 1)
+			if err := w.Write(bytes.Repeat([]byte{v}, int(v))); err != nil {
+				t.Error(err)
+				return
+			}
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		buf := make([]byte, 256)
+		for i := 0; i < writes; i++ {
+			n, err := r.Read(buf)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			if n == 0 {
+				continue
+			}
+			v := buf[0]
+			if n != int(v) || bytes.Count(buf[:n], []byte{v}) != n {
+				t.Errorf("torn read: %d bytes starting with %d", n, v)
+				return
+			}
+		}
+	}()
+	wg.Wait()
+}
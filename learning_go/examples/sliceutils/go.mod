@@ -0,0 +1,3 @@
+module sliceutils
+
+go 1.21.3
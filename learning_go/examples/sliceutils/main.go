@@ -0,0 +1,63 @@
+package main
+
+import "fmt"
+
+// Person is a cut down copy of the Person type from exercise 06.
+type Person struct {
+	FirstName string
+	LastName  string
+	Age       int
+}
+
+func main() {
+	people := []Person{
+		{"Fred", "Fredson", 32},
+		{"Tim", "Timson", 12},
+		{"Mary", "Maryson", 45},
+		{"Jo", "Joson", 17},
+	}
+	isAdult := func(p Person) bool { return p.Age >= 18 }
+
+	adults := Filter(people, isAdult)
+	names := Map(adults, func(p Person) string {
+		return p.FirstName + " " + p.LastName
+	})
+	fmt.Println(names)
+	total := Reduce(people, 0, func(acc int, p Person) int {
+		return acc + p.Age
+	})
+	fmt.Println(total)
+	fmt.Println(Any(people, isAdult), All(people, isAdult))
+
+	var none []Person
+	fmt.Println(Filter(none, isAdult) == nil, Map(none, isAdult) == nil, Reduce(none, 0, func(acc int, p Person) int { return acc + 1 }))
+
+	chunks, err := Chunk([]int{1, 2, 3, 4, 5, 6, 7}, 3)
+	fmt.Println(chunks, err)
+	fmt.Println(Flatten(chunks))
+	_, err = Chunk([]int{1}, 0)
+	fmt.Println(err)
+	for _, p := range Zip(names, []int{1, 2, 3}) {
+		fmt.Println(p.B, p.A)
+	}
+
+	fmt.Println(Unique([]int{3, 1, 3, 2, 1}))
+
+	// The teams from the league in exercise 07
+	teams := []string{"USA", "Canada", "Serbia", "Germany", "Spain", "Chile"}
+	byLetter := GroupBy(teams, func(name string) byte { return name[0] })
+	for _, letter := range []byte("CGSU") {
+		fmt.Printf("%c: %v\n", letter, byLetter[letter])
+	}
+	adults, minors := Partition(people, isAdult)
+	fmt.Println(len(adults), len(minors))
+
+	windows, err := SlidingWindow([]int{1, 2, 3, 4}, 3)
+	fmt.Println(windows, err)
+	// Points scored by one team over its last few games
+	scores := []float64{70, 85, 60, 100, 95}
+	avg, err := RollingAverage(scores, 3)
+	fmt.Println(avg, err)
+	_, err = SlidingWindow([]int{}, 1)
+	fmt.Println(err)
+}
@@ -0,0 +1,173 @@
+package main
+
+import "fmt"
+
+// Filter returns the elements of s for which predicate returns true, in
+// order. It returns nil if none match.
+func Filter[T any](s []T, predicate func(T) bool) []T {
+	var r []T
+	for _, v := range s {
+		if predicate(v) {
+			r = append(r, v)
+		}
+	}
+	return r
+}
+
+// Map returns a slice holding f applied to each element of s. A nil slice
+// maps to nil.
+func Map[T, U any](s []T, f func(T) U) []U {
+	if s == nil {
+		return nil
+	}
+	r := make([]U, len(s))
+	for i, v := range s {
+		r[i] = f(v)
+	}
+	return r
+}
+
+// Reduce folds s into a single value, starting from initial and calling f
+// with the running value and each element in turn.
+func Reduce[T, U any](s []T, initial U, f func(U, T) U) U {
+	r := initial
+	for _, v := range s {
+		r = f(r, v)
+	}
+	return r
+}
+
+// Any reports whether predicate returns true for at least one element of s.
+// It stops at the first match.
+func Any[T any](s []T, predicate func(T) bool) bool {
+	for _, v := range s {
+		if predicate(v) {
+			return true
+		}
+	}
+	return false
+}
+
+// All reports whether predicate returns true for every element of s. It's
+// true for an empty slice.
+func All[T any](s []T, predicate func(T) bool) bool {
+	for _, v := range s {
+		if !predicate(v) {
+			return false
+		}
+	}
+	return true
+}
+
+// Chunk splits s into consecutive sub-slices of size elements, the last one
+// holding whatever is left over. The chunks share s's backing array.
+func Chunk[T any](s []T, size int) ([][]T, error) {
+	if size <= 0 {
+		return nil, fmt.Errorf("invalid chunk size %d: must be positive", size)
+	}
+	var r [][]T
+	for len(s) > 0 {
+		n := min(size, len(s))
+		// Cap each chunk so appending to it can't overwrite the next one
+		r = append(r, s[:n:n])
+		s = s[n:]
+	}
+	return r, nil
+}
+
+// Flatten concatenates the slices in s into a single new slice.
+func Flatten[T any](s [][]T) []T {
+	n := 0
+	for _, inner := range s {
+		n += len(inner)
+	}
+	r := make([]T, 0, n)
+	for _, inner := range s {
+		r = append(r, inner...)
+	}
+	return r
+}
+
+// Pair holds one element from each of the slices passed to Zip.
+type Pair[A, B any] struct {
+	A A
+	B B
+}
+
+// Zip pairs up the elements of a and b by index. Elements past the end of
+// the shorter slice are dropped.
+func Zip[A, B any](a []A, b []B) []Pair[A, B] {
+	n := min(len(a), len(b))
+	r := make([]Pair[A, B], n)
+	for i := 0; i < n; i++ {
+		r[i] = Pair[A, B]{A: a[i], B: b[i]}
+	}
+	return r
+}
+
+// Unique returns the elements of s with duplicates removed, keeping the first
+// occurrence of each. A nil slice gives nil.
+func Unique[T comparable](s []T) []T {
+	if s == nil {
+		return nil
+	}
+	seen := make(map[T]struct{}, len(s))
+	r := make([]T, 0, len(s))
+	for _, v := range s {
+		if _, ok := seen[v]; ok {
+			continue
+		}
+		seen[v] = struct{}{}
+		r = append(r, v)
+	}
+	return r
+}
+
+// GroupBy puts the elements of s into groups by the result of key. Each group
+// keeps the order the elements had in s.
+func GroupBy[T any, K comparable](s []T, key func(T) K) map[K][]T {
+	r := map[K][]T{}
+	for _, v := range s {
+		k := key(v)
+		r[k] = append(r[k], v)
+	}
+	return r
+}
+
+// Partition splits s into the elements for which pred returns true and the
+// ones for which it returns false, both in order.
+func Partition[T any](s []T, pred func(T) bool) ([]T, []T) {
+	var yes, no []T
+	for _, v := range s {
+		if pred(v) {
+			yes = append(yes, v)
+		} else {
+			no = append(no, v)
+		}
+	}
+	return yes, no
+}
+
+// SlidingWindow returns every run of size consecutive elements of s, in
+// order. The windows overlap and share s's backing array.
+func SlidingWindow[T any](s []T, size int) ([][]T, error) {
+	if size <= 0 || size > len(s) {
+		return nil, fmt.Errorf("invalid window size %d for %d elements", size, len(s))
+	}
+	r := make([][]T, 0, len(s)-size+1)
+	for i := 0; i+size <= len(s); i++ {
+		r = append(r, s[i:i+size:i+size])
+	}
+	return r, nil
+}
+
+// RollingAverage returns the mean of each window of the given size over s.
+func RollingAverage(s []float64, window int) ([]float64, error) {
+	windows, err := SlidingWindow(s, window)
+	if err != nil {
+		return nil, err
+	}
+	return Map(windows, func(w []float64) float64 {
+		return Reduce(w, 0.0, func(acc, v float64) float64 { return acc + v }) / float64(len(w))
+	}), nil
+}
@@ -0,0 +1,107 @@
+package main
+
+import (
+	"slices"
+	"strconv"
+	"testing"
+)
+
+func isEven(n int) bool { return n%2 == 0 }
+
+func TestFilter(t *testing.T) {
+	tests := []struct {
+		in, want []int
+	}{
+		{nil, nil},
+		{[]int{}, nil},
+		{[]int{1, 3}, nil},
+		{[]int{1, 2, 3, 4, 6}, []int{2, 4, 6}},
+	}
+	for _, tt := range tests {
+		got := Filter(tt.in, isEven)
+		if !slices.Equal(got, tt.want) || (tt.want == nil && got != nil) {
+			t.Errorf("Filter(%v) = %#v, want %#v", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestMap(t *testing.T) {
+	if got := Map([]int(nil), strconv.Itoa); got != nil {
+		t.Errorf("Map(nil) = %#v, want nil", got)
+	}
+	if got := Map([]int{}, strconv.Itoa); got == nil || len(got) != 0 {
+		t.Errorf("Map([]) = %#v, want an empty slice", got)
+	}
+	if got := Map([]int{1, 22, 333}, strconv.Itoa); !slices.Equal(got, []string{"1", "22", "333"}) {
+		t.Errorf("Map = %q", got)
+	}
+}
+
+func TestReduce(t *testing.T) {
+	sum := func(acc, v int) int { return acc + v }
+	if got := Reduce(nil, 10, sum); got != 10 {
+		t.Errorf("Reduce(nil) = %d, want the initial value 10", got)
+	}
+	if got := Reduce([]int{1, 2, 3}, 10, sum); got != 16 {
+		t.Errorf("Reduce sum = %d, want 16", got)
+	}
+	// The result type can differ from the element type, and the order is
+	// kept
+	concat := func(acc string, v int) string { return acc + strconv.Itoa(v) }
+	if got := Reduce([]int{1, 2, 3}, ">", concat); got != ">123" {
+		t.Errorf("Reduce concat = %q, want >123", got)
+	}
+}
+
+func TestAnyAll(t *testing.T) {
+	tests := []struct {
+		in       []int
+		any, all bool
+	}{
+		{nil, false, true},
+		{[]int{1, 3}, false, false},
+		{[]int{1, 2}, true, false},
+		{[]int{2, 4}, true, true},
+	}
+	for _, tt := range tests {
+		if got := Any(tt.in, isEven); got != tt.any {
+			t.Errorf("Any(%v) = %t, want %t", tt.in, got, tt.any)
+		}
+		if got := All(tt.in, isEven); got != tt.all {
+			t.Errorf("All(%v) = %t, want %t", tt.in, got, tt.all)
+		}
+	}
+	// Any stops at the first match
+	calls := 0
+	Any([]int{2, 4, 6}, func(n int) bool { calls++; return isEven(n) })
+	if calls != 1 {
+		t.Errorf("Any called the predicate %d times, want 1", calls)
+	}
+}
+
+var benchInts = func() []int {
+	s := make([]int, 10_000)
+	for i := range s {
+		s[i] = i
+	}
+	return s
+}()
+
+// The Map and loop benchmarks do the same work, so the difference is the
+// cost of calling a closure per element.
+
+func BenchmarkMap(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		_ = Map(benchInts, func(n int) int { return n * 2 })
+	}
+}
+
+func BenchmarkLoop(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		r := make([]int, len(benchInts))
+		for j, n := range benchInts {
+			r[j] = n * 2
+		}
+		_ = r
+	}
+}
@@ -2,31 +2,18 @@ package main
 
 import "fmt"
 
-type Stack[T comparable] struct {
-	vals []T
-}
-
-func (s *Stack[T]) Push(val T) {
-	s.vals = append(s.vals, val)
-}
-
-func (s *Stack[T]) Pop() (T, bool) {
-	if len(s.vals) == 0 {
-		var zero T
-		return zero, false
+// reverse uses a stack to return the values of in back to front.
+func reverse(in []int) []int {
+	s := NewStack[int]()
+	for _, v := range in {
+		s.Push(v)
 	}
-	top := s.vals[len(s.vals)-1]
-	s.vals = s.vals[:len(s.vals)-1]
-	return top, true
-}
-
-func (s Stack[T]) Contains(val T) bool {
-	for _, v := range s.vals {
-		if v == val {
-			return true
-		}
+	out := make([]int, 0, s.Len())
+	for !s.IsEmpty() {
+		v, _ := s.Pop()
+		out = append(out, v)
 	}
-	return false
+	return out
 }
 
 func main() {
@@ -36,6 +23,13 @@ func main() {
 	s.Push(30)
 	v, ok := s.Pop()
 	fmt.Println(v, ok)
-	fmt.Println(s.Contains(10))
-	fmt.Println(s.Contains(30))
+	fmt.Println(Contains(&s, 10))
+	fmt.Println(Contains(&s, 30))
+	top, ok := s.Peek()
+	fmt.Println(top, ok, s.Len())
+	s.Clear()
+	_, ok = s.Pop()
+	fmt.Println(s.IsEmpty(), ok)
+
+	fmt.Println(reverse([]int{1, 2, 3, 4, 5}))
 }
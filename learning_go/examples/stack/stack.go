@@ -0,0 +1,62 @@
+package main
+
+// Stack is a last in, first out collection backed by a slice.
+type Stack[T any] struct {
+	vals []T
+}
+
+func NewStack[T any]() *Stack[T] {
+	return &Stack[T]{}
+}
+
+func (s *Stack[T]) Push(val T) {
+	s.vals = append(s.vals, val)
+}
+
+// Pop removes and returns the top value. It returns false if the stack is
+// empty.
+func (s *Stack[T]) Pop() (T, bool) {
+	if len(s.vals) == 0 {
+		var zero T
+		return zero, false
+	}
+	top := s.vals[len(s.vals)-1]
+	// Clear the slot so the popped value can be garbage collected
+	var zero T
+	s.vals[len(s.vals)-1] = zero
+	s.vals = s.vals[:len(s.vals)-1]
+	return top, true
+}
+
+// Peek returns the top value without removing it. It returns false if the
+// stack is empty.
+func (s *Stack[T]) Peek() (T, bool) {
+	if len(s.vals) == 0 {
+		var zero T
+		return zero, false
+	}
+	return s.vals[len(s.vals)-1], true
+}
+
+func (s *Stack[T]) Len() int {
+	return len(s.vals)
+}
+
+func (s *Stack[T]) IsEmpty() bool {
+	return len(s.vals) == 0
+}
+
+func (s *Stack[T]) Clear() {
+	s.vals = nil
+}
+
+// Contains reports whether val is anywhere in the stack. It's a function
+// rather than a method because it needs T to be comparable.
+func Contains[T comparable](s *Stack[T], val T) bool {
+	for _, v := range s.vals {
+		if v == val {
+			return true
+		}
+	}
+	return false
+}
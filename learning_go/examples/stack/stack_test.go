@@ -0,0 +1,114 @@
+package main
+
+import "testing"
+
+// stackOp is one call on a Stack[int] and what it should return. push and
+// clear don't return anything.
+type stackOp struct {
+	op     string
+	val    int
+	want   int
+	wantOK bool
+}
+
+func TestStack(t *testing.T) {
+	tests := []struct {
+		name    string
+		ops     []stackOp
+		wantLen int
+	}{
+		{"pop empty", []stackOp{{op: "pop"}}, 0},
+		{"peek empty", []stackOp{{op: "peek"}}, 0},
+		{"push then pop restores empty", []stackOp{
+			{op: "push", val: 1},
+			{op: "pop", want: 1, wantOK: true},
+			{op: "pop"},
+		}, 0},
+		{"last in first out", []stackOp{
+			{op: "push", val: 1},
+			{op: "push", val: 2},
+			{op: "push", val: 3},
+			{op: "pop", want: 3, wantOK: true},
+			{op: "pop", want: 2, wantOK: true},
+			{op: "push", val: 4},
+			{op: "pop", want: 4, wantOK: true},
+			{op: "pop", want: 1, wantOK: true},
+		}, 0},
+		{"peek doesn't remove", []stackOp{
+			{op: "push", val: 1},
+			{op: "push", val: 2},
+			{op: "peek", want: 2, wantOK: true},
+			{op: "peek", want: 2, wantOK: true},
+		}, 2},
+		{"zero values", []stackOp{
+			{op: "push", val: 0},
+			{op: "peek", want: 0, wantOK: true},
+		}, 1},
+		{"clear", []stackOp{
+			{op: "push", val: 1},
+			{op: "push", val: 2},
+			{op: "clear"},
+			{op: "pop"},
+			{op: "push", val: 3},
+			{op: "peek", want: 3, wantOK: true},
+		}, 1},
+	}
+	for _, tt := range tests {
+		s := NewStack[int]()
+		for i, op := range tt.ops {
+			var got int
+			var ok bool
+			switch op.op {
+			case "push":
+				s.Push(op.val)
+				continue
+			case "clear":
+				s.Clear()
+				continue
+			case "pop":
+				got, ok = s.Pop()
+			case "peek":
+				got, ok = s.Peek()
+			}
+			if got != op.want || ok != op.wantOK {
+				t.Errorf("%s: op %d %s = %d, %t, want %d, %t", tt.name, i, op.op, got, ok, op.want, op.wantOK)
+			}
+		}
+		if s.Len() != tt.wantLen || s.IsEmpty() != (tt.wantLen == 0) {
+			t.Errorf("%s: Len, IsEmpty = %d, %t, want %d, %t", tt.name, s.Len(), s.IsEmpty(), tt.wantLen, tt.wantLen == 0)
+		}
+	}
+}
+
+func TestContains(t *testing.T) {
+	s := NewStack[string]()
+	if Contains(s, "a") {
+		t.Error("empty stack contains a")
+	}
+	s.Push("a")
+	s.Push("b")
+	if !Contains(s, "a") || !Contains(s, "b") || Contains(s, "c") {
+		t.Error("Contains doesn't match what was pushed")
+	}
+	s.Pop()
+	if Contains(s, "b") {
+		t.Error("stack still contains b after popping it")
+	}
+}
+
+// BenchmarkStackPushPop runs 1M push/pop cycles per op, with the stack
+// growing to 1000 values and back down.
+func BenchmarkStackPushPop(b *testing.B) {
+	const cycles, depth = 1_000_000, 1000
+	s := NewStack[int]()
+	for i := 0; i < b.N; i++ {
+		for j := 0; j < cycles/depth; j++ {
+			for k := 0; k < depth; k++ {
+				s.Push(k)
+			}
+			for k := 0; k < depth; k++ {
+				s.Pop()
+			}
+		}
+	}
+}
@@ -0,0 +1,3 @@
+module uringwriter
+
+go 1.21.3
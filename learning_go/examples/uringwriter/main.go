@@ -0,0 +1,86 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// writeRecords writes n small lines, roughly the size of a match record,
+// then flushes.
+func writeRecords(w io.Writer, flush func() error, n int) error {
+	for i := 0; i < n; i++ {
+		if _, err := fmt.Fprintf(w, "match %d: USA %d Canada %d\n", i, i%120, i%97); err != nil {
+			return err
+		}
+	}
+	return flush()
+}
+
+// bench times writing n records to a new file in dir with the writer that
+// open returns, and reports the number of writes per second.
+func bench(dir, name string, n int, open func(*os.File) (io.Writer, func() error, error)) ([]byte, error) {
+	path := filepath.Join(dir, name)
+	f, err := os.Create(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	w, flush, err := open(f)
+	if err != nil {
+		return nil, err
+	}
+	start := time.Now()
+	if err := writeRecords(w, flush, n); err != nil {
+		return nil, err
+	}
+	elapsed := time.Since(start)
+	fmt.Printf("%-12s %d writes in %v, %.0f IOPS\n", name, n, elapsed.Round(time.Microsecond), float64(n)/elapsed.Seconds())
+	return os.ReadFile(path)
+}
+
+func main() {
+	n := flag.Int("n", 100_000, "number of writes")
+	flag.Parse()
+
+	dir, err := os.MkdirTemp("", "uringwriter")
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	defer os.RemoveAll(dir)
+
+	plain, err := bench(dir, "bufio.Writer", *n, func(f *os.File) (io.Writer, func() error, error) {
+		w := bufio.NewWriter(f)
+		return w, w.Flush, nil
+	})
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	uring, err := bench(dir, "UringWriter", *n, func(f *os.File) (io.Writer, func() error, error) {
+		w, err := NewUringWriter(f)
+		if err != nil {
+			return nil, nil, err
+		}
+		return w, func() error {
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+			defer cancel()
+			if err := w.Flush(ctx); err != nil {
+				return err
+			}
+			return w.Close()
+		}, nil
+	})
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	fmt.Println("same output:", bytes.Equal(plain, uring), len(uring), "bytes")
+}
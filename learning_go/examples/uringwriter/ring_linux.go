@@ -0,0 +1,193 @@
+package main
+
+import (
+	"fmt"
+	"runtime"
+	"sync/atomic"
+	"syscall"
+	"unsafe"
+)
+
+// The io_uring system calls and constants, from linux/io_uring.h. The
+// syscall numbers are the same on every architecture Go supports.
+const (
+	sysIOUringSetup = 425
+	sysIOUringEnter = 426
+
+	ioringOffSQRing = 0
+	ioringOffCQRing = 0x8000000
+	ioringOffSQEs   = 0x10000000
+
+	ioringFeatSingleMmap = 1 << 0
+	ioringEnterGetEvents = 1 << 0
+	ioringOpWrite        = 23
+
+	sqeSize = 64
+	cqeSize = 16
+)
+
+// ioUringParams is struct io_uring_params.
+type ioUringParams struct {
+	sqEntries    uint32
+	cqEntries    uint32
+	flags        uint32
+	sqThreadCPU  uint32
+	sqThreadIdle uint32
+	features     uint32
+	wqFD         uint32
+	resv         [3]uint32
+	sqOff        sqRingOffsets
+	cqOff        cqRingOffsets
+}
+
+type sqRingOffsets struct {
+	head, tail, ringMask, ringEntries, flags, dropped, array, resv1 uint32
+	userAddr                                                        uint64
+}
+
+type cqRingOffsets struct {
+	head, tail, ringMask, ringEntries, overflow, cqes, flags, resv1 uint32
+	userAddr                                                        uint64
+}
+
+// ring is an io_uring instance: a submission queue the program fills with
+// requests and a completion queue the kernel fills with results, both
+// shared with the kernel through mmap.
+type ring struct {
+	fd     int
+	sqRing []byte
+	cqRing []byte
+	sqes   []byte
+	p      ioUringParams
+}
+
+func newRing(entries uint32) (*ring, error) {
+	r := &ring{}
+	fd, _, errno := syscall.Syscall(sysIOUringSetup, uintptr(entries), uintptr(unsafe.Pointer(&r.p)), 0)
+	if errno != 0 {
+		return nil, fmt.Errorf("io_uring_setup: %w", errno)
+	}
+	r.fd = int(fd)
+
+	sqSize := int(r.p.sqOff.array + r.p.sqEntries*4)
+	cqSize := int(r.p.cqOff.cqes + r.p.cqEntries*cqeSize)
+	if r.p.features&ioringFeatSingleMmap != 0 {
+		sqSize = max(sqSize, cqSize)
+	}
+	var err error
+	if r.sqRing, err = mmapRing(r.fd, ioringOffSQRing, sqSize); err != nil {
+		r.close()
+		return nil, err
+	}
+	if r.p.features&ioringFeatSingleMmap != 0 {
+		r.cqRing = r.sqRing
+	} else if r.cqRing, err = mmapRing(r.fd, ioringOffCQRing, cqSize); err != nil {
+		r.close()
+		return nil, err
+	}
+	if r.sqes, err = mmapRing(r.fd, ioringOffSQEs, int(r.p.sqEntries)*sqeSize); err != nil {
+		r.close()
+		return nil, err
+	}
+	return r, nil
+}
+
+func mmapRing(fd int, offset int64, size int) ([]byte, error) {
+	b, err := syscall.Mmap(fd, offset, size, syscall.PROT_READ|syscall.PROT_WRITE, syscall.MAP_SHARED|syscall.MAP_POPULATE)
+	if err != nil {
+		return nil, fmt.Errorf("mmap io_uring: %w", err)
+	}
+	return b, nil
+}
+
+// u32 points at a uint32 the kernel also reads or writes, so it must only be
+// accessed atomically.
+func u32(b []byte, off uint32) *uint32 {
+	return (*uint32)(unsafe.Pointer(&b[off]))
+}
+
+// write is one write request: len(buf) bytes to fd at offset.
+type write struct {
+	fd     int
+	buf    []byte
+	offset int64
+}
+
+// submit queues the writes, at most sqEntries of them, waits for all of
+// them to complete and returns the number of bytes each one wrote or the
+// error it failed with. The buffers must stay alive until it returns.
+func (r *ring) submit(writes []write) ([]int, error) {
+	mask := *u32(r.sqRing, r.p.sqOff.ringMask)
+	tail := atomic.LoadUint32(u32(r.sqRing, r.p.sqOff.tail))
+	for i, w := range writes {
+		idx := (tail + uint32(i)) & mask
+		sqe := r.sqes[idx*sqeSize : (idx+1)*sqeSize]
+		clear(sqe)
+		sqe[0] = ioringOpWrite
+		*(*int32)(unsafe.Pointer(&sqe[4])) = int32(w.fd)
+		*(*uint64)(unsafe.Pointer(&sqe[8])) = uint64(w.offset)
+		*(*uint64)(unsafe.Pointer(&sqe[16])) = uint64(uintptr(unsafe.Pointer(unsafe.SliceData(w.buf))))
+		*(*uint32)(unsafe.Pointer(&sqe[24])) = uint32(len(w.buf))
+		*(*uint64)(unsafe.Pointer(&sqe[32])) = uint64(i)
+		*u32(r.sqRing, r.p.sqOff.array+idx*4) = idx
+	}
+	// Publishing the new tail hands the entries to the kernel
+	atomic.StoreUint32(u32(r.sqRing, r.p.sqOff.tail), tail+uint32(len(writes)))
+
+	n := uint32(len(writes))
+	for n > 0 {
+		done, _, errno := syscall.Syscall6(sysIOUringEnter, uintptr(r.fd), uintptr(n), uintptr(n), ioringEnterGetEvents, 0, 0)
+		if errno == syscall.EINTR {
+			continue
+		}
+		if errno != 0 {
+			return nil, fmt.Errorf("io_uring_enter: %w", errno)
+		}
+		n -= uint32(done)
+	}
+
+	// Reap every completion, even after a failure, so none are left over to
+	// be mistaken for results of the next batch
+	results := make([]int, len(writes))
+	var firstErr error
+	cqMask := *u32(r.cqRing, r.p.cqOff.ringMask)
+	for seen := 0; seen < len(writes); {
+		head := atomic.LoadUint32(u32(r.cqRing, r.p.cqOff.head))
+		if head == atomic.LoadUint32(u32(r.cqRing, r.p.cqOff.tail)) {
+			_, _, errno := syscall.Syscall6(sysIOUringEnter, uintptr(r.fd), 0, 1, ioringEnterGetEvents, 0, 0)
+			if errno != 0 && errno != syscall.EINTR {
+				return nil, fmt.Errorf("io_uring_enter: %w", errno)
+			}
+			continue
+		}
+		cqe := r.p.cqOff.cqes + (head&cqMask)*cqeSize
+		i := *(*uint64)(unsafe.Pointer(&r.cqRing[cqe]))
+		res := *(*int32)(unsafe.Pointer(&r.cqRing[cqe+8]))
+		atomic.StoreUint32(u32(r.cqRing, r.p.cqOff.head), head+1)
+		seen++
+		if res < 0 {
+			if firstErr == nil {
+				firstErr = fmt.Errorf("write at offset %d: %w", writes[i].offset, syscall.Errno(-res))
+			}
+			continue
+		}
+		results[i] = int(res)
+	}
+	// The kernel had the buffers' addresses, not references to them
+	runtime.KeepAlive(writes)
+	return results, firstErr
+}
+
+func (r *ring) close() error {
+	if r.sqes != nil {
+		syscall.Munmap(r.sqes)
+	}
+	if r.cqRing != nil && r.p.features&ioringFeatSingleMmap == 0 {
+		syscall.Munmap(r.cqRing)
+	}
+	if r.sqRing != nil {
+		syscall.Munmap(r.sqRing)
+	}
+	r.sqes, r.cqRing, r.sqRing = nil, nil, nil
+	return syscall.Close(r.fd)
+}
@@ -0,0 +1,118 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+)
+
+const (
+	// ringEntries is how many writes go to the kernel in one io_uring_enter.
+	ringEntries = 64
+	// chunkSize is how much data each write request carries.
+	chunkSize = 64 << 10
+	// maxPending is how much data Write buffers before flushing by itself.
+	maxPending = ringEntries * chunkSize
+)
+
+// UringWriter writes sequentially to a file through io_uring. Write only
+// copies into a buffer, Flush splits the buffer into chunks and hands the
+// kernel up to 64 of them with a single system call, where a plain write
+// loop would need one call per chunk. It writes at explicit offsets from
+// the file's position when the writer was made, so nothing else should
+// write to the file while it's in use.
+type UringWriter struct {
+	f       *os.File
+	r       *ring
+	offset  int64
+	pending []byte
+}
+
+// NewUringWriter returns a writer that appends to f from its current
+// position.
+func NewUringWriter(f *os.File) (*UringWriter, error) {
+	offset, err := f.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return nil, err
+	}
+	r, err := newRing(ringEntries)
+	if err != nil {
+		return nil, err
+	}
+	return &UringWriter{f: f, r: r, offset: offset}, nil
+}
+
+// Write buffers p. Once a full batch is waiting it's flushed straight away.
+func (w *UringWriter) Write(p []byte) (int, error) {
+	if w.r == nil {
+		return 0, os.ErrClosed
+	}
+	w.pending = append(w.pending, p...)
+	if len(w.pending) >= maxPending {
+		if err := w.Flush(context.Background()); err != nil {
+			return len(p), err
+		}
+	}
+	return len(p), nil
+}
+
+// Flush writes everything buffered. ctx is checked between batches, a
+// batch that has been submitted always runs to completion. Data that wasn't
+// written stays buffered for the next Flush.
+func (w *UringWriter) Flush(ctx context.Context) error {
+	if w.r == nil {
+		return os.ErrClosed
+	}
+	fd := int(w.f.Fd())
+	buf := w.pending[:0]
+	for len(w.pending) > 0 {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		var batch []write
+		off, rest := w.offset, w.pending
+		for len(rest) > 0 && len(batch) < ringEntries {
+			n := min(len(rest), chunkSize)
+			batch = append(batch, write{fd: fd, buf: rest[:n], offset: off})
+			off += int64(n)
+			rest = rest[n:]
+		}
+		written, err := w.r.submit(batch)
+		if err != nil {
+			return err
+		}
+		// A short write leaves a gap, so only count the chunks up to the
+		// first one that didn't finish and write the rest again
+		done := 0
+		for i, n := range written {
+			done += n
+			if n < len(batch[i].buf) {
+				break
+			}
+		}
+		if done == 0 {
+			return fmt.Errorf("write at offset %d: %w", w.offset, io.ErrShortWrite)
+		}
+		w.offset += int64(done)
+		w.pending = w.pending[done:]
+	}
+	// Reuse the whole buffer again rather than what's left past the end of
+	// the data just written
+	w.pending = buf
+	return nil
+}
+
+// Close flushes anything still buffered and releases the ring. It doesn't
+// close the file.
+func (w *UringWriter) Close() error {
+	if w.r == nil {
+		return nil
+	}
+	err := w.Flush(context.Background())
+	if cerr := w.r.close(); err == nil {
+		err = cerr
+	}
+	w.r = nil
+	return err
+}
@@ -0,0 +1,139 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// newTestWriter opens a new file in a temporary directory and a UringWriter
+// for it. The test is skipped if io_uring isn't available, such as in a
+// sandbox that blocks it.
+func newTestWriter(t testing.TB) (*os.File, *UringWriter) {
+	t.Helper()
+	f, err := os.Create(filepath.Join(t.TempDir(), "out"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { f.Close() })
+	w, err := NewUringWriter(f)
+	if err != nil {
+		t.Skipf("io_uring isn't available: %v", err)
+	}
+	return f, w
+}
+
+func TestUringWriter(t *testing.T) {
+	// Sizes below one chunk, across chunks, and past a full batch, which
+	// Write flushes by itself
+	for _, n := range []int{0, 1, 3000, 20_000, 200_000} {
+		f, w := newTestWriter(t)
+		var want bytes.Buffer
+		if err := writeRecords(io.MultiWriter(w, &want), func() error { return w.Flush(context.Background()) }, n); err != nil {
+			t.Fatal(err)
+		}
+		if err := w.Close(); err != nil {
+			t.Fatal(err)
+		}
+		got, err := os.ReadFile(f.Name())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(got, want.Bytes()) {
+			t.Errorf("%d records: file has %d bytes, want %d", n, len(got), want.Len())
+		}
+	}
+}
+
+func TestUringWriterOffset(t *testing.T) {
+	// The writer carries on from the file's position
+	f, err := os.Create(filepath.Join(t.TempDir(), "out"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	if _, err := f.WriteString("header\n"); err != nil {
+		t.Fatal(err)
+	}
+	w, err := NewUringWriter(f)
+	if err != nil {
+		t.Skipf("io_uring isn't available: %v", err)
+	}
+	io.WriteString(w, "one\n")
+	if err := w.Flush(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	io.WriteString(w, "two\n")
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	got, err := os.ReadFile(f.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "header\none\ntwo\n"; string(got) != want {
+		t.Errorf("file = %q, want %q", got, want)
+	}
+}
+
+func TestUringWriterCancelledFlush(t *testing.T) {
+	f, w := newTestWriter(t)
+	io.WriteString(w, "kept\n")
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := w.Flush(ctx); !errors.Is(err, context.Canceled) {
+		t.Errorf("Flush with a cancelled context = %v, want context.Canceled", err)
+	}
+	// The data is still buffered, and Close writes it
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := os.ReadFile(f.Name()); string(got) != "kept\n" {
+		t.Errorf("file = %q, want %q", got, "kept\n")
+	}
+	if _, err := w.Write([]byte("late")); !errors.Is(err, os.ErrClosed) {
+		t.Errorf("Write after Close = %v, want os.ErrClosed", err)
+	}
+	if err := w.Close(); err != nil {
+		t.Errorf("second Close = %v", err)
+	}
+}
+
+// The writer benchmarks each write 100,000 small records and report the
+// writes per second.
+
+const benchWrites = 100_000
+
+func reportIOPS(b *testing.B) {
+	b.ReportMetric(float64(b.N)*benchWrites/b.Elapsed().Seconds(), "IOPS")
+}
+
+func BenchmarkBufioWriter(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		f, err := os.Create(filepath.Join(b.TempDir(), "out"))
+		if err != nil {
+			b.Fatal(err)
+		}
+		w := bufio.NewWriter(f)
+		if err := writeRecords(w, w.Flush, benchWrites); err != nil {
+			b.Fatal(err)
+		}
+		f.Close()
+	}
+	reportIOPS(b)
+}
+
+func BenchmarkUringWriter(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		_, w := newTestWriter(b)
+		if err := writeRecords(w, w.Close, benchWrites); err != nil {
+			b.Fatal(err)
+		}
+	}
+	reportIOPS(b)
+}
@@ -0,0 +1,20 @@
+//go:build !linux
+
+package main
+
+import (
+	"context"
+	"errors"
+	"os"
+)
+
+// UringWriter needs io_uring, which only Linux has.
+type UringWriter struct{}
+
+func NewUringWriter(f *os.File) (*UringWriter, error) { return nil, errors.ErrUnsupported }
+
+func (w *UringWriter) Write(p []byte) (int, error) { return 0, errors.ErrUnsupported }
+
+func (w *UringWriter) Flush(ctx context.Context) error { return errors.ErrUnsupported }
+
+func (w *UringWriter) Close() error { return nil }
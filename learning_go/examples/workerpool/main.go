@@ -0,0 +1,71 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+)
+
+// fileLen returns the number of bytes in the file, like fileLen in exercise
+// 05/ex2.
+func fileLen(file string) (int, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+	n, err := io.Copy(io.Discard, f)
+	return int(n), err
+}
+
+func main() {
+	dir := "."
+	if len(os.Args) > 1 {
+		dir = os.Args[1]
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	var mu sync.Mutex
+	sizes := map[string]int{}
+	pool := NewPool(4, WithQueueSize(16))
+	for _, e := range entries {
+		if e.IsDir() {
+			continue
+		}
+		path := filepath.Join(dir, e.Name())
+		err := pool.Submit(func() {
+			n, err := fileLen(path)
+			if err != nil {
+				fmt.Println(err)
+				return
+			}
+			mu.Lock()
+			sizes[path] = n
+			mu.Unlock()
+		})
+		if err != nil {
+			fmt.Println(err)
+		}
+	}
+	if err := pool.Resize(2); err != nil {
+		fmt.Println(err)
+	}
+	pool.Close()
+
+	names := make([]string, 0, len(sizes))
+	for name := range sizes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Println(name, sizes[name])
+	}
+	fmt.Println(pool.Submit(func() {}))
+}
@@ -0,0 +1,159 @@
+package main
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+var (
+	ErrPoolClosed = errors.New("pool is closed")
+	ErrTimeout    = errors.New("timed out waiting for room in the queue")
+)
+
+type poolOptions struct {
+	queueSize int
+}
+
+// PoolOption configures NewPool.
+type PoolOption func(*poolOptions)
+
+// WithQueueSize sets how many tasks can wait for a worker before Submit
+// blocks.
+func WithQueueSize(size int) PoolOption {
+	return func(o *poolOptions) {
+		o.queueSize = size
+	}
+}
+
+// Pool runs tasks on a fixed number of goroutines. Tasks wait in a buffered
+// channel until a worker is free.
+type Pool struct {
+	tasks chan func()
+	// quit tells one worker to stop, it's used to shrink the pool
+	quit chan struct{}
+	// done is closed by Close, so Resize stops waiting for workers to quit
+	done chan struct{}
+	wg   sync.WaitGroup
+	// mu is held for reading while a task is being queued, so Close can't
+	// close the channel while a Submit is sending on it
+	mu      sync.RWMutex
+	workers int
+	closed  bool
+}
+
+// NewPool starts a pool with the given number of workers, at least one. The
+// queue holds twice as many tasks as there are workers unless WithQueueSize
+// says otherwise.
+func NewPool(workers int, opts ...PoolOption) *Pool {
+	workers = max(workers, 1)
+	o := poolOptions{queueSize: 2 * workers}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	p := &Pool{
+		tasks: make(chan func(), max(o.queueSize, 0)),
+		quit:  make(chan struct{}),
+		done:  make(chan struct{}),
+	}
+	p.start(workers)
+	return p
+}
+
+func (p *Pool) start(n int) {
+	p.workers += n
+	p.wg.Add(n)
+	for i := 0; i < n; i++ {
+		go p.work()
+	}
+}
+
+func (p *Pool) work() {
+	defer p.wg.Done()
+	for {
+		select {
+		case <-p.quit:
+			return
+		case task, ok := <-p.tasks:
+			if !ok {
+				return
+			}
+			task()
+		}
+	}
+}
+
+// Submit queues task to run on one of the workers, blocking while the queue
+// is full. It returns ErrPoolClosed once Close has been called.
+func (p *Pool) Submit(task func()) error {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	if p.closed {
+		return ErrPoolClosed
+	}
+	p.tasks <- task
+	return nil
+}
+
+// SubmitWithTimeout is like Submit, but gives up with ErrTimeout if the task
+// can't be queued within timeout.
+func (p *Pool) SubmitWithTimeout(task func(), timeout time.Duration) error {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	if p.closed {
+		return ErrPoolClosed
+	}
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	select {
+	case p.tasks <- task:
+		return nil
+	case <-timer.C:
+		return ErrTimeout
+	}
+}
+
+// Resize changes the number of workers. Workers that are removed finish the
+// task they're running first, and Resize waits until they have.
+func (p *Pool) Resize(workers int) error {
+	if workers < 1 {
+		return errors.New("a pool needs at least one worker")
+	}
+	p.mu.Lock()
+	if p.closed {
+		p.mu.Unlock()
+		return ErrPoolClosed
+	}
+	if workers > p.workers {
+		p.start(workers - p.workers)
+		p.mu.Unlock()
+		return nil
+	}
+	stop := p.workers - workers
+	p.workers = workers
+	// Signal the workers without the lock, a task that calls Submit would
+	// otherwise wait for it while its worker is the one we're waiting for
+	p.mu.Unlock()
+	for i := 0; i < stop; i++ {
+		select {
+		case p.quit <- struct{}{}:
+		case <-p.done:
+			// Close has stopped every worker anyway
+			return nil
+		}
+	}
+	return nil
+}
+
+// Close stops the pool accepting tasks and waits for the queued and running
+// ones to finish. Calling it more than once is safe.
+func (p *Pool) Close() {
+	p.mu.Lock()
+	if !p.closed {
+		p.closed = true
+		close(p.tasks)
+		close(p.done)
+	}
+	p.mu.Unlock()
+	p.wg.Wait()
+}
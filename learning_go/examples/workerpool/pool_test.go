@@ -0,0 +1,230 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestPoolRunsEveryTask(t *testing.T) {
+	p := NewPool(4, WithQueueSize(8))
+	var ran atomic.Int32
+	for i := 0; i < 1000; i++ {
+		if err := p.Submit(func() { ran.Add(1) }); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// Close waits for the queued tasks as well as the running ones
+	p.Close()
+	if n := ran.Load(); n != 1000 {
+		t.Errorf("%d tasks ran, want 1000", n)
+	}
+}
+
+func TestPoolClosed(t *testing.T) {
+	p := NewPool(2)
+	p.Close()
+	p.Close()
+	if err := p.Submit(func() {}); !errors.Is(err, ErrPoolClosed) {
+		t.Errorf("Submit after Close = %v, want ErrPoolClosed", err)
+	}
+	if err := p.SubmitWithTimeout(func() {}, time.Second); !errors.Is(err, ErrPoolClosed) {
+		t.Errorf("SubmitWithTimeout after Close = %v, want ErrPoolClosed", err)
+	}
+	if err := p.Resize(4); !errors.Is(err, ErrPoolClosed) {
+		t.Errorf("Resize after Close = %v, want ErrPoolClosed", err)
+	}
+}
+
+func TestSubmitWithTimeout(t *testing.T) {
+	p := NewPool(1, WithQueueSize(0))
+	defer p.Close()
+	release := make(chan struct{})
+	if err := p.Submit(func() { <-release }); err != nil {
+		t.Fatal(err)
+	}
+	// The only worker is busy and there's no queue
+	if err := p.SubmitWithTimeout(func() {}, 20*time.Millisecond); !errors.Is(err, ErrTimeout) {
+		t.Errorf("SubmitWithTimeout = %v, want ErrTimeout", err)
+	}
+	close(release)
+	if err := p.SubmitWithTimeout(func() {}, time.Second); err != nil {
+		t.Errorf("SubmitWithTimeout with a free worker = %v", err)
+	}
+}
+
+// maxConcurrent submits tasks that each hold a worker for a moment, and
+// returns the most that ran at once.
+func maxConcurrent(t *testing.T, p *Pool, tasks int) int32 {
+	t.Helper()
+	var running, most atomic.Int32
+	var wg sync.WaitGroup
+	for i := 0; i < tasks; i++ {
+		wg.Add(1)
+		err := p.Submit(func() {
+			defer wg.Done()
+			n := running.Add(1)
+			for m := most.Load(); n > m && !most.CompareAndSwap(m, n); m = most.Load() {
+			}
+			time.Sleep(5 * time.Millisecond)
+			running.Add(-1)
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	wg.Wait()
+	return most.Load()
+}
+
+func TestResize(t *testing.T) {
+	p := NewPool(4, WithQueueSize(100))
+	defer p.Close()
+	if n := maxConcurrent(t, p, 40); n != 4 {
+		t.Errorf("4 workers ran %d tasks at once", n)
+	}
+	if err := p.Resize(1); err != nil {
+		t.Fatal(err)
+	}
+	if n := maxConcurrent(t, p, 10); n != 1 {
+		t.Errorf("1 worker ran %d tasks at once", n)
+	}
+	if err := p.Resize(6); err != nil {
+		t.Fatal(err)
+	}
+	if n := maxConcurrent(t, p, 60); n != 6 {
+		t.Errorf("6 workers ran %d tasks at once", n)
+	}
+	if err := p.Resize(0); err == nil {
+		t.Error("Resize(0) succeeded")
+	}
+}
+
+// TestResizeWhileTasksSubmit shrinks the pool while every worker is running
+// a task that submits another one, which used to deadlock: Resize held the
+// lock waiting for a worker to quit, and the workers' Submit calls waited
+// for the lock.
+func TestResizeWhileTasksSubmit(t *testing.T) {
+	// There's room in the queue for every nested task, so only the lock can
+	// hold them up
+	p := NewPool(4, WithQueueSize(4))
+	started := make(chan struct{}, 4)
+	release := make(chan struct{})
+	var ran atomic.Int32
+	var submitted sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		submitted.Add(1)
+		err := p.Submit(func() {
+			defer submitted.Done()
+			started <- struct{}{}
+			<-release
+			if err := p.Submit(func() { ran.Add(1) }); err != nil {
+				t.Error(err)
+			}
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	for i := 0; i < 4; i++ {
+		<-started
+	}
+	resized := make(chan error)
+	go func() { resized <- p.Resize(1) }()
+	// Give Resize time to start waiting before the tasks submit
+	time.Sleep(20 * time.Millisecond)
+	close(release)
+	select {
+	case err := <-resized:
+		if err != nil {
+			t.Fatal(err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Resize deadlocked with tasks calling Submit")
+	}
+	// Resize can return as soon as one worker is free, before the others
+	// have submitted
+	submitted.Wait()
+	p.Close()
+	if n := ran.Load(); n != 4 {
+		t.Errorf("%d nested tasks ran, want 4", n)
+	}
+}
+
+func TestResizeThenClose(t *testing.T) {
+	// A Resize still waiting for busy workers returns once Close stops them
+	p := NewPool(2, WithQueueSize(0))
+	release := make(chan struct{})
+	for i := 0; i < 2; i++ {
+		p.Submit(func() { <-release })
+	}
+	resized := make(chan error)
+	go func() { resized <- p.Resize(1) }()
+	time.Sleep(20 * time.Millisecond)
+	closed := make(chan struct{})
+	go func() {
+		p.Close()
+		close(closed)
+	}()
+	close(release)
+	<-closed
+	select {
+	case <-resized:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Resize didn't return after Close")
+	}
+}
+
+// smallFiles writes n small files to a temporary directory and returns their
+// paths.
+func smallFiles(b *testing.B, n int) []string {
+	b.Helper()
+	dir := b.TempDir()
+	paths := make([]string, n)
+	for i := range paths {
+		paths[i] = filepath.Join(dir, fmt.Sprintf("file%04d.txt", i))
+		if err := os.WriteFile(paths[i], []byte(fmt.Sprintf("file number %d\n", i)), 0o644); err != nil {
+			b.Fatal(err)
+		}
+	}
+	return paths
+}
+
+func BenchmarkFileLenSerial(b *testing.B) {
+	paths := smallFiles(b, 1000)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		total := 0
+		for _, path := range paths {
+			n, err := fileLen(path)
+			if err != nil {
+				b.Fatal(err)
+			}
+			total += n
+		}
+	}
+}
+
+func BenchmarkFileLenPool(b *testing.B) {
+	paths := smallFiles(b, 1000)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		p := NewPool(8, WithQueueSize(64))
+		var total atomic.Int64
+		for _, path := range paths {
+			p.Submit(func() {
+				n, err := fileLen(path)
+				if err != nil {
+					b.Error(err)
+				}
+				total.Add(int64(n))
+			})
+		}
+		p.Close()
+	}
+}
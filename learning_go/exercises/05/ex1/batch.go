@@ -0,0 +1,118 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// jsonResult is one entry in the output of EvalJSON. Result is a
+// json.Number so ints, floats and big ints are all written as plain JSON
+// numbers, exactly as calculate formatted them.
+type jsonResult struct {
+	Expr   string      `json:"expr"`
+	Result json.Number `json:"result,omitempty"`
+	Error  string      `json:"error,omitempty"`
+}
+
+// EvalJSON reads expressions as a JSON array of strings, or an object with
+// the array in its "expressions" field, and writes a JSON array with a
+// result object for each one, in the same order. Expressions that fail get
+// an "error" field instead of a "result". Input is decoded and results are
+// written one at a time, so neither has to fit in memory. The error is only
+// set when the input isn't in one of those shapes or reading or writing
+// fails, in which case the output is left unfinished.
+func EvalJSON(r io.Reader, w io.Writer) error {
+	return evalJSON(r, w, autoMode, &failures{})
+}
+
+// evalJSON is EvalJSON counting the expressions that fail in f.
+func evalJSON(r io.Reader, w io.Writer, m mode, f *failures) error {
+	dec := json.NewDecoder(r)
+	tok, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	switch tok {
+	case json.Delim('['):
+		return evalJSONArray(dec, w, m, f)
+	case json.Delim('{'):
+	default:
+		return fmt.Errorf("expected a JSON array or object, found %v", tok)
+	}
+
+	found := false
+	for dec.More() {
+		key, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		if key != "expressions" || found {
+			// Some other field, or a repeat of expressions, skip its value
+			var skip json.RawMessage
+			if err := dec.Decode(&skip); err != nil {
+				return err
+			}
+			continue
+		}
+		found = true
+		tok, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		if tok != json.Delim('[') {
+			return fmt.Errorf("expressions must be an array, found %v", tok)
+		}
+		if err := evalJSONArray(dec, w, m, f); err != nil {
+			return err
+		}
+	}
+	if !found {
+		return errors.New(`object has no "expressions" field`)
+	}
+	_, err = dec.Token()
+	return err
+}
+
+// evalJSONArray evaluates the strings in an array whose opening bracket has
+// already been read, and writes the results as a JSON array.
+func evalJSONArray(dec *json.Decoder, w io.Writer, m mode, f *failures) error {
+	if _, err := io.WriteString(w, "[\n"); err != nil {
+		return err
+	}
+	enc := json.NewEncoder(w)
+	for i := 0; dec.More(); i++ {
+		var expr string
+		if err := dec.Decode(&expr); err != nil {
+			return fmt.Errorf("expression %d: %w", i, err)
+		}
+		if i > 0 {
+			if _, err := io.WriteString(w, ","); err != nil {
+				return err
+			}
+		}
+		res := jsonResult{Expr: expr}
+		result, err := evalLine(expr, m)
+		switch {
+		case err != nil:
+			f.add(err)
+			res.Error = err.Error()
+		case !json.Valid([]byte(result)):
+			// A float that overflowed to +Inf, which JSON has no number for
+			f.eval++
+			res.Error = fmt.Sprintf("result %s can't be written as a JSON number", result)
+		default:
+			res.Result = json.Number(result)
+		}
+		if err := enc.Encode(res); err != nil {
+			return err
+		}
+	}
+	// The closing bracket
+	if _, err := dec.Token(); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "]\n")
+	return err
+}
@@ -0,0 +1,92 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+)
+
+type bigOpFuncType func(*big.Int, *big.Int) (*big.Int, error)
+
+// maxBigBits limits how large a power can get, so a typo like 2 ** 2000000000
+// fails straight away instead of using up all the memory.
+const maxBigBits = 1 << 20
+
+func addBig(i, j *big.Int) (*big.Int, error) { return new(big.Int).Add(i, j), nil }
+
+func subBig(i, j *big.Int) (*big.Int, error) { return new(big.Int).Sub(i, j), nil }
+
+func mulBig(i, j *big.Int) (*big.Int, error) { return new(big.Int).Mul(i, j), nil }
+
+// divBig and modBig truncate towards zero like Go's / and %, rather than
+// using big.Int's Euclidean Div and Mod.
+func divBig(i, j *big.Int) (*big.Int, error) {
+	if j.Sign() == 0 {
+		return nil, ErrDivisionByZero
+	}
+	return new(big.Int).Quo(i, j), nil
+}
+
+func modBig(i, j *big.Int) (*big.Int, error) {
+	if j.Sign() == 0 {
+		return nil, ErrDivisionByZero
+	}
+	return new(big.Int).Rem(i, j), nil
+}
+
+func powBig(i, j *big.Int) (*big.Int, error) {
+	if j.Sign() < 0 {
+		return nil, errors.New("negative exponent")
+	}
+	// The result has about bitlen(i) * j bits, except for 0, 1 and -1
+	// which stay small whatever the exponent
+	if i.CmpAbs(big.NewInt(1)) > 0 && (!j.IsInt64() || j.Int64() > maxBigBits/int64(i.BitLen()-1)) {
+		return nil, errors.New("result too large")
+	}
+	return new(big.Int).Exp(i, j, nil), nil
+}
+
+func andBig(i, j *big.Int) (*big.Int, error) { return new(big.Int).And(i, j), nil }
+
+func orBig(i, j *big.Int) (*big.Int, error) { return new(big.Int).Or(i, j), nil }
+
+func xorBig(i, j *big.Int) (*big.Int, error) { return new(big.Int).Xor(i, j), nil }
+
+// bigShiftCount checks a shift count, which can be larger than for ints but
+// is still limited by maxBigBits.
+func bigShiftCount(j *big.Int) (uint, error) {
+	if j.Sign() < 0 || !j.IsInt64() || j.Int64() > maxBigBits {
+		return 0, fmt.Errorf("invalid shift count %s: must be between 0 and %d", j, maxBigBits)
+	}
+	return uint(j.Int64()), nil
+}
+
+func shlBig(i, j *big.Int) (*big.Int, error) {
+	n, err := bigShiftCount(j)
+	if err != nil {
+		return nil, err
+	}
+	return new(big.Int).Lsh(i, n), nil
+}
+
+func shrBig(i, j *big.Int) (*big.Int, error) {
+	n, err := bigShiftCount(j)
+	if err != nil {
+		return nil, err
+	}
+	return new(big.Int).Rsh(i, n), nil
+}
+
+var opMapBig = map[string]bigOpFuncType{
+	"+":  addBig,
+	"-":  subBig,
+	"*":  mulBig,
+	"/":  divBig,
+	"%":  modBig,
+	"**": powBig,
+	"&":  andBig,
+	"|":  orBig,
+	"^":  xorBig,
+	"<<": shlBig,
+	">>": shrBig,
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestBigMode(t *testing.T) {
+	tests := []struct {
+		expr []string
+		want string
+	}{
+		// The product needs 126 bits
+		{[]string{"9223372036854775807", "*", "9223372036854775807"}, "85070591730234615847396907784232501249"},
+		{[]string{"2", "**", "200"}, "1606938044258990275541962092341162602522202993782792835301376"},
+		{[]string{"-9223372036854775808", "/", "-1"}, "9223372036854775808"},
+		// / and % truncate towards zero like Go's
+		{[]string{"-7", "/", "2"}, "-3"},
+		{[]string{"-7", "%", "2"}, "-1"},
+	}
+	for _, tt := range tests {
+		if got, err := calculate(tt.expr, bigMode); err != nil || got != tt.want {
+			t.Errorf("calculate(%q, bigMode) = %q, %v, want %q", tt.expr, got, err, tt.want)
+		}
+	}
+	for _, expr := range [][]string{
+		{"18446744073709551616", "/", "0"},
+		{"18446744073709551616", "%", "0"},
+	} {
+		if _, err := calculate(expr, bigMode); !errors.Is(err, ErrDivisionByZero) {
+			t.Errorf("calculate(%q, bigMode) error = %v, want ErrDivisionByZero", expr, err)
+		}
+	}
+	if _, err := calculate([]string{"2", "**", "2000000000"}, bigMode); err == nil {
+		t.Error("calculate(2 ** 2000000000, bigMode) succeeded")
+	}
+}
+
+// TestBigPromotion checks that auto mode switches to big.Int when a literal
+// doesn't fit in an int64, but not just because a result doesn't.
+func TestBigPromotion(t *testing.T) {
+	tests := []struct {
+		expr string
+		want string
+	}{
+		{"9223372036854775808 + 0", "9223372036854775808"},
+		{"99999999999999999999 * 2", "199999999999999999998"},
+		{"-9223372036854775809 + 1", "-9223372036854775808"},
+		{"18446744073709551616 % 7", "2"},
+		{"1 + 2 * 18446744073709551616", "36893488147419103233"},
+	}
+	for _, tt := range tests {
+		if got, err := evalLine(tt.expr, autoMode); err != nil || got != tt.want {
+			t.Errorf("evalLine(%q) = %q, %v, want %q", tt.expr, got, err, tt.want)
+		}
+	}
+	if got, err := calculate([]string{"99999999999999999999", "*", "2"}, autoMode); err != nil || got != "199999999999999999998" {
+		t.Errorf("calculate(99999999999999999999 * 2) = %q, %v", got, err)
+	}
+	// Literals that fit stay ints, and overflow as ints do
+	if got, err := evalLine("9223372036854775807 * 9223372036854775807", autoMode); !errors.Is(err, ErrOverflow) {
+		t.Errorf("evalLine(MaxInt64 * MaxInt64) = %q, %v, want ErrOverflow", got, err)
+	}
+}
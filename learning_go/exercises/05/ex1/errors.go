@@ -0,0 +1,60 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	// ErrDivisionByZero is returned for both / and % with a zero divisor.
+	ErrDivisionByZero = errors.New("division by zero")
+	// ErrOverflow is returned when an integer result doesn't fit in an int.
+	ErrOverflow = errors.New("integer overflow")
+	// ErrUnsupportedOperator is wrapped with the operator that isn't known.
+	ErrUnsupportedOperator = errors.New("unsupported operator")
+	// ErrUnknownFunction is the Err of the ParseError for a call to a
+	// function that isn't registered.
+	ErrUnknownFunction = errors.New("unknown function")
+)
+
+// ParseError is returned when an expression isn't well formed. Token is the
+// token that was found instead of what was expected, or empty at the end of
+// the input. Pos is its byte offset in the input. For an expression that is
+// already split into tokens, the offset is into the tokens joined by single
+// spaces.
+type ParseError struct {
+	Token string
+	Pos   int
+	Msg   string
+	// Err is the underlying error, such as from strconv, if there is one
+	Err error
+}
+
+func (e *ParseError) Error() string {
+	if e.Token == "" {
+		return fmt.Sprintf("%s at end of input", e.Msg)
+	}
+	return fmt.Sprintf("%s at offset %d, found %q", e.Msg, e.Pos, e.Token)
+}
+
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
+func unsupportedOperator(op string) error {
+	return fmt.Errorf("%w: %s", ErrUnsupportedOperator, op)
+}
+
+// tokenOffset returns where expression[i] starts once the tokens are joined
+// by single spaces.
+func tokenOffset(expression []string, i int) int {
+	pos := 0
+	for _, t := range expression[:i] {
+		pos += len(t) + 1
+	}
+	return pos
+}
+
+func invalidNumber(expression []string, i int, err error) error {
+	return &ParseError{Token: expression[i], Pos: tokenOffset(expression, i), Msg: "invalid number", Err: err}
+}
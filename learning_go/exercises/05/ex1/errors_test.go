@@ -0,0 +1,94 @@
+package main
+
+import (
+	"errors"
+	"strconv"
+	"testing"
+)
+
+func TestErrDivisionByZero(t *testing.T) {
+	for _, expr := range []string{"1 / 0", "1 % 0", "1 + 2 / (3 - 3)"} {
+		for _, m := range []mode{autoMode, floatMode, bigMode} {
+			if _, err := evalLine(expr, m); !errors.Is(err, ErrDivisionByZero) {
+				t.Errorf("evalLine(%q, %d) error = %v, want ErrDivisionByZero", expr, m, err)
+			}
+		}
+		if _, err := Eval(expr); !errors.Is(err, ErrDivisionByZero) {
+			t.Errorf("Eval(%q) error = %v, want ErrDivisionByZero", expr, err)
+		}
+	}
+	if _, err := calculate([]string{"7", "/", "0"}, autoMode); !errors.Is(err, ErrDivisionByZero) {
+		t.Errorf("calculate(7 / 0) error = %v, want ErrDivisionByZero", err)
+	}
+}
+
+func TestErrOverflow(t *testing.T) {
+	for _, expr := range []string{"9223372036854775807 + 1", "3037000500 * 3037000500", "2 ** 63"} {
+		if _, err := Eval(expr); !errors.Is(err, ErrOverflow) {
+			t.Errorf("Eval(%q) error = %v, want ErrOverflow", expr, err)
+		}
+	}
+	// An overflow isn't a parse error
+	_, err := Eval("9223372036854775807 + 1")
+	var pe *ParseError
+	if errors.As(err, &pe) {
+		t.Errorf("overflow error %v is a *ParseError", err)
+	}
+}
+
+func TestParseErrorToken(t *testing.T) {
+	tests := []struct {
+		expr      string
+		wantToken string
+		wantPos   int
+	}{
+		{"1 + x", "x", 4},
+		{"2 $ 3", "$", 2},
+		{"1 + 2 3", "3", 6},
+		{"1 +", "", 3},
+	}
+	for _, tt := range tests {
+		_, err := Eval(tt.expr)
+		var pe *ParseError
+		if !errors.As(err, &pe) || pe.Token != tt.wantToken || pe.Pos != tt.wantPos {
+			t.Errorf("Eval(%q) error = %v, want a *ParseError for %q at %d", tt.expr, err, tt.wantToken, tt.wantPos)
+		}
+	}
+	// An invalid number wraps the error from strconv
+	_, err := Eval("1.5 + 1")
+	if !errors.Is(err, strconv.ErrSyntax) {
+		t.Errorf("Eval(1.5 + 1) error = %v, want it to wrap strconv.ErrSyntax", err)
+	}
+	_, err = calculate([]string{"1", "+", "two"}, autoMode)
+	var pe *ParseError
+	if !errors.As(err, &pe) || pe.Token != "two" || pe.Pos != 4 {
+		t.Errorf("calculate(1 + two) error = %v, want a *ParseError for %q at 4", err, "two")
+	}
+}
+
+func TestErrUnknownFunction(t *testing.T) {
+	for _, m := range []mode{autoMode, floatMode, bigMode} {
+		_, err := evalLine("1 + foo(2)", m)
+		var pe *ParseError
+		if !errors.Is(err, ErrUnknownFunction) || !errors.As(err, &pe) || pe.Token != "foo" {
+			t.Errorf("evalLine(1 + foo(2), %d) error = %v, want ErrUnknownFunction for foo", m, err)
+		}
+	}
+	// A known function called wrongly is a different error
+	if _, err := Eval("max(1)"); err == nil || errors.Is(err, ErrUnknownFunction) {
+		t.Errorf("Eval(max(1)) error = %v, want an arity error", err)
+	}
+}
+
+func TestErrUnsupportedOperator(t *testing.T) {
+	_, err := evalLine("6 & 3", floatMode)
+	if !errors.Is(err, ErrUnsupportedOperator) {
+		t.Fatalf("evalLine(6 & 3, floatMode) error = %v, want ErrUnsupportedOperator", err)
+	}
+	if err.Error() != "unsupported operator: &" {
+		t.Errorf("error = %q, want it to name the operator", err)
+	}
+	if _, err := calculate([]string{"1", "@", "2"}, autoMode); !errors.Is(err, ErrUnsupportedOperator) {
+		t.Errorf("calculate(1 @ 2) error = %v, want ErrUnsupportedOperator", err)
+	}
+}
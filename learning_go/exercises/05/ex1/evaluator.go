@@ -0,0 +1,129 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Evaluator holds the operators and functions an expression can use. The
+// zero value has none, use NewEvaluator to get one with the built-ins
+// registered. RegisterOp and RegisterFunc must not be called while the
+// Evaluator is evaluating.
+type Evaluator struct {
+	ops        map[string]opFuncType
+	funcs      map[string]function[int]
+	precedence map[string]int
+	rightAssoc map[string]bool
+	// symbols is every operator, longest first, so the tokenizer matches **
+	// before *
+	symbols []string
+}
+
+// NewEvaluator returns an Evaluator with the operators in opMap and the
+// functions in funcMap registered.
+func NewEvaluator() *Evaluator {
+	e := &Evaluator{funcs: map[string]function[int]{}}
+	for sym, fn := range opMap {
+		e.register(sym, precedence[sym], fn)
+	}
+	for sym := range rightAssoc {
+		e.rightAssoc[sym] = true
+	}
+	for name, f := range funcMap {
+		e.funcs[name] = f
+	}
+	return e
+}
+
+// defaultEvaluator is used by Eval and Tokenize.
+var defaultEvaluator = NewEvaluator()
+
+// RegisterOp adds a left associative binary operator. Higher precedence
+// binds tighter: +, -, | and ^ are 1, *, /, %, &, << and >> are 2, and ** is
+// 3. The symbol can't be empty, contain digits, spaces, commas or
+// parentheses, or already be registered. A symbol with letters in it must be
+// a name such as gcd, since the tokenizer reads a run of letters as one
+// token.
+func (e *Evaluator) RegisterOp(symbol string, precedence int, fn func(int, int) (int, error)) error {
+	switch {
+	case symbol == "":
+		return errors.New("operator symbol can't be empty")
+	case strings.ContainsAny(symbol, "0123456789(), \t\r\n"):
+		return fmt.Errorf("invalid operator symbol %q: can't contain digits, spaces, commas or parentheses", symbol)
+	case strings.IndexFunc(symbol, isIdentRune) >= 0 && !isIdent(symbol):
+		return fmt.Errorf("invalid operator symbol %q: can't mix letters with other characters", symbol)
+	case precedence < 1:
+		return fmt.Errorf("invalid precedence %d for %q: must be at least 1", precedence, symbol)
+	case fn == nil:
+		return fmt.Errorf("operator %q has no function", symbol)
+	}
+	if _, ok := e.ops[symbol]; ok {
+		return fmt.Errorf("operator %q is already registered", symbol)
+	}
+	e.register(symbol, precedence, fn)
+	return nil
+}
+
+func (e *Evaluator) register(symbol string, precedence int, fn opFuncType) {
+	if e.ops == nil {
+		e.ops = map[string]opFuncType{}
+		e.precedence = map[string]int{}
+		e.rightAssoc = map[string]bool{}
+	}
+	e.ops[symbol] = fn
+	e.precedence[symbol] = precedence
+	e.symbols = append(e.symbols, symbol)
+	sort.Slice(e.symbols, func(i, j int) bool {
+		if len(e.symbols[i]) != len(e.symbols[j]) {
+			return len(e.symbols[i]) > len(e.symbols[j])
+		}
+		return e.symbols[i] < e.symbols[j]
+	})
+}
+
+// isIdent reports whether s is a name that the tokenizer reads as a single
+// token: a letter or underscore followed by letters, digits and
+// underscores.
+func isIdent(s string) bool {
+	if s == "" {
+		return false
+	}
+	for i, c := range s {
+		if !isIdentRune(c) && (i == 0 || c < '0' || c > '9') {
+			return false
+		}
+	}
+	return true
+}
+
+func isIdentRune(c rune) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+// RegisterFunc adds a function that expressions can call as name(args...),
+// such as the built-in abs, min, max, pow and gcd. fn is only called with
+// exactly arity arguments, calls with any other number are rejected with an
+// error naming the function. The name must be a letter or underscore
+// followed by letters, digits or underscores, and not already be a
+// function. It can be the same as an operator, since functions are only
+// looked up where an operand is expected.
+func (e *Evaluator) RegisterFunc(name string, arity int, fn func(args []int) (int, error)) error {
+	switch {
+	case !isIdent(name):
+		return fmt.Errorf("invalid function name %q: must be a letter or underscore followed by letters, digits or underscores", name)
+	case arity < 0:
+		return fmt.Errorf("invalid arity %d for %s: can't be negative", arity, name)
+	case fn == nil:
+		return fmt.Errorf("function %s has nothing to call", name)
+	}
+	if _, ok := e.funcs[name]; ok {
+		return fmt.Errorf("function %s is already registered", name)
+	}
+	if e.funcs == nil {
+		e.funcs = map[string]function[int]{}
+	}
+	e.funcs[name] = function[int]{arity: arity, fn: fn}
+	return nil
+}
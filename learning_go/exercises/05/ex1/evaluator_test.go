@@ -0,0 +1,114 @@
+package main
+
+import (
+	"errors"
+	"testing"
+)
+
+func gcdOp(a, b int) (int, error) {
+	for b != 0 {
+		a, b = b, a%b
+	}
+	if a < 0 {
+		a = -a
+	}
+	return a, nil
+}
+
+func TestRegisterOp(t *testing.T) {
+	ev := NewEvaluator()
+	if err := ev.RegisterOp("gcd", 2, gcdOp); err != nil {
+		t.Fatal(err)
+	}
+	minOp := func(a, b int) (int, error) { return min(a, b), nil }
+	if err := ev.RegisterOp("<?", 1, minOp); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		expr string
+		want int
+	}{
+		{"12 gcd 18", 6},
+		// gcd binds as tightly as * and tighter than +
+		{"2 + 12 gcd 18", 8},
+		{"12 gcd 18 * 2", 12},
+		{"(12 gcd 18) + gcd(4, 6)", 8},
+		{"3 <? 4 * 2", 3},
+		{"9 <? 4 + 1", 5},
+		// The built-ins are still there
+		{"1 + 2 * 3 - 8 / 4", 5},
+	}
+	for _, tt := range tests {
+		if got, err := ev.Eval(tt.expr); err != nil || got != tt.want {
+			t.Errorf("Eval(%q) = %d, %v, want %d", tt.expr, got, err, tt.want)
+		}
+	}
+	// Operators registered on ev don't leak into the default evaluator
+	if _, err := Eval("12 gcd 18"); err == nil {
+		t.Error("Eval(12 gcd 18) on the default evaluator succeeded")
+	}
+}
+
+func TestRegisterOpErrors(t *testing.T) {
+	ev := NewEvaluator()
+	if err := ev.RegisterOp("gcd", 2, gcdOp); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name       string
+		symbol     string
+		precedence int
+		fn         func(int, int) (int, error)
+	}{
+		{"duplicate", "gcd", 2, gcdOp},
+		{"duplicate built-in", "+", 1, gcdOp},
+		{"duplicate with another precedence", "*", 3, gcdOp},
+		{"empty", "", 1, gcdOp},
+		{"digit", "x2", 1, gcdOp},
+		{"space", "a b", 1, gcdOp},
+		{"parenthesis", "(", 1, gcdOp},
+		{"comma", ",", 1, gcdOp},
+		{"letters and symbols", "g+", 1, gcdOp},
+		{"zero precedence", "@", 0, gcdOp},
+		{"no function", "@", 1, nil},
+	}
+	for _, tt := range tests {
+		if err := ev.RegisterOp(tt.symbol, tt.precedence, tt.fn); err == nil {
+			t.Errorf("%s: RegisterOp(%q) succeeded", tt.name, tt.symbol)
+		}
+	}
+	// A failed registration leaves the operator as it was
+	if got, err := ev.Eval("2 + 3 * 4"); err != nil || got != 14 {
+		t.Errorf("Eval(2 + 3 * 4) = %d, %v, want 14", got, err)
+	}
+}
+
+func TestZeroEvaluator(t *testing.T) {
+	var ev Evaluator
+	if _, err := ev.Eval("1 + 2"); err == nil {
+		t.Error("a zero Evaluator knows +")
+	}
+	if err := ev.RegisterOp("gcd", 1, gcdOp); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := ev.Eval("12 gcd 18 gcd 4"); err != nil || got != 2 {
+		t.Errorf("Eval(12 gcd 18 gcd 4) = %d, %v, want 2", got, err)
+	}
+}
+
+func TestRegisterOpError(t *testing.T) {
+	ev := NewEvaluator()
+	errNegative := errors.New("negative")
+	checked := func(a, b int) (int, error) {
+		if a < 0 || b < 0 {
+			return 0, errNegative
+		}
+		return a + b, nil
+	}
+	if err := ev.RegisterOp("+?", 1, checked); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ev.Eval("1 +? (0 - 1)"); !errors.Is(err, errNegative) {
+		t.Errorf("got error %v, want the operator's error", err)
+	}
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"math"
+	"math/big"
+)
+
+// function is an entry in a function table, something that can be called
+// in an expression such as max(3, 5). fn is only called with arity
+// arguments.
+type function[T any] struct {
+	arity int
+	fn    func(args []T) (T, error)
+}
+
+func absFunc(args []int) (int, error) {
+	if args[0] < 0 {
+		// sub catches -MinInt, which doesn't fit
+		return sub(0, args[0])
+	}
+	return args[0], nil
+}
+
+func minFunc(args []int) (int, error) { return min(args[0], args[1]), nil }
+
+func maxFunc(args []int) (int, error) { return max(args[0], args[1]), nil }
+
+func powFunc(args []int) (int, error) { return pow(args[0], args[1]) }
+
+// gcdFunc returns the greatest common divisor, which is never negative.
+// gcd(0, 0) is 0.
+func gcdFunc(args []int) (int, error) {
+	a, b := args[0], args[1]
+	for b != 0 {
+		a, b = b, a%b
+	}
+	return absFunc([]int{a})
+}
+
+// funcMap is the built-in functions and how many arguments each takes.
+var funcMap = map[string]function[int]{
+	"abs": {1, absFunc},
+	"min": {2, minFunc},
+	"max": {2, maxFunc},
+	"pow": {2, powFunc},
+	"gcd": {2, gcdFunc},
+}
+
+// floatFuncMap is the built-in functions for float mode. There's no gcd,
+// since it only makes sense for integers.
+var floatFuncMap = map[string]function[float64]{
+	"abs": {1, func(args []float64) (float64, error) { return math.Abs(args[0]), nil }},
+	"min": {2, func(args []float64) (float64, error) { return math.Min(args[0], args[1]), nil }},
+	"max": {2, func(args []float64) (float64, error) { return math.Max(args[0], args[1]), nil }},
+	"pow": {2, func(args []float64) (float64, error) { return powFloat(args[0], args[1]) }},
+}
+
+// bigFuncMap is the built-in functions for big mode.
+var bigFuncMap = map[string]function[*big.Int]{
+	"abs": {1, func(args []*big.Int) (*big.Int, error) { return new(big.Int).Abs(args[0]), nil }},
+	"min": {2, func(args []*big.Int) (*big.Int, error) {
+		if args[0].Cmp(args[1]) <= 0 {
+			return args[0], nil
+		}
+		return args[1], nil
+	}},
+	"max": {2, func(args []*big.Int) (*big.Int, error) {
+		if args[0].Cmp(args[1]) >= 0 {
+			return args[0], nil
+		}
+		return args[1], nil
+	}},
+	"pow": {2, func(args []*big.Int) (*big.Int, error) { return powBig(args[0], args[1]) }},
+	"gcd": {2, func(args []*big.Int) (*big.Int, error) { return new(big.Int).GCD(nil, nil, args[0], args[1]), nil }},
+}
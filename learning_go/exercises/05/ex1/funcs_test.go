@@ -0,0 +1,99 @@
+package main
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestFuncsNested(t *testing.T) {
+	tests := []struct {
+		expr string
+		m    mode
+		want string
+	}{
+		{"max(3, min(7, 5))", autoMode, "5"},
+		{"abs(-4) + gcd(12, 18)", autoMode, "10"},
+		{"pow(2, max(3, abs(-10)))", autoMode, "1024"},
+		{"min(max(1, 2), max(3, 4)) * 2", autoMode, "4"},
+		{"-abs(-(2 + 3))", autoMode, "-5"},
+		{"max(1.5, min(3, 2))", floatMode, "2"},
+		{"abs(-2) + pow(2, 0.5) * 0", floatMode, "2"},
+		{"gcd(abs(-12), 99999999999999999999)", bigMode, "3"},
+		{"max(pow(2, 100), 1)", bigMode, "1267650600228229401496703205376"},
+	}
+	for _, tt := range tests {
+		got, err := evalLine(tt.expr, tt.m)
+		if err != nil || got != tt.want {
+			t.Errorf("evalLine(%q, %d) = %q, %v, want %q", tt.expr, tt.m, got, err, tt.want)
+		}
+	}
+}
+
+func TestFuncsArity(t *testing.T) {
+	tests := []struct {
+		expr, msg string
+	}{
+		{"max(1)", "max takes 2 arguments, got 1"},
+		{"abs(1, 2)", "abs takes 1 argument, got 2"},
+		{"gcd()", "gcd takes 2 arguments, got 0"},
+		{"max(1, min(2))", "min takes 2 arguments, got 1"},
+	}
+	for _, tt := range tests {
+		for _, m := range []mode{autoMode, floatMode, bigMode} {
+			if m == floatMode && strings.HasPrefix(tt.expr, "gcd") {
+				continue
+			}
+			_, err := evalLine(tt.expr, m)
+			var pe *ParseError
+			if !errors.As(err, &pe) || pe.Msg != tt.msg {
+				t.Errorf("evalLine(%q, %d) error = %v, want %q", tt.expr, m, err, tt.msg)
+			}
+		}
+	}
+}
+
+func TestFuncsUnknown(t *testing.T) {
+	tests := []struct {
+		expr string
+		m    mode
+		name string
+		pos  int
+	}{
+		{"foo(1)", autoMode, "foo", 0},
+		{"1 + nope(2, 3)", autoMode, "nope", 4},
+		{"max(1, bar(2))", autoMode, "bar", 7},
+		{"gcd(4, 6)", floatMode, "gcd", 0},
+		{"sqrt(4)", bigMode, "sqrt", 0},
+	}
+	for _, tt := range tests {
+		_, err := evalLine(tt.expr, tt.m)
+		var pe *ParseError
+		if !errors.As(err, &pe) || pe.Msg != "unknown function" || pe.Token != tt.name || pe.Pos != tt.pos {
+			t.Errorf("evalLine(%q, %d) error = %v, want unknown function %q at %d", tt.expr, tt.m, err, tt.name, tt.pos)
+		}
+	}
+}
+
+func TestRegisterFunc(t *testing.T) {
+	ev := NewEvaluator()
+	clamp := func(args []int) (int, error) { return max(args[1], min(args[0], args[2])), nil }
+	if err := ev.RegisterFunc("clamp", 3, clamp); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := ev.Eval("clamp(15, 0, max(5, 10))"); err != nil || got != 10 {
+		t.Errorf("Eval(clamp) = %d, %v, want 10", got, err)
+	}
+	if err := ev.RegisterFunc("clamp", 3, clamp); err == nil {
+		t.Error("registering clamp twice succeeded")
+	}
+	for _, name := range []string{"", "2x", "a-b", "a b"} {
+		if err := ev.RegisterFunc(name, 1, clamp); err == nil {
+			t.Errorf("RegisterFunc(%q) succeeded", name)
+		}
+	}
+	// Functions registered on ev don't leak into the default evaluator
+	if _, err := Eval("clamp(1, 2, 3)"); err == nil {
+		t.Error("Eval(clamp) on the default evaluator succeeded")
+	}
+}
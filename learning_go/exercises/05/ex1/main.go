@@ -2,72 +2,596 @@ package main
 
 import (
 	"errors"
+	"flag"
 	"fmt"
+	"io"
+	"math"
+	"math/big"
+	"os"
 	"strconv"
+	"strings"
 )
 
 type opFuncType func(int, int) (int, error)
 
-func add(i, j int) (int, error) { return i + j, nil }
+type floatOpFuncType func(float64, float64) (float64, error)
 
-func sub(i, j int) (int, error) { return i - j, nil }
+// The integer ops return ErrOverflow rather than wrapping around when the
+// result doesn't fit in an int.
 
-func mul(i, j int) (int, error) { return i * j, nil }
+func add(i, j int) (int, error) {
+	r := i + j
+	if (j > 0 && r < i) || (j < 0 && r > i) {
+		return 0, ErrOverflow
+	}
+	return r, nil
+}
+
+func sub(i, j int) (int, error) {
+	r := i - j
+	if (j > 0 && r > i) || (j < 0 && r < i) {
+		return 0, ErrOverflow
+	}
+	return r, nil
+}
+
+func mul(i, j int) (int, error) {
+	r, ok := mulChecked(i, j)
+	if !ok {
+		return 0, ErrOverflow
+	}
+	return r, nil
+}
 
 func div(i, j int) (int, error) {
 	if j == 0 {
-		return 0, errors.New("division by zero")
+		return 0, ErrDivisionByZero
+	}
+	// The one quotient that doesn't fit, -MinInt is MaxInt+1
+	if i == math.MinInt && j == -1 {
+		return 0, ErrOverflow
 	}
 	return i / j, nil
 }
 
+func mod(i, j int) (int, error) {
+	if j == 0 {
+		return 0, ErrDivisionByZero
+	}
+	return i % j, nil
+}
+
+// mulChecked multiplies i and j, and reports false if the result doesn't fit
+// in an int.
+func mulChecked(i, j int) (int, bool) {
+	if i == 0 || j == 0 {
+		return 0, true
+	}
+	r := i * j
+	if r/j != i || (i == -1 && j == math.MinInt) || (j == -1 && i == math.MinInt) {
+		return 0, false
+	}
+	return r, true
+}
+
+// pow raises i to the power j by repeated squaring, so it takes log(j)
+// multiplications instead of j.
+func pow(i, j int) (int, error) {
+	if j < 0 {
+		return 0, errors.New("negative exponent")
+	}
+	result, base := 1, i
+	for j > 0 {
+		var ok bool
+		if j&1 == 1 {
+			if result, ok = mulChecked(result, base); !ok {
+				return 0, ErrOverflow
+			}
+		}
+		j >>= 1
+		// Only square the base if it's needed again, the last square could
+		// overflow even when the result fits
+		if j > 0 {
+			if base, ok = mulChecked(base, base); !ok {
+				return 0, ErrOverflow
+			}
+		}
+	}
+	return result, nil
+}
+
+func and(i, j int) (int, error) { return i & j, nil }
+
+func or(i, j int) (int, error) { return i | j, nil }
+
+func xor(i, j int) (int, error) { return i ^ j, nil }
+
+// checkShift rejects shift counts that Go would panic on or that would shift
+// every bit out.
+func checkShift(j int) error {
+	if j < 0 || j >= 64 {
+		return fmt.Errorf("invalid shift count %d: must be between 0 and 63", j)
+	}
+	return nil
+}
+
+func shl(i, j int) (int, error) {
+	if err := checkShift(j); err != nil {
+		return 0, err
+	}
+	r := i << j
+	if r>>j != i {
+		return 0, ErrOverflow
+	}
+	return r, nil
+}
+
+func shr(i, j int) (int, error) {
+	if err := checkShift(j); err != nil {
+		return 0, err
+	}
+	return i >> j, nil
+}
+
 var opMap = map[string]opFuncType{
-	"+": add,
-	"-": sub,
-	"*": mul,
-	"/": div,
+	"+":  add,
+	"-":  sub,
+	"*":  mul,
+	"/":  div,
+	"%":  mod,
+	"**": pow,
+	"&":  and,
+	"|":  or,
+	"^":  xor,
+	"<<": shl,
+	">>": shr,
+}
+
+func addFloat(i, j float64) (float64, error) { return i + j, nil }
+
+func subFloat(i, j float64) (float64, error) { return i - j, nil }
+
+func mulFloat(i, j float64) (float64, error) { return i * j, nil }
+
+// divFloat returns ErrDivisionByZero rather than +Inf or NaN, so it behaves
+// the same as div.
+func divFloat(i, j float64) (float64, error) {
+	if j == 0 {
+		return 0, ErrDivisionByZero
+	}
+	return i / j, nil
+}
+
+// modFloat is the remainder of truncated division, with the sign of i, like
+// % on ints.
+func modFloat(i, j float64) (float64, error) {
+	if j == 0 {
+		return 0, ErrDivisionByZero
+	}
+	return math.Mod(i, j), nil
+}
+
+// powFloat rejects results that aren't real numbers, such as -8 ** 0.5.
+func powFloat(i, j float64) (float64, error) {
+	r := math.Pow(i, j)
+	if math.IsNaN(r) {
+		return 0, fmt.Errorf("%g ** %g is not a real number", i, j)
+	}
+	return r, nil
+}
+
+var opMapFloat = map[string]floatOpFuncType{
+	"+":  addFloat,
+	"-":  subFloat,
+	"*":  mulFloat,
+	"/":  divFloat,
+	"%":  modFloat,
+	"**": powFloat,
+}
+
+// mode picks the kind of arithmetic calculate uses.
+type mode int
+
+const (
+	// autoMode uses ints, unless an operand is a fraction, which switches to
+	// float64, or too big for an int, which switches to big.Int
+	autoMode mode = iota
+	floatMode
+	bigMode
+)
+
+// looksLikeNumber reports whether tok is a number, even one too big for a
+// float64, rather than an operator.
+func looksLikeNumber(tok string) bool {
+	_, err := strconv.ParseFloat(tok, 64)
+	return err == nil || errors.Is(err, strconv.ErrRange)
+}
+
+// checkTokens checks that expression alternates between numbers and
+// operators, starting and ending with a number. The error names the first
+// token that's out of place.
+func checkTokens(expression []string) error {
+	for i, tok := range expression {
+		_, isOp := precedence[tok]
+		switch {
+		case i%2 == 0 && isOp:
+			return &ParseError{Token: tok, Pos: tokenOffset(expression, i), Msg: "expected number"}
+		case i%2 == 1 && looksLikeNumber(tok):
+			return &ParseError{Token: tok, Pos: tokenOffset(expression, i), Msg: "expected operator"}
+		case i%2 == 1 && !isOp:
+			return unsupportedOperator(tok)
+		}
+	}
+	if len(expression)%2 == 0 {
+		return &ParseError{Pos: len(strings.Join(expression, " ")), Msg: "expected number"}
+	}
+	return nil
+}
+
+// calculate evaluates an expression split into tokens, numbers alternating
+// with operators, using the arithmetic for m. With more than one operator
+// the usual precedence applies, so {"1", "+", "2", "*", "3"} is 7.
+func calculate(expression []string, m mode) (string, error) {
+	if err := checkTokens(expression); err != nil {
+		return "", err
+	}
+	switch {
+	case len(expression) == 1:
+		// Adding zero checks the number and writes it the usual way
+		return calculate([]string{expression[0], "+", "0"}, m)
+	case len(expression) > 3:
+		return calculateChain(expression, m)
+	}
+	p1, err1 := strconv.Atoi(expression[0])
+	p2, err2 := strconv.Atoi(expression[2])
+	if m == autoMode && (errors.Is(err1, strconv.ErrRange) || errors.Is(err2, strconv.ErrRange)) {
+		m = bigMode
+	}
+	switch {
+	case m == bigMode:
+		return calculateBig(expression)
+	case m == autoMode && err1 == nil && err2 == nil:
+		opFunc, ok := opMap[expression[1]]
+		if !ok {
+			return "", unsupportedOperator(expression[1])
+		}
+		result, err := opFunc(p1, p2)
+		if err != nil {
+			return "", err
+		}
+		return strconv.Itoa(result), nil
+	}
+
+	f1, err := strconv.ParseFloat(expression[0], 64)
+	if err != nil {
+		return "", invalidNumber(expression, 0, err)
+	}
+	opFunc, ok := opMapFloat[expression[1]]
+	if !ok {
+		return "", unsupportedOperator(expression[1])
+	}
+	f2, err := strconv.ParseFloat(expression[2], 64)
+	if err != nil {
+		return "", invalidNumber(expression, 2, err)
+	}
+	result, err := opFunc(f1, f2)
+	if err != nil {
+		return "", err
+	}
+	return strconv.FormatFloat(result, 'g', -1, 64), nil
+}
+
+// calculateChain evaluates an expression with more than one operator by
+// precedence climbing, working out one three part expression at a time with
+// calculate.
+func calculateChain(expression []string, m mode) (string, error) {
+	next := 0
+	// climb returns the value of the operand at next and any operators after
+	// it binding at least as tightly as minPrec. i is the operand's index in
+	// expression, or -1 once it has been combined with another.
+	var climb func(minPrec int) (value string, i int, err error)
+	climb = func(minPrec int) (string, int, error) {
+		lhs, lhsIdx := expression[next], next
+		next++
+		for next < len(expression) {
+			op := expression[next]
+			prec := precedence[op]
+			if prec < minPrec {
+				break
+			}
+			next++
+			nextMin := prec + 1
+			if rightAssoc[op] {
+				nextMin = prec
+			}
+			rhs, rhsIdx, err := climb(nextMin)
+			if err != nil {
+				return "", -1, err
+			}
+			result, err := calculate([]string{lhs, op, rhs}, m)
+			if err != nil {
+				// Point parse errors at the operand in the whole expression
+				// rather than in the three part one
+				var pe *ParseError
+				if errors.As(err, &pe) {
+					i := lhsIdx
+					if pe.Pos > 0 {
+						i = rhsIdx
+					}
+					if i >= 0 {
+						pe.Pos = tokenOffset(expression, i)
+					}
+				}
+				return "", -1, err
+			}
+			lhs, lhsIdx = result, -1
+		}
+		return lhs, lhsIdx, nil
+	}
+	result, _, err := climb(1)
+	return result, err
+}
+
+func calculateBig(expression []string) (string, error) {
+	b1, ok := new(big.Int).SetString(expression[0], 10)
+	if !ok {
+		return "", invalidNumber(expression, 0, nil)
+	}
+	opFunc, ok := opMapBig[expression[1]]
+	if !ok {
+		return "", unsupportedOperator(expression[1])
+	}
+	b2, ok := new(big.Int).SetString(expression[2], 10)
+	if !ok {
+		return "", invalidNumber(expression, 2, nil)
+	}
+	result, err := opFunc(b1, b2)
+	if err != nil {
+		return "", err
+	}
+	return result.String(), nil
+}
+
+// printError writes why expr failed to w. Parse errors point at the
+// offending token.
+func printError(w io.Writer, expr string, err error) {
+	fmt.Fprintf(w, "%q: %v\n", expr, err)
+	var pe *ParseError
+	if errors.As(err, &pe) {
+		fmt.Fprintf(w, "  %s\n  %s^\n", expr, strings.Repeat(" ", pe.Pos))
+	}
+}
+
+// Exit codes returned by run. When some expressions fail to parse and others
+// fail to evaluate, the parse error code wins.
+const (
+	exitOK = 0
+	// exitEvalError means an expression was well formed but couldn't be
+	// evaluated, such as a division by zero or an overflow
+	exitEvalError = 1
+	// exitParseError means an expression wasn't well formed
+	exitParseError = 2
+	// exitUsage means the command line was wrong
+	exitUsage = 3
+	// exitInputError means the input couldn't be read, or for -json wasn't
+	// in the right shape
+	exitInputError = 4
+)
+
+// isParseError reports whether err means an expression wasn't well formed,
+// rather than that evaluating it failed.
+func isParseError(err error) bool {
+	var pe *ParseError
+	return errors.As(err, &pe) || errors.Is(err, ErrUnsupportedOperator)
+}
+
+// failures counts the expressions that failed, split by whether they
+// failed to parse or to evaluate.
+type failures struct {
+	parse, eval int
+}
+
+func (f *failures) add(err error) {
+	if isParseError(err) {
+		f.parse++
+	} else {
+		f.eval++
+	}
+}
+
+func (f failures) total() int {
+	return f.parse + f.eval
+}
+
+func (f failures) exitCode() int {
+	switch {
+	case f.parse > 0:
+		return exitParseError
+	case f.eval > 0:
+		return exitEvalError
+	}
+	return exitOK
+}
+
+// run is the whole calculator command. Expressions given as arguments are
+// evaluated in turn, otherwise -f, -json or -repl says where to read them
+// from, and with none of those it runs the built-in examples. Results go to
+// stdout and errors to stderr, prefixed with the expression that failed. It
+// returns exitOK only if every expression worked. The examples always
+// return exitOK, some of them are meant to fail.
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("calc", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	useFloat := fs.Bool("float", false, "use floating point arithmetic even when both operands are integers")
+	useBig := fs.Bool("big", false, "use arbitrary precision integers")
+	interactive := fs.Bool("repl", false, "read expressions from stdin instead of running the examples")
+	historySize := fs.Int("history", 20, "number of lines the REPL keeps for the history command")
+	file := fs.String("f", "", "evaluate each line of the named file instead of running the examples")
+	batch := fs.Bool("json", false, "read a JSON array of expressions from stdin and write the results as JSON")
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return exitOK
+		}
+		return exitUsage
+	}
+	m := autoMode
+	switch {
+	case *useFloat && *useBig:
+		fmt.Fprintln(stderr, "Error: -float and -big can't be used together")
+		return exitUsage
+	case *useFloat:
+		m = floatMode
+	case *useBig:
+		m = bigMode
+	}
+
+	var f failures
+	switch {
+	case *file != "":
+		in, err := os.Open(*file)
+		if err != nil {
+			fmt.Fprintln(stderr, "Error:", err)
+			return exitInputError
+		}
+		err = evalLines(in, stdout, stderr, m, &f)
+		in.Close()
+		if err != nil {
+			fmt.Fprintln(stderr, "Error:", err)
+			return exitInputError
+		}
+		if f.total() > 0 {
+			fmt.Fprintf(stderr, "%d of the expressions failed\n", f.total())
+		}
+	case *batch:
+		if err := evalJSON(stdin, stdout, m, &f); err != nil {
+			fmt.Fprintln(stderr, "Error:", err)
+			return exitInputError
+		}
+	case *interactive:
+		if err := repl(stdin, stdout, stderr, m, *historySize, &f); err != nil {
+			fmt.Fprintln(stderr, "Error:", err)
+			return exitInputError
+		}
+	case fs.NArg() > 0:
+		for _, expr := range fs.Args() {
+			result, err := evalLine(expr, m)
+			if err != nil {
+				f.add(err)
+				printError(stderr, expr, err)
+				continue
+			}
+			fmt.Fprintln(stdout, result)
+		}
+	default:
+		examples(stdout, stderr, m)
+	}
+	return f.exitCode()
 }
 
 func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+// examples shows off the calculator, including plenty of expressions that
+// fail.
+func examples(stdout, stderr io.Writer, m mode) {
 	expressions := [][]string{
 		{"2", "+", "3"},
 		{"2", "-", "3"},
 		{"2", "*", "3"},
 		{"2", "/", "3"},
 		{"2", "%", "3"},
+		{"2", "%", "0"},
+		{"2", "**", "10"},
+		{"2", "**", "-1"},
+		{"2", "**", "64"},
+		{"2", "**", "62"},
+		{"12", "&", "10"},
+		{"12", "|", "10"},
+		{"12", "^", "10"},
+		{"1", "<<", "62"},
+		{"1", "<<", "63"},
+		{"1", "<<", "64"},
+		{"-16", ">>", "2"},
+		{"16", ">>", "-1"},
+		{"9223372036854775807", "+", "1"},
+		{"9223372036854775806", "+", "1"},
+		{"-9223372036854775808", "-", "1"},
+		{"-9223372036854775807", "-", "1"},
+		{"-9223372036854775808", "*", "-1"},
+		{"-9223372036854775808", "/", "-1"},
+		{"2.5", "+", "1.5"},
+		{"1", "+", "2", "+", "3"},
+		{"1", "+", "2", "*", "3", "-", "4"},
+		{"2", "**", "3", "**", "2"},
+		{"1.5", "*", "2", "+", "9223372036854775807", "*", "2"},
+		{"1", "+", "x", "*", "2"},
+		{"1", "+", "2", "+"},
+		{"1", "+", "2", "3", "4"},
+		{"1", "*", "/", "2", "3"},
+		{"7"},
+		{},
+		{"7", "/", "2.0"},
+		{"1.5", "/", "0"},
+		{"99999999999999999999", "*", "99999999999999999999"},
 		{"two", "+", "three"},
 		{"5"},
 		{"2", "/", "0"},
 	}
 
 	for _, expression := range expressions {
-		if len(expression) != 3 {
-			fmt.Println("Invalid expression:", expression)
-			continue
-		}
-		p1, err := strconv.Atoi(expression[0])
+		result, err := calculate(expression, m)
 		if err != nil {
-			fmt.Println(err)
-			continue
-		}
-
-		opFunc, ok := opMap[expression[1]]
-		if !ok {
-			fmt.Println("unsupported operator:", expression[1])
+			printError(stderr, strings.Join(expression, " "), err)
 			continue
 		}
+		fmt.Fprintln(stdout, result)
+	}
 
-		p2, err := strconv.Atoi(expression[2])
+	for _, expr := range []string{"2 + 3 * (4 - 1)", "2 - 3 - 4", "2 ** 3 ** 2", "12+34*2", "1 << 3 + 1", "1 + 2 & 3", "6 & 3 | 8", "1 | 2 ^ 3", "max(3, min(7, 5))", "abs(-4) + gcd(12, 18)", "pow(2, 10)", "max(1)", "foo(1)", "-3 + 5", "2 * -4", "-(2+3)", "2 - -3", "--3", "-2 ** 2", "2.5+1.5", "(1 + 2", "1 +", "", "2 $ 3"} {
+		result, err := evalLine(expr, m)
 		if err != nil {
-			fmt.Println(err)
+			printError(stderr, expr, err)
 			continue
 		}
+		fmt.Fprintf(stdout, "%s = %s\n", expr, result)
+	}
 
-		result, err := opFunc(p1, p2)
-		if err != nil {
-			fmt.Println(err)
-			continue
+	failed, err := EvalLines(strings.NewReader("1 + 2\n\n2 / 0\n(1 + 2) * 3\n"), stdout)
+	fmt.Fprintln(stdout, failed, err)
+	// A failed line leaves ans alone
+	if err := repl(strings.NewReader("2 + 3\nans / 0\nans * 2\n!1\nhistory\n"), stdout, stderr, m, 3, &failures{}); err != nil {
+		fmt.Fprintln(stdout, err)
+	}
+	if err := EvalJSON(strings.NewReader(`{"expressions": ["6 * 7", "1 % 0"]}`), stdout); err != nil {
+		fmt.Fprintln(stdout, err)
+	}
+
+	ev := NewEvaluator()
+	gcd := func(a, b int) (int, error) {
+		for b != 0 {
+			a, b = b, a%b
+		}
+		if a < 0 {
+			a = -a
 		}
-		fmt.Println(result)
+		return a, nil
+	}
+	if err := ev.RegisterOp("gcd", 2, gcd); err != nil {
+		fmt.Fprintln(stdout, err)
+	}
+	result, err := ev.Eval("12 gcd 18 + 1")
+	fmt.Fprintln(stdout, result, err)
+	fmt.Fprintln(stdout, ev.RegisterOp("gcd", 2, gcd))
+	fmt.Fprintln(stdout, ev.RegisterOp("", 1, gcd))
+
+	clamp := func(args []int) (int, error) {
+		return min(max(args[0], args[1]), args[2]), nil
+	}
+	if err := ev.RegisterFunc("clamp", 3, clamp); err != nil {
+		fmt.Fprintln(stdout, err)
 	}
+	result, err = ev.Eval("clamp(12 gcd 18 * 4, 0, 10)")
+	fmt.Fprintln(stdout, result, err)
+	fmt.Fprintln(stdout, ev.RegisterFunc("max", 2, clamp))
 }
@@ -0,0 +1,244 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCalculateFloat(t *testing.T) {
+	tests := []struct {
+		expr    []string
+		want    string
+		wantErr error
+	}{
+		{[]string{"7", "%", "3"}, "1", nil},
+		{[]string{"-7.5", "%", "2"}, "-1.5", nil},
+		{[]string{"2", "%", "0"}, "", ErrDivisionByZero},
+		{[]string{"2", "**", "10"}, "1024", nil},
+		{[]string{"2", "**", "-1"}, "0.5", nil},
+		{[]string{"2", "**", "0.5"}, "1.4142135623730951", nil},
+	}
+	for _, tt := range tests {
+		got, err := calculate(tt.expr, floatMode)
+		if !errors.Is(err, tt.wantErr) || got != tt.want {
+			t.Errorf("calculate(%q, floatMode) = %q, %v, want %q, %v", tt.expr, got, err, tt.want, tt.wantErr)
+		}
+	}
+	if _, err := calculate([]string{"-8", "**", "0.5"}, floatMode); err == nil {
+		t.Error("calculate(-8 ** 0.5, floatMode) succeeded, want an error")
+	}
+}
+
+func TestIntOverflow(t *testing.T) {
+	tests := []struct {
+		name string
+		op   opFuncType
+		i, j int
+		want int
+	}{
+		// The largest results that still fit
+		{"+", add, math.MaxInt64 - 1, 1, math.MaxInt64},
+		{"+", add, math.MinInt64 + 1, -1, math.MinInt64},
+		{"-", sub, math.MinInt64 + 1, 1, math.MinInt64},
+		{"-", sub, -1, math.MaxInt64, math.MinInt64},
+		{"*", mul, math.MaxInt64, -1, math.MinInt64 + 1},
+		{"*", mul, math.MinInt64 / 2, 2, math.MinInt64},
+		{"*", mul, math.MinInt64, 1, math.MinInt64},
+		{"/", div, math.MinInt64, 1, math.MinInt64},
+		{"**", pow, 2, 62, 1 << 62},
+		{"**", pow, -2, 63, math.MinInt64},
+		{"**", pow, 3, 39, 4052555153018976267},
+		{"**", pow, -1, math.MaxInt64, -1},
+	}
+	for _, tt := range tests {
+		if got, err := tt.op(tt.i, tt.j); err != nil || got != tt.want {
+			t.Errorf("%d %s %d = %d, %v, want %d", tt.i, tt.name, tt.j, got, err, tt.want)
+		}
+	}
+
+	overflows := []struct {
+		name string
+		op   opFuncType
+		i, j int
+	}{
+		{"+", add, math.MaxInt64, 1},
+		{"+", add, math.MinInt64, -1},
+		{"+", add, math.MaxInt64, math.MaxInt64},
+		{"-", sub, math.MinInt64, 1},
+		{"-", sub, math.MaxInt64, -1},
+		{"-", sub, 0, math.MinInt64},
+		{"*", mul, math.MinInt64, -1},
+		{"*", mul, -1, math.MinInt64},
+		{"*", mul, math.MaxInt64, 2},
+		{"*", mul, 1 << 32, 1 << 31},
+		{"/", div, math.MinInt64, -1},
+		{"**", pow, 2, 63},
+		{"**", pow, 2, 64},
+		{"**", pow, -2, 64},
+		{"**", pow, 3, 40},
+	}
+	for _, tt := range overflows {
+		if got, err := tt.op(tt.i, tt.j); !errors.Is(err, ErrOverflow) {
+			t.Errorf("%d %s %d = %d, %v, want ErrOverflow", tt.i, tt.name, tt.j, got, err)
+		}
+	}
+}
+
+func TestCalculateOverflow(t *testing.T) {
+	tests := []struct {
+		expr []string
+		want string
+	}{
+		{[]string{"9223372036854775806", "+", "1"}, "9223372036854775807"},
+		{[]string{"-9223372036854775807", "-", "1"}, "-9223372036854775808"},
+		{[]string{"2", "**", "62"}, "4611686018427387904"},
+	}
+	for _, tt := range tests {
+		if got, err := calculate(tt.expr, autoMode); err != nil || got != tt.want {
+			t.Errorf("calculate(%q) = %q, %v, want %q", tt.expr, got, err, tt.want)
+		}
+	}
+	for _, expr := range [][]string{
+		{"9223372036854775807", "+", "1"},
+		{"-9223372036854775808", "-", "1"},
+		{"-9223372036854775808", "*", "-1"},
+		{"-9223372036854775808", "/", "-1"},
+		{"2", "**", "64"},
+		{"1", "+", "9223372036854775807", "*", "2"},
+	} {
+		if got, err := calculate(expr, autoMode); !errors.Is(err, ErrOverflow) {
+			t.Errorf("calculate(%q) = %q, %v, want ErrOverflow", expr, got, err)
+		}
+	}
+	// Big mode never overflows
+	if got, err := calculate([]string{"9223372036854775807", "+", "1"}, bigMode); err != nil || got != "9223372036854775808" {
+		t.Errorf("calculate(MaxInt64 + 1, bigMode) = %q, %v", got, err)
+	}
+	// Nor does Eval wrap around, including when negating MinInt64
+	for _, expr := range []string{"9223372036854775807 + 1", "-(-9223372036854775807 - 1)", "2 ** 63"} {
+		if got, err := Eval(expr); !errors.Is(err, ErrOverflow) {
+			t.Errorf("Eval(%q) = %d, %v, want ErrOverflow", expr, got, err)
+		}
+	}
+}
+
+func TestModPow(t *testing.T) {
+	tests := []struct {
+		expr    []string
+		want    string
+		wantErr string
+	}{
+		{[]string{"7", "%", "3"}, "1", ""},
+		// The remainder has the sign of the dividend, like Go's %
+		{[]string{"-7", "%", "3"}, "-1", ""},
+		{[]string{"7", "%", "-3"}, "1", ""},
+		{[]string{"6", "%", "3"}, "0", ""},
+		{[]string{"-9223372036854775808", "%", "-1"}, "0", ""},
+		{[]string{"2", "%", "0"}, "", "division by zero"},
+		{[]string{"0", "%", "0"}, "", "division by zero"},
+		{[]string{"2", "**", "10"}, "1024", ""},
+		{[]string{"2", "**", "0"}, "1", ""},
+		{[]string{"0", "**", "0"}, "1", ""},
+		{[]string{"-3", "**", "3"}, "-27", ""},
+		{[]string{"2", "**", "-1"}, "", "negative exponent"},
+		{[]string{"1", "**", "-1"}, "", "negative exponent"},
+		{[]string{"2", "**", "63"}, "", "integer overflow"},
+		{[]string{"10", "**", "19"}, "", "integer overflow"},
+		{[]string{"10", "**", "18"}, "1000000000000000000", ""},
+	}
+	for _, tt := range tests {
+		got, err := calculate(tt.expr, autoMode)
+		gotErr := ""
+		if err != nil {
+			gotErr = err.Error()
+		}
+		if got != tt.want || gotErr != tt.wantErr {
+			t.Errorf("calculate(%q) = %q, %q, want %q, %q", tt.expr, got, gotErr, tt.want, tt.wantErr)
+		}
+	}
+}
+
+// runCalc runs the calculator with args and stdin, and returns its exit code
+// and what it wrote to stdout and stderr.
+func runCalc(args []string, stdin string) (code int, stdout, stderr string) {
+	var out, errOut bytes.Buffer
+	code = run(args, strings.NewReader(stdin), &out, &errOut)
+	return code, out.String(), errOut.String()
+}
+
+func TestRunExitCodes(t *testing.T) {
+	missing := filepath.Join(t.TempDir(), "missing.txt")
+	tests := []struct {
+		name  string
+		args  []string
+		stdin string
+		want  int
+	}{
+		{"every expression works", []string{"1 + 2", "3 * 4"}, "", exitOK},
+		{"help", []string{"-h"}, "", exitOK},
+		{"evaluation error", []string{"1 + 2", "1 / 0"}, "", exitEvalError},
+		{"overflow", []string{"9223372036854775807 + 1"}, "", exitEvalError},
+		{"parse error", []string{"1 +"}, "", exitParseError},
+		{"unsupported operator", []string{"-float", "6 & 3"}, "", exitParseError},
+		{"parse error wins", []string{"1 / 0", "(1"}, "", exitParseError},
+		{"unknown flag", []string{"-nope"}, "", exitUsage},
+		{"float and big", []string{"-float", "-big", "1"}, "", exitUsage},
+		{"missing file", []string{"-f", missing}, "", exitInputError},
+		{"bad JSON", []string{"-json"}, "not json", exitInputError},
+		{"JSON in the wrong shape", []string{"-json"}, `"1 + 2"`, exitInputError},
+		{"JSON evaluation error", []string{"-json"}, `["1 + 2", "1 % 0"]`, exitEvalError},
+		{"REPL parse error", []string{"-repl"}, "1 + 2\n2 $ 3\n", exitParseError},
+		{"REPL", []string{"-repl"}, "1 + 2\nans * 2\n", exitOK},
+	}
+	for _, tt := range tests {
+		if code, stdout, stderr := runCalc(tt.args, tt.stdin); code != tt.want {
+			t.Errorf("%s: run(%q) = %d, want %d\nstdout:\n%s\nstderr:\n%s", tt.name, tt.args, code, tt.want, stdout, stderr)
+		}
+	}
+}
+
+// TestRunStreams checks that results go to stdout and errors to stderr,
+// prefixed with the expression that failed, so the results can be piped.
+func TestRunStreams(t *testing.T) {
+	code, stdout, stderr := runCalc([]string{"1 + 2", "1 / 0", "2 * 3", "1 + )"}, "")
+	if code != exitParseError {
+		t.Errorf("exit code = %d, want %d", code, exitParseError)
+	}
+	if want := "3\n6\n"; stdout != want {
+		t.Errorf("stdout = %q, want %q", stdout, want)
+	}
+	wantErr := `"1 / 0": division by zero` + "\n" +
+		`"1 + )": expected number or '(' at offset 4, found ")"` + "\n" +
+		"  1 + )\n" +
+		"      ^\n"
+	if stderr != wantErr {
+		t.Errorf("stderr = %q, want %q", stderr, wantErr)
+	}
+
+	// Lines from a file are echoed with their results, and failures counted
+	file := filepath.Join(t.TempDir(), "exprs.txt")
+	if err := os.WriteFile(file, []byte("1 + 2\n\n2 / 0\n(1 + 2) * 3\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	code, stdout, stderr = runCalc([]string{"-f", file}, "")
+	if code != exitEvalError {
+		t.Errorf("-f: exit code = %d, want %d", code, exitEvalError)
+	}
+	if want := "1 + 2 = 3\n(1 + 2) * 3 = 9\n"; stdout != want {
+		t.Errorf("-f: stdout = %q, want %q", stdout, want)
+	}
+	if want := "2 / 0 : line 3: division by zero\n1 of the expressions failed\n"; stderr != want {
+		t.Errorf("-f: stderr = %q, want %q", stderr, want)
+	}
+
+	// An input error goes to stderr only
+	_, stdout, stderr = runCalc([]string{"-f", filepath.Join(t.TempDir(), "missing.txt")}, "")
+	if stdout != "" || !strings.HasPrefix(stderr, "Error: ") {
+		t.Errorf("missing file: stdout = %q, stderr = %q", stdout, stderr)
+	}
+}
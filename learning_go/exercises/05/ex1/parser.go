@@ -0,0 +1,353 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+)
+
+// precedence says how tightly each binary operator binds, higher binds
+// tighter. The bitwise operators follow Go's rules, so & and the shifts bind
+// like * and | and ^ like +. ** is also right associative, so 2 ** 3 ** 2 is
+// 2 ** 9.
+var precedence = map[string]int{
+	"+":  1,
+	"-":  1,
+	"|":  1,
+	"^":  1,
+	"*":  2,
+	"/":  2,
+	"%":  2,
+	"&":  2,
+	"<<": 2,
+	">>": 2,
+	"**": 3,
+}
+
+var rightAssoc = map[string]bool{"**": true}
+
+// unaryPrecedence is how tightly unary minus binds, the same as **.
+const unaryPrecedence = 3
+
+// token is a number, operator or parenthesis, along with the byte offset it
+// starts at in the input.
+type token struct {
+	Text string
+	Pos  int
+}
+
+func (e *Evaluator) tokenize(s string) ([]token, error) {
+	var tokens []token
+	ops := e.symbols
+	i := 0
+outer:
+	for i < len(s) {
+		c := s[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\r' || c == '\n':
+			i++
+			continue
+		case c >= '0' && c <= '9':
+			// A number may have a fractional part for float mode, the
+			// integer parser rejects it later
+			start := i
+			for i < len(s) && s[i] >= '0' && s[i] <= '9' {
+				i++
+			}
+			if i+1 < len(s) && s[i] == '.' && s[i+1] >= '0' && s[i+1] <= '9' {
+				i++
+				for i < len(s) && s[i] >= '0' && s[i] <= '9' {
+					i++
+				}
+			}
+			tokens = append(tokens, token{Text: s[start:i], Pos: start})
+			continue
+		case c == '(' || c == ')' || c == ',':
+			tokens = append(tokens, token{Text: string(c), Pos: i})
+			i++
+			continue
+		case c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z'):
+			// A function name, or an operator spelt with letters such as
+			// gcd, which the parser tells apart by where it is
+			start := i
+			for i < len(s) && isIdent(s[start:i+1]) {
+				i++
+			}
+			tokens = append(tokens, token{Text: s[start:i], Pos: start})
+			continue
+		}
+		for _, op := range ops {
+			if len(s)-i >= len(op) && s[i:i+len(op)] == op {
+				tokens = append(tokens, token{Text: op, Pos: i})
+				i += len(op)
+				continue outer
+			}
+		}
+		return nil, &ParseError{Token: s[i : i+1], Pos: i, Msg: "invalid character"}
+	}
+	return tokens, nil
+}
+
+// Tokenize splits an expression into numbers, operators and parentheses,
+// using the built-in operators. See Evaluator.Tokenize.
+func Tokenize(s string) ([]string, error) {
+	return defaultEvaluator.Tokenize(s)
+}
+
+// Tokenize splits an expression into numbers, operators, names, parentheses
+// and commas. Spaces between tokens are optional, so "12+34*2" and
+// "12 + 34 * 2" give the same tokens. The error for an invalid character
+// gives its byte offset.
+func (e *Evaluator) Tokenize(s string) ([]string, error) {
+	tokens, err := e.tokenize(s)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]string, len(tokens))
+	for i, t := range tokens {
+		out[i] = t.Text
+	}
+	return out, nil
+}
+
+// arith is the arithmetic an expression is evaluated with: how to read and
+// negate a number, and the operators and functions that work on it.
+// Operators that have a precedence but no function for T are rejected as
+// unsupported, such as & on floats.
+type arith[T any] struct {
+	parse func(string) (T, error)
+	neg   func(T) (T, error)
+	op    func(symbol string) (func(T, T) (T, error), bool)
+	fn    func(name string) (function[T], bool)
+}
+
+// parser is a recursive descent parser over a list of tokens. Binary
+// operators are handled by precedence climbing, using the precedences
+// registered with the Evaluator.
+type parser[T any] struct {
+	ev     *Evaluator
+	ar     arith[T]
+	tokens []token
+	pos    int
+	// end is the offset just past the input, used in errors about running
+	// out of tokens
+	end int
+}
+
+func (p *parser[T]) peek() (token, bool) {
+	if p.pos >= len(p.tokens) {
+		return token{Pos: p.end}, false
+	}
+	return p.tokens[p.pos], true
+}
+
+// expr parses operands joined by operators that bind at least as tightly as
+// minPrec.
+func (p *parser[T]) expr(minPrec int) (T, error) {
+	var zero T
+	left, err := p.operand()
+	if err != nil {
+		return zero, err
+	}
+	for {
+		t, ok := p.peek()
+		if !ok {
+			return left, nil
+		}
+		prec, isOp := p.ev.precedence[t.Text]
+		if !isOp || prec < minPrec {
+			return left, nil
+		}
+		p.pos++
+		next := prec + 1
+		if p.ev.rightAssoc[t.Text] {
+			next = prec
+		}
+		right, err := p.expr(next)
+		if err != nil {
+			return zero, err
+		}
+		op, ok := p.ar.op(t.Text)
+		if !ok {
+			return zero, unsupportedOperator(t.Text)
+		}
+		left, err = op(left, right)
+		if err != nil {
+			return zero, err
+		}
+	}
+}
+
+// operand parses a number, a parenthesised expression, a function call or a
+// negated operand. A - is unary wherever an operand is expected: at the
+// start, after another operator and after '(' or ','. It binds tighter than
+// the built-in operators but **, so -2 ** 2 is -4. Each - negates again, so
+// --3 is 3.
+func (p *parser[T]) operand() (T, error) {
+	var zero T
+	t, ok := p.peek()
+	if !ok {
+		return zero, &ParseError{Pos: p.end, Msg: "expected number or '('"}
+	}
+	p.pos++
+	switch {
+	case t.Text == "-":
+		v, err := p.expr(unaryPrecedence)
+		if err != nil {
+			return zero, err
+		}
+		return p.ar.neg(v)
+	case t.Text == "(":
+		v, err := p.expr(1)
+		if err != nil {
+			return zero, err
+		}
+		closing, ok := p.peek()
+		if !ok || closing.Text != ")" {
+			return zero, &ParseError{Token: closing.Text, Pos: closing.Pos, Msg: fmt.Sprintf("expected ')' to close '(' at offset %d", t.Pos)}
+		}
+		p.pos++
+		return v, nil
+	case isIdent(t.Text):
+		return p.call(t)
+	case t.Text[0] >= '0' && t.Text[0] <= '9':
+		v, err := p.ar.parse(t.Text)
+		if err != nil {
+			return zero, &ParseError{Token: t.Text, Pos: t.Pos, Msg: "invalid number", Err: err}
+		}
+		return v, nil
+	}
+	return zero, &ParseError{Token: t.Text, Pos: t.Pos, Msg: "expected number or '('"}
+}
+
+// call parses the arguments of a call to the function named by name, whose
+// token has already been read, and calls it.
+func (p *parser[T]) call(name token) (T, error) {
+	var zero T
+	f, ok := p.ar.fn(name.Text)
+	if !ok {
+		return zero, &ParseError{Token: name.Text, Pos: name.Pos, Msg: "unknown function", Err: ErrUnknownFunction}
+	}
+	if t, ok := p.peek(); !ok || t.Text != "(" {
+		return zero, &ParseError{Token: t.Text, Pos: t.Pos, Msg: fmt.Sprintf("expected '(' after %s", name.Text)}
+	}
+	p.pos++
+	var args []T
+	if t, ok := p.peek(); ok && t.Text == ")" {
+		p.pos++
+	} else {
+		for {
+			v, err := p.expr(1)
+			if err != nil {
+				return zero, err
+			}
+			args = append(args, v)
+			t, ok := p.peek()
+			if ok && t.Text == "," {
+				p.pos++
+				continue
+			}
+			if !ok || t.Text != ")" {
+				return zero, &ParseError{Token: t.Text, Pos: t.Pos, Msg: fmt.Sprintf("expected ',' or ')' in call to %s", name.Text)}
+			}
+			p.pos++
+			break
+		}
+	}
+	if len(args) != f.arity {
+		unit := "arguments"
+		if f.arity == 1 {
+			unit = "argument"
+		}
+		return zero, &ParseError{Token: name.Text, Pos: name.Pos, Msg: fmt.Sprintf("%s takes %d %s, got %d", name.Text, f.arity, unit, len(args))}
+	}
+	return f.fn(args)
+}
+
+// evaluate evaluates an infix expression using the operators, precedences
+// and functions registered with e, and the arithmetic of ar.
+func evaluate[T any](e *Evaluator, ar arith[T], expr string) (T, error) {
+	var zero T
+	tokens, err := e.tokenize(expr)
+	if err != nil {
+		return zero, err
+	}
+	if len(tokens) == 0 {
+		return zero, &ParseError{Pos: len(expr), Msg: "expected an expression"}
+	}
+	p := &parser[T]{ev: e, ar: ar, tokens: tokens, end: len(expr)}
+	v, err := p.expr(1)
+	if err != nil {
+		return zero, err
+	}
+	if t, ok := p.peek(); ok {
+		return zero, &ParseError{Token: t.Text, Pos: t.Pos, Msg: "expected operator"}
+	}
+	return v, nil
+}
+
+// Eval evaluates an infix expression using the built-in operators. See
+// Evaluator.Eval.
+func Eval(expr string) (int, error) {
+	return defaultEvaluator.Eval(expr)
+}
+
+// Eval evaluates an infix expression such as "2 + 3 * (4 - 1)", with the
+// usual precedence rules.
+func (e *Evaluator) Eval(expr string) (int, error) {
+	return evaluate(e, e.intArith(), expr)
+}
+
+// intArith is int arithmetic with the operators and functions registered
+// with e.
+func (e *Evaluator) intArith() arith[int] {
+	return arith[int]{
+		parse: strconv.Atoi,
+		neg:   func(v int) (int, error) { return sub(0, v) },
+		op: func(symbol string) (func(int, int) (int, error), bool) {
+			f, ok := e.ops[symbol]
+			return f, ok
+		},
+		fn: func(name string) (function[int], bool) {
+			f, ok := e.funcs[name]
+			return f, ok
+		},
+	}
+}
+
+// floatArith is float64 arithmetic with the operators in opMapFloat and
+// the functions in floatFuncMap.
+var floatArith = arith[float64]{
+	parse: func(s string) (float64, error) { return strconv.ParseFloat(s, 64) },
+	neg:   func(v float64) (float64, error) { return -v, nil },
+	op: func(symbol string) (func(float64, float64) (float64, error), bool) {
+		f, ok := opMapFloat[symbol]
+		return f, ok
+	},
+	fn: func(name string) (function[float64], bool) {
+		f, ok := floatFuncMap[name]
+		return f, ok
+	},
+}
+
+// bigArith is big.Int arithmetic with the operators in opMapBig and the
+// functions in bigFuncMap.
+var bigArith = arith[*big.Int]{
+	parse: func(s string) (*big.Int, error) {
+		v, ok := new(big.Int).SetString(s, 10)
+		if !ok {
+			return nil, errors.New("not an integer")
+		}
+		return v, nil
+	},
+	neg: func(v *big.Int) (*big.Int, error) { return new(big.Int).Neg(v), nil },
+	op: func(symbol string) (func(*big.Int, *big.Int) (*big.Int, error), bool) {
+		f, ok := opMapBig[symbol]
+		return f, ok
+	},
+	fn: func(name string) (function[*big.Int], bool) {
+		f, ok := bigFuncMap[name]
+		return f, ok
+	},
+}
@@ -0,0 +1,288 @@
+package main
+
+import (
+	"errors"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// TestUnaryMinus checks that - negates where an operand is expected and
+// subtracts everywhere else, with every kind of arithmetic.
+func TestUnaryMinus(t *testing.T) {
+	tests := []struct {
+		expr string
+		want map[mode]string
+	}{
+		// Binary, even with no spaces
+		{"5-3", map[mode]string{autoMode: "2", floatMode: "2", bigMode: "2"}},
+		{"5 - 3 - 1", map[mode]string{autoMode: "1", floatMode: "1", bigMode: "1"}},
+		// Unary at the start, after an operator and after '('
+		{"-3 + 5", map[mode]string{autoMode: "2", floatMode: "2", bigMode: "2"}},
+		{"2 * -4", map[mode]string{autoMode: "-8", floatMode: "-8", bigMode: "-8"}},
+		{"-(2+3)", map[mode]string{autoMode: "-5", floatMode: "-5", bigMode: "-5"}},
+		{"(-2) * 3", map[mode]string{autoMode: "-6", floatMode: "-6", bigMode: "-6"}},
+		// Binary then unary
+		{"2 - -3", map[mode]string{autoMode: "5", floatMode: "5", bigMode: "5"}},
+		{"2--3", map[mode]string{autoMode: "5", floatMode: "5", bigMode: "5"}},
+		{"2 - - -3", map[mode]string{autoMode: "-1", floatMode: "-1", bigMode: "-1"}},
+		// Each unary - negates again
+		{"--3", map[mode]string{autoMode: "3", floatMode: "3", bigMode: "3"}},
+		{"---3", map[mode]string{autoMode: "-3", floatMode: "-3", bigMode: "-3"}},
+		// Unary - binds looser than ** but tighter than *
+		{"-2 ** 2", map[mode]string{autoMode: "-4", floatMode: "-4", bigMode: "-4"}},
+		{"(-2) ** 2", map[mode]string{autoMode: "4", floatMode: "4", bigMode: "4"}},
+		{"-2 * 3 - 1", map[mode]string{autoMode: "-7", floatMode: "-7", bigMode: "-7"}},
+		{"2 ** -1", map[mode]string{floatMode: "0.5"}},
+		{"-2.5 - 1", map[mode]string{autoMode: "-3.5", floatMode: "-3.5"}},
+	}
+	for _, tt := range tests {
+		for m, want := range tt.want {
+			got, err := evalLine(tt.expr, m)
+			if err != nil || got != want {
+				t.Errorf("evalLine(%q, %d) = %q, %v, want %q", tt.expr, m, got, err, want)
+			}
+		}
+	}
+}
+
+func TestUnaryMinusErrors(t *testing.T) {
+	for _, expr := range []string{"-", "2 -", "2 * -", "-)", "3 - (-)"} {
+		for _, m := range []mode{autoMode, floatMode, bigMode} {
+			_, err := evalLine(expr, m)
+			var pe *ParseError
+			if !errors.As(err, &pe) {
+				t.Errorf("evalLine(%q, %d) error = %v, want a *ParseError", expr, m, err)
+			}
+		}
+	}
+	// Negating the smallest int doesn't fit
+	if _, err := evalLine("-(-9223372036854775807 - 1)", autoMode); !errors.Is(err, ErrOverflow) {
+		t.Errorf("negating MinInt: error = %v, want ErrOverflow", err)
+	}
+}
+
+// TestBitwise checks each bitwise operator and that they bind like Go's, so
+// & and the shifts bind like * and | and ^ like +.
+func TestBitwise(t *testing.T) {
+	tests := []struct {
+		expr string
+		want string
+	}{
+		{"6 & 3", "2"},
+		{"6 | 3", "7"},
+		{"5 ^ 3", "6"},
+		{"1 << 10", "1024"},
+		{"1024 >> 3", "128"},
+		{"6&3", "2"},
+		{"1<<3", "8"},
+		// >> is arithmetic, so it keeps the sign
+		{"-16 >> 2", "-4"},
+		{"-1 >> 63", "-1"},
+		{"-1 << 62", "-4611686018427387904"},
+		// Precedence
+		{"1 << 3 + 1", "9"},
+		{"1 + 3 << 1", "7"},
+		{"1 + 2 & 3", "3"},
+		{"6 & 3 | 8", "10"},
+		{"8 | 6 & 3", "10"},
+		{"1 | 2 ^ 3", "0"},
+		{"2 * 3 << 1", "12"},
+		{"2 ** 2 << 1", "8"},
+		{"(1 + 2) & 3", "3"},
+		{"1 << (3 + 1)", "16"},
+	}
+	for _, tt := range tests {
+		for _, m := range []mode{autoMode, bigMode} {
+			if got, err := evalLine(tt.expr, m); err != nil || got != tt.want {
+				t.Errorf("evalLine(%q, %d) = %q, %v, want %q", tt.expr, m, got, err, tt.want)
+			}
+		}
+		if got, err := Eval(tt.expr); err != nil || strconv.Itoa(got) != tt.want {
+			t.Errorf("Eval(%q) = %d, %v, want %s", tt.expr, got, err, tt.want)
+		}
+	}
+}
+
+func TestShiftErrors(t *testing.T) {
+	tests := []struct {
+		expr    string
+		wantErr string
+	}{
+		{"16 >> -1", "invalid shift count -1: must be between 0 and 63"},
+		{"1 << -1", "invalid shift count -1: must be between 0 and 63"},
+		{"1 << 64", "invalid shift count 64: must be between 0 and 63"},
+		{"1 >> 64", "invalid shift count 64: must be between 0 and 63"},
+		{"1 << 100", "invalid shift count 100: must be between 0 and 63"},
+	}
+	for _, tt := range tests {
+		if got, err := Eval(tt.expr); err == nil || err.Error() != tt.wantErr {
+			t.Errorf("Eval(%q) = %d, %v, want %q", tt.expr, got, err, tt.wantErr)
+		}
+	}
+	// Shifting bits out of an int overflows, except into the sign bit of a
+	// negative number
+	for _, expr := range []string{"1 << 63", "3 << 62", "-3 << 62"} {
+		if got, err := Eval(expr); !errors.Is(err, ErrOverflow) {
+			t.Errorf("Eval(%q) = %d, %v, want ErrOverflow", expr, got, err)
+		}
+	}
+	if got, err := Eval("-1 << 63"); err != nil || got != math.MinInt64 {
+		t.Errorf("Eval(-1 << 63) = %d, %v, want MinInt64", got, err)
+	}
+	// Big numbers can shift further, but not by a negative count
+	if got, err := evalLine("1 << 64", bigMode); err != nil || got != "18446744073709551616" {
+		t.Errorf("evalLine(1 << 64, bigMode) = %q, %v", got, err)
+	}
+	if _, err := evalLine("16 >> -1", bigMode); err == nil {
+		t.Error("evalLine(16 >> -1, bigMode) succeeded")
+	}
+	// Floats have no bitwise operators
+	for _, expr := range []string{"6 & 3", "1 << 2", "1.5 | 1"} {
+		if _, err := evalLine(expr, floatMode); !errors.Is(err, ErrUnsupportedOperator) {
+			t.Errorf("evalLine(%q, floatMode) = %v, want ErrUnsupportedOperator", expr, err)
+		}
+	}
+}
+
+func TestTokenize(t *testing.T) {
+	tests := []struct {
+		expr string
+		want []string
+	}{
+		{"12+34*2", []string{"12", "+", "34", "*", "2"}},
+		{"12 + 34 * 2", []string{"12", "+", "34", "*", "2"}},
+		{"(1+2)*3", []string{"(", "1", "+", "2", ")", "*", "3"}},
+		{"2**3**2", []string{"2", "**", "3", "**", "2"}},
+		{"1<<3>>1", []string{"1", "<<", "3", ">>", "1"}},
+		{"2*-3", []string{"2", "*", "-", "3"}},
+		{"2.5/0.5", []string{"2.5", "/", "0.5"}},
+		{"max(1,min(2,3))", []string{"max", "(", "1", ",", "min", "(", "2", ",", "3", ")", ")"}},
+		{" \t7\r\n", []string{"7"}},
+		{"", []string{}},
+		// Spaces still separate numbers
+		{"1 2", []string{"1", "2"}},
+	}
+	for _, tt := range tests {
+		got, err := Tokenize(tt.expr)
+		if err != nil || !slices.Equal(got, tt.want) {
+			t.Errorf("Tokenize(%q) = %q, %v, want %q", tt.expr, got, err, tt.want)
+		}
+	}
+}
+
+func TestTokenizeErrors(t *testing.T) {
+	tests := []struct {
+		expr    string
+		wantPos int
+	}{
+		{"2 $ 3", 2},
+		{"1+2#", 3},
+		// A dot that isn't followed by a digit isn't part of the number
+		{"1.", 1},
+		{"é", 0},
+	}
+	for _, tt := range tests {
+		_, err := Tokenize(tt.expr)
+		var pe *ParseError
+		if !errors.As(err, &pe) || pe.Pos != tt.wantPos {
+			t.Errorf("Tokenize(%q) error = %v, want a *ParseError at offset %d", tt.expr, err, tt.wantPos)
+		}
+	}
+}
+
+// FuzzTokenize checks that Tokenize doesn't panic, that the tokens are the
+// input with only the spaces dropped, and that an error points into the
+// input.
+func FuzzTokenize(f *testing.F) {
+	for _, s := range []string{"12+34*2", "(1+2)*3", "2 ** 3 ** 2", "max(1, -2.5)", "1 << 63", "2 $ 3", "1.", ""} {
+		f.Add(s)
+	}
+	f.Fuzz(func(t *testing.T, s string) {
+		tokens, err := Tokenize(s)
+		if err != nil {
+			var pe *ParseError
+			if !errors.As(err, &pe) || pe.Pos < 0 || pe.Pos >= len(s) || s[pe.Pos:pe.Pos+len(pe.Token)] != pe.Token {
+				t.Fatalf("Tokenize(%q) error = %#v, want a *ParseError pointing into the input", s, err)
+			}
+			return
+		}
+		stripped := strings.Map(func(r rune) rune {
+			if strings.ContainsRune(" \t\r\n", r) {
+				return -1
+			}
+			return r
+		}, s)
+		if joined := strings.Join(tokens, ""); joined != stripped {
+			t.Fatalf("Tokenize(%q) = %q, which joins to %q, want %q", s, tokens, joined, stripped)
+		}
+		for _, tok := range tokens {
+			if tok == "" {
+				t.Fatalf("Tokenize(%q) = %q, which has an empty token", s, tokens)
+			}
+		}
+	})
+}
+
+func TestEval(t *testing.T) {
+	tests := []struct {
+		expr string
+		want int
+	}{
+		{"2 + 3 * (4 - 1)", 11},
+		// Precedence
+		{"1 + 2 * 3", 7},
+		{"2 * 3 + 1", 7},
+		{"10 - 6 / 2", 7},
+		{"1 + 7 % 4", 4},
+		{"2 * 3 ** 2", 18},
+		// The same precedence associates to the left
+		{"2 - 3 - 4", -5},
+		{"100 / 10 / 5", 2},
+		{"2 * 7 % 4", 2},
+		{"7 % 4 * 2", 6},
+		// Except ** which associates to the right
+		{"2 ** 3 ** 2", 512},
+		{"(2 ** 3) ** 2", 64},
+		// Parentheses
+		{"(2 - 3) - 4", -5},
+		{"2 - (3 - 4)", 3},
+		{"(1 + 2) * 3", 9},
+		{"((((7))))", 7},
+		{"2 * (3 + (4 - (5 - 6)) * 2)", 26},
+		{"(1 + 2) * (3 + 4)", 21},
+		{"42", 42},
+	}
+	for _, tt := range tests {
+		if got, err := Eval(tt.expr); err != nil || got != tt.want {
+			t.Errorf("Eval(%q) = %d, %v, want %d", tt.expr, got, err, tt.want)
+		}
+	}
+}
+
+func TestEvalErrors(t *testing.T) {
+	tests := []struct {
+		expr    string
+		wantErr string
+	}{
+		{"", "expected an expression at end of input"},
+		{"   ", "expected an expression at end of input"},
+		{"(1 + 2", "expected ')' to close '(' at offset 0 at end of input"},
+		{"((1 + 2)", "expected ')' to close '(' at offset 0 at end of input"},
+		{"1 + 2)", `expected operator at offset 5, found ")"`},
+		{"()", `expected number or '(' at offset 1, found ")"`},
+		{"1 +", "expected number or '(' at end of input"},
+		{"1 + 2 *", "expected number or '(' at end of input"},
+		{"* 2", `expected number or '(' at offset 0, found "*"`},
+		{"1 2", `expected operator at offset 2, found "2"`},
+	}
+	for _, tt := range tests {
+		got, err := Eval(tt.expr)
+		var pe *ParseError
+		if !errors.As(err, &pe) || err.Error() != tt.wantErr {
+			t.Errorf("Eval(%q) = %d, %v, want %q", tt.expr, got, err, tt.wantErr)
+		}
+	}
+}
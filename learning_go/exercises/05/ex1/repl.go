@@ -0,0 +1,229 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// evalLine evaluates a whole expression with the arithmetic for m. In auto
+// mode that's ints, unless a number is too big for an int, which switches to
+// big.Int, or has a fractional part, which switches to float64, the same
+// way calculate chooses.
+func evalLine(line string, m mode) (string, error) {
+	if m == autoMode {
+		m = modeFor(line)
+	}
+	switch m {
+	case floatMode:
+		v, err := evaluate(defaultEvaluator, floatArith, line)
+		if err != nil {
+			return "", err
+		}
+		return strconv.FormatFloat(v, 'g', -1, 64), nil
+	case bigMode:
+		v, err := evaluate(defaultEvaluator, bigArith, line)
+		if err != nil {
+			return "", err
+		}
+		return v.String(), nil
+	}
+	v, err := Eval(line)
+	if err != nil {
+		return "", err
+	}
+	return strconv.Itoa(v), nil
+}
+
+// modeFor picks the arithmetic for line in auto mode, looking at its
+// numbers. A line that can't be tokenized gets int arithmetic, which
+// reports the error.
+func modeFor(line string) mode {
+	tokens, err := Tokenize(line)
+	if err != nil {
+		return autoMode
+	}
+	m := autoMode
+	for _, tok := range tokens {
+		if tok[0] < '0' || tok[0] > '9' {
+			continue
+		}
+		_, err := strconv.Atoi(tok)
+		switch {
+		case errors.Is(err, strconv.ErrRange):
+			return bigMode
+		case err != nil:
+			m = floatMode
+		}
+	}
+	return m
+}
+
+// historyEntry is one line evaluated by the REPL. N counts from 1 for the
+// first line of the session and keeps counting as old entries are dropped.
+type historyEntry struct {
+	N      int
+	Expr   string
+	Result string
+	Err    error
+}
+
+// replState is what the REPL remembers from one line to the next.
+type replState struct {
+	m mode
+	// ans is the last successful result, empty until there is one
+	ans        string
+	history    []historyEntry
+	maxHistory int
+	count      int
+}
+
+// eval evaluates line with ans replaced by the last result, and records it
+// in the history. Only successful evaluations change ans. A negative ans is
+// put in parentheses so that ans ** 2 squares it.
+func (s *replState) eval(line string) (string, error) {
+	result, err := s.evalAns(line)
+	s.count++
+	s.history = append(s.history, historyEntry{N: s.count, Expr: line, Result: result, Err: err})
+	if len(s.history) > s.maxHistory {
+		s.history = s.history[len(s.history)-s.maxHistory:]
+	}
+	if err == nil {
+		s.ans = result
+	}
+	return result, err
+}
+
+// evalAns evaluates line with every ans token replaced by the last result.
+// Only whole tokens are replaced, so a function such as trans is left alone.
+func (s *replState) evalAns(line string) (string, error) {
+	tokens, err := defaultEvaluator.tokenize(line)
+	if err != nil {
+		// Let evalLine report it
+		return evalLine(line, s.m)
+	}
+	var b strings.Builder
+	last := 0
+	for _, t := range tokens {
+		if t.Text != "ans" {
+			continue
+		}
+		if s.ans == "" {
+			return "", errors.New("ans has no value yet")
+		}
+		b.WriteString(line[last:t.Pos])
+		b.WriteString(s.ansText())
+		last = t.Pos + len(t.Text)
+	}
+	b.WriteString(line[last:])
+	return evalLine(b.String(), s.m)
+}
+
+// ansText is ans written so that it can go in an expression: without an
+// exponent, which the tokenizer doesn't read, and in parentheses if it's
+// negative.
+func (s *replState) ansText() string {
+	text := s.ans
+	if strings.ContainsAny(text, "eE") {
+		if f, err := strconv.ParseFloat(text, 64); err == nil {
+			text = strconv.FormatFloat(f, 'f', -1, 64)
+		}
+	}
+	if strings.HasPrefix(text, "-") {
+		return "(" + text + ")"
+	}
+	return text
+}
+
+// entry returns history entry n, if it hasn't been dropped.
+func (s *replState) entry(n int) (historyEntry, bool) {
+	for _, e := range s.history {
+		if e.N == n {
+			return e, true
+		}
+	}
+	return historyEntry{}, false
+}
+
+// repl evaluates one expression per line read from r, writing each result
+// to w and each error to errOut. It stops at EOF or when it reads "quit".
+// Blank lines are skipped. ans stands for the last successful result,
+// "history" lists the last maxHistory lines and "!N" evaluates line N
+// again. Failed lines are counted in f.
+func repl(r io.Reader, w, errOut io.Writer, m mode, maxHistory int, f *failures) error {
+	s := &replState{m: m, maxHistory: max(maxHistory, 1)}
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		switch {
+		case line == "":
+			continue
+		case line == "quit":
+			return nil
+		case line == "history":
+			for _, e := range s.history {
+				if e.Err != nil {
+					fmt.Fprintf(w, "%4d  %s  Error: %v\n", e.N, e.Expr, e.Err)
+				} else {
+					fmt.Fprintf(w, "%4d  %s = %s\n", e.N, e.Expr, e.Result)
+				}
+			}
+			continue
+		case strings.HasPrefix(line, "!"):
+			n, err := strconv.Atoi(line[1:])
+			if err != nil {
+				fmt.Fprintf(errOut, "Error: invalid history entry %q\n", line[1:])
+				continue
+			}
+			e, ok := s.entry(n)
+			if !ok {
+				fmt.Fprintf(errOut, "Error: no history entry %d\n", n)
+				continue
+			}
+			line = e.Expr
+			fmt.Fprintln(w, line)
+		}
+		result, err := s.eval(line)
+		if err != nil {
+			f.add(err)
+			fmt.Fprintln(errOut, "Error:", err)
+			continue
+		}
+		fmt.Fprintln(w, "=", result)
+	}
+	return scanner.Err()
+}
+
+// EvalLines evaluates one expression per line read from r, writing
+// "expr = result" for each one that works and "expr : line N: error" for each
+// one that doesn't. It carries on past failed lines and returns how many
+// there were. Blank lines are skipped. err is only set if reading r fails.
+func EvalLines(r io.Reader, w io.Writer) (failed int, err error) {
+	var f failures
+	err = evalLines(r, w, w, autoMode, &f)
+	return f.total(), err
+}
+
+// evalLines is EvalLines writing errors to errOut and counting them in f.
+func evalLines(r io.Reader, w, errOut io.Writer, m mode, f *failures) error {
+	scanner := bufio.NewScanner(r)
+	lineNo := 0
+	for scanner.Scan() {
+		lineNo++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		result, err := evalLine(line, m)
+		if err != nil {
+			f.add(err)
+			fmt.Fprintf(errOut, "%s : line %d: %v\n", line, lineNo, err)
+			continue
+		}
+		fmt.Fprintf(w, "%s = %s\n", line, result)
+	}
+	return scanner.Err()
+}
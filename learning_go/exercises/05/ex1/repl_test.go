@@ -0,0 +1,82 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+// runREPL drives a scripted session and returns what was written to stdout
+// and stderr.
+func runREPL(t *testing.T, m mode, maxHistory int, script string) (stdout, stderr string, f failures) {
+	t.Helper()
+	var out, errOut strings.Builder
+	if err := repl(strings.NewReader(script), &out, &errOut, m, maxHistory, &f); err != nil {
+		t.Fatal(err)
+	}
+	return out.String(), errOut.String(), f
+}
+
+func TestREPLAnsAfterFailure(t *testing.T) {
+	stdout, stderr, f := runREPL(t, autoMode, 20, `ans + 1
+2 + 3
+ans / 0
+ans * 2
+ans - 20
+ans ** 2
+`)
+	if want := "= 5\n= 10\n= -10\n= 100\n"; stdout != want {
+		t.Errorf("stdout = %q, want %q", stdout, want)
+	}
+	if want := "Error: ans has no value yet\nError: division by zero\n"; stderr != want {
+		t.Errorf("stderr = %q, want %q", stderr, want)
+	}
+	if f.eval != 2 || f.parse != 0 {
+		t.Errorf("failures = %+v, want 2 evaluation errors", f)
+	}
+}
+
+func TestREPLAnsWholeTokens(t *testing.T) {
+	stdout, stderr, _ := runREPL(t, autoMode, 20, "4\nanswer(ans)\nans+ans\n(ans)*ans\n")
+	if want := "= 4\n= 8\n= 64\n"; stdout != want {
+		t.Errorf("stdout = %q, want %q", stdout, want)
+	}
+	// answer is a name of its own, not ans followed by "wer"
+	if want := "Error: unknown function at offset 0, found \"answer\"\n"; stderr != want {
+		t.Errorf("stderr = %q, want %q", stderr, want)
+	}
+}
+
+func TestREPLAnsModes(t *testing.T) {
+	stdout, _, _ := runREPL(t, floatMode, 20, "10 ** 21\nans * 2\n0 - 2.5\nans * ans\n")
+	if want := "= 1e+21\n= 2e+21\n= -2.5\n= 6.25\n"; stdout != want {
+		t.Errorf("float stdout = %q, want %q", stdout, want)
+	}
+	stdout, _, _ = runREPL(t, bigMode, 20, "2 ** 100\nans - ans - 1\n-ans\n")
+	if want := "= 1267650600228229401496703205376\n= -1\n= 1\n"; stdout != want {
+		t.Errorf("big stdout = %q, want %q", stdout, want)
+	}
+}
+
+func TestREPLHistory(t *testing.T) {
+	stdout, stderr, _ := runREPL(t, autoMode, 2, `1 + 1
+2 / 0
+3 * 3
+history
+!1
+!3
+!x
+quit
+4 + 4
+`)
+	want := "= 2\n= 9\n" +
+		"   2  2 / 0  Error: division by zero\n" +
+		"   3  3 * 3 = 9\n" +
+		"3 * 3\n= 9\n"
+	if stdout != want {
+		t.Errorf("stdout = %q, want %q", stdout, want)
+	}
+	wantErr := "Error: division by zero\nError: no history entry 1\nError: invalid history entry \"x\"\n"
+	if stderr != wantErr {
+		t.Errorf("stderr = %q, want %q", stderr, wantErr)
+	}
+}
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"bufio"
+	"os"
+)
+
+// DiffLine is one line of output from FilesDiffer. Kind is '=' for a line in
+// both files, '-' for a line only in the first file, and '+' for a line only
+// in the second file. LineNum is the 1-based line number in the second file
+// for '+' lines and in the first file otherwise.
+type DiffLine struct {
+	LineNum int
+	Kind    rune
+	Text    string
+}
+
+func readLines(file string) ([]string, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	var lines []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return lines, nil
+}
+
+// FilesDiffer compares two files line by line using the longest common
+// subsequence of their lines. Removed lines are listed before the lines that
+// were added in their place.
+func FilesDiffer(a, b string) ([]DiffLine, error) {
+	linesA, err := readLines(a)
+	if err != nil {
+		return nil, err
+	}
+	linesB, err := readLines(b)
+	if err != nil {
+		return nil, err
+	}
+
+	// lcs[i][j] is the length of the longest common subsequence of
+	// linesA[i:] and linesB[j:]
+	lcs := make([][]int, len(linesA)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(linesB)+1)
+	}
+	for i := len(linesA) - 1; i >= 0; i-- {
+		for j := len(linesB) - 1; j >= 0; j-- {
+			if linesA[i] == linesB[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	var out []DiffLine
+	i, j := 0, 0
+	for i < len(linesA) && j < len(linesB) {
+		switch {
+		case linesA[i] == linesB[j]:
+			out = append(out, DiffLine{LineNum: i + 1, Kind: '=', Text: linesA[i]})
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			out = append(out, DiffLine{LineNum: i + 1, Kind: '-', Text: linesA[i]})
+			i++
+		default:
+			out = append(out, DiffLine{LineNum: j + 1, Kind: '+', Text: linesB[j]})
+			j++
+		}
+	}
+	for ; i < len(linesA); i++ {
+		out = append(out, DiffLine{LineNum: i + 1, Kind: '-', Text: linesA[i]})
+	}
+	for ; j < len(linesB); j++ {
+		out = append(out, DiffLine{LineNum: j + 1, Kind: '+', Text: linesB[j]})
+	}
+	return out, nil
+}
@@ -0,0 +1,101 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"path/filepath"
+	"sync"
+)
+
+// DirStats walks the directory tree under root and returns the size of every
+// regular file, keyed by path. It stops at the first error.
+func DirStats(root string) (map[string]int, error) {
+	ctx := context.Background()
+	stats := map[string]int{}
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		count, err := fileLen(ctx, path)
+		if err != nil {
+			return err
+		}
+		stats[path] = count
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return stats, nil
+}
+
+// DirStatsConcurrent does the same thing as DirStats, but the files are read
+// by a pool of workers goroutines while the directory is being walked.
+func DirStatsConcurrent(root string, workers int) (map[string]int, error) {
+	if workers < 1 {
+		return nil, errors.New("workers must be at least 1")
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var mu sync.Mutex
+	stats := map[string]int{}
+	var firstErr error
+	setErr := func(err error) {
+		mu.Lock()
+		defer mu.Unlock()
+		if firstErr == nil {
+			firstErr = err
+			// Stop the walk and any reads that are in progress
+			cancel()
+		}
+	}
+
+	paths := make(chan string)
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for path := range paths {
+				count, err := fileLen(ctx, path)
+				if err != nil {
+					setErr(err)
+					continue
+				}
+				mu.Lock()
+				stats[path] = count
+				mu.Unlock()
+			}
+		}()
+	}
+
+	walkErr := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		select {
+		case paths <- path:
+			return nil
+		case <-ctx.Done():
+			return filepath.SkipAll
+		}
+	})
+	close(paths)
+	wg.Wait()
+
+	if walkErr != nil {
+		setErr(walkErr)
+	}
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	return stats, nil
+}
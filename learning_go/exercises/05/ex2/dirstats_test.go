@@ -0,0 +1,85 @@
+package main
+
+import (
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// smallTree writes n small files spread over subdirectories of a new
+// directory, and returns the directory and each file's size.
+func smallTree(t testing.TB, n int) (string, map[string]int) {
+	t.Helper()
+	root := t.TempDir()
+	want := make(map[string]int, n)
+	for i := 0; i < n; i++ {
+		dir := filepath.Join(root, fmt.Sprintf("d%02d", i%20), fmt.Sprintf("e%d", i%3))
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			t.Fatal(err)
+		}
+		path := filepath.Join(dir, fmt.Sprintf("f%d.txt", i))
+		content := strings.Repeat("x", i%300)
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		want[path] = len(content)
+	}
+	return root, want
+}
+
+func TestDirStats(t *testing.T) {
+	root, want := smallTree(t, 200)
+	// Symlinks aren't regular files, so they're skipped
+	if err := os.Symlink(filepath.Join(root, "d00"), filepath.Join(root, "link")); err != nil {
+		t.Fatal(err)
+	}
+	got, err := DirStats(root)
+	if err != nil || !maps.Equal(got, want) {
+		t.Errorf("DirStats = %d files, %v, want %d files", len(got), err, len(want))
+	}
+	for _, workers := range []int{1, 4, 16} {
+		got, err := DirStatsConcurrent(root, workers)
+		if err != nil || !maps.Equal(got, want) {
+			t.Errorf("DirStatsConcurrent with %d workers = %d files, %v, want %d files", workers, len(got), err, len(want))
+		}
+	}
+}
+
+func TestDirStatsErrors(t *testing.T) {
+	missing := filepath.Join(t.TempDir(), "missing")
+	if _, err := DirStats(missing); err == nil {
+		t.Error("DirStats of a missing directory succeeded")
+	}
+	if _, err := DirStatsConcurrent(missing, 4); err == nil {
+		t.Error("DirStatsConcurrent of a missing directory succeeded")
+	}
+	if _, err := DirStatsConcurrent(t.TempDir(), 0); err == nil {
+		t.Error("DirStatsConcurrent with 0 workers succeeded")
+	}
+}
+
+func BenchmarkDirStats(b *testing.B) {
+	root, _ := smallTree(b, 10_000)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := DirStats(root); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkDirStatsConcurrent(b *testing.B) {
+	root, _ := smallTree(b, 10_000)
+	for _, workers := range []int{2, 8, 32} {
+		b.Run(fmt.Sprintf("workers=%d", workers), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				if _, err := DirStatsConcurrent(root, workers); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+)
+
+// FileHead returns the first n lines of file. Scanning stops as soon as n
+// lines have been read, so the rest of the file is never loaded. Files with
+// fewer than n lines return every line.
+func FileHead(file string, n int) ([]string, error) {
+	if n <= 0 {
+		return nil, fmt.Errorf("line count must be positive, got %d", n)
+	}
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	scanner := bufio.NewScanner(f)
+	var lines []string
+	for len(lines) < n && scanner.Scan() {
+		lines = append(lines, scanner.Text())
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return lines, nil
+}
+
+// FileTail returns the last n lines of file. Only n lines are kept in memory
+// at a time, in a circular buffer that overwrites the oldest line. The
+// buffer grows as lines are read, so a large n costs nothing on a short file.
+func FileTail(file string, n int) ([]string, error) {
+	if n <= 0 {
+		return nil, fmt.Errorf("line count must be positive, got %d", n)
+	}
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	scanner := bufio.NewScanner(f)
+	var ring []string
+	var seen int
+	for scanner.Scan() {
+		if len(ring) < n {
+			ring = append(ring, scanner.Text())
+		} else {
+			ring[seen%n] = scanner.Text()
+		}
+		seen++
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if seen <= n {
+		return ring, nil
+	}
+	// The oldest line is the one that would be overwritten next
+	start := seen % n
+	return append(ring[start:], ring[:start]...), nil
+}
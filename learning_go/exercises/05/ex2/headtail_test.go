@@ -0,0 +1,88 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// numberedFile writes a file of count lines, "line 1" onwards, and returns
+// its path and lines.
+func numberedFile(t *testing.T, count int) (string, []string) {
+	t.Helper()
+	lines := make([]string, count)
+	for i := range lines {
+		lines[i] = fmt.Sprintf("line %d", i+1)
+	}
+	path := filepath.Join(t.TempDir(), "lines.txt")
+	content := strings.Join(lines, "\n")
+	if count > 0 {
+		content += "\n"
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path, lines
+}
+
+func TestFileHeadTail(t *testing.T) {
+	const n = 5
+	tests := []struct {
+		name  string
+		count int
+	}{
+		{"empty", 0},
+		{"fewer than n", 3},
+		{"exactly n", n},
+		{"one more than n", n + 1},
+		{"more than n", 12},
+		{"many times n", 1000},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path, lines := numberedFile(t, tt.count)
+			want := lines[:min(n, len(lines))]
+			if got, err := FileHead(path, n); err != nil || !slices.Equal(got, want) {
+				t.Errorf("FileHead(%d) = %q, %v, want %q", n, got, err, want)
+			}
+			want = lines[max(0, len(lines)-n):]
+			if got, err := FileTail(path, n); err != nil || !slices.Equal(got, want) {
+				t.Errorf("FileTail(%d) = %q, %v, want %q", n, got, err, want)
+			}
+		})
+	}
+}
+
+func TestFileTailHugeN(t *testing.T) {
+	// The ring only grows as far as the file's lines, so this doesn't try to
+	// allocate a trillion strings
+	path, lines := numberedFile(t, 3)
+	if got, err := FileTail(path, 1<<40); err != nil || !slices.Equal(got, lines) {
+		t.Errorf("FileTail(1<<40) = %q, %v, want %q", got, err, lines)
+	}
+	if got, err := FileHead(path, 1<<40); err != nil || !slices.Equal(got, lines) {
+		t.Errorf("FileHead(1<<40) = %q, %v, want %q", got, err, lines)
+	}
+}
+
+func TestFileHeadTailErrors(t *testing.T) {
+	path, _ := numberedFile(t, 3)
+	for _, n := range []int{0, -1} {
+		if _, err := FileHead(path, n); err == nil {
+			t.Errorf("FileHead(%d) succeeded", n)
+		}
+		if _, err := FileTail(path, n); err == nil {
+			t.Errorf("FileTail(%d) succeeded", n)
+		}
+	}
+	missing := filepath.Join(t.TempDir(), "missing")
+	if _, err := FileHead(missing, 1); !os.IsNotExist(err) {
+		t.Errorf("FileHead(missing) = %v, want a not exist error", err)
+	}
+	if _, err := FileTail(missing, 1); !os.IsNotExist(err) {
+		t.Errorf("FileTail(missing) = %v, want a not exist error", err)
+	}
+}
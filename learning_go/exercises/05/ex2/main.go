@@ -1,13 +1,32 @@
 package main
 
 import (
+	"bufio"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
 	"fmt"
 	"io"
 	"log"
+	"math"
 	"os"
+	"path/filepath"
+	"strings"
+	"unicode"
 )
 
-func fileLen(file string) (int, error) {
+func fileLen(ctx context.Context, file string) (int, error) {
+	return fileLenWithProgress(ctx, file, nil)
+}
+
+// fileLenWithProgress works like fileLen, but calls onProgress with the
+// running total after every read. onProgress is called at least once, even
+// for an empty file, and may be nil.
+func fileLenWithProgress(ctx context.Context, file string, onProgress func(bytesRead int)) (int, error) {
+	if onProgress == nil {
+		onProgress = func(int) {}
+	}
 	f, err := os.Open(file)
 	if err != nil {
 		return 0, err
@@ -18,6 +37,10 @@ func fileLen(file string) (int, error) {
 	for {
 		count, err := f.Read(data)
 		total += count
+		onProgress(total)
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return 0, fmt.Errorf("reading %s: %w", file, ctxErr)
+		}
 		if err != nil {
 			if err != io.EOF {
 				return 0, err
@@ -28,13 +51,247 @@ func fileLen(file string) (int, error) {
 	return total, nil
 }
 
+// countTokens counts the tokens produced by split over the whole file.
+func countTokens(ctx context.Context, file string, split bufio.SplitFunc) (int, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+	scanner := bufio.NewScanner(f)
+	// Let tokens grow past Scanner's 64 KiB default, so a long line is
+	// counted instead of failing with bufio.ErrTooLong
+	scanner.Buffer(make([]byte, 0, 64*1024), math.MaxInt)
+	scanner.Split(split)
+	var total int
+	for scanner.Scan() {
+		total++
+		if err := ctx.Err(); err != nil {
+			return 0, fmt.Errorf("reading %s: %w", file, err)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return 0, err
+	}
+	return total, nil
+}
+
+func lineCount(ctx context.Context, file string) (int, error) {
+	return countTokens(ctx, file, bufio.ScanLines)
+}
+
+func wordCount(ctx context.Context, file string) (int, error) {
+	return countTokens(ctx, file, bufio.ScanWords)
+}
+
+// FileStats counts bytes, lines, and words in a single pass over the file.
+// The counts match what fileLen, lineCount, and wordCount return.
+func FileStats(file string) (bytes, lines, words int, err error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return 0, 0, 0, err
+	}
+	defer f.Close()
+	r := bufio.NewReader(f)
+	inWord := false
+	var last rune
+	for {
+		c, size, err := r.ReadRune()
+		if err != nil {
+			if err != io.EOF {
+				return 0, 0, 0, err
+			}
+			break
+		}
+		bytes += size
+		if c == '\n' {
+			lines++
+		}
+		if unicode.IsSpace(c) {
+			inWord = false
+		} else if !inWord {
+			inWord = true
+			words++
+		}
+		last = c
+	}
+	// A final line without a trailing newline still counts as a line
+	if bytes > 0 && last != '\n' {
+		lines++
+	}
+	return bytes, lines, words, nil
+}
+
+type checksumOptions struct {
+	bufferSize int
+}
+
+// ChecksumOption configures FileChecksum and VerifyChecksum.
+type ChecksumOption func(*checksumOptions)
+
+// WithBufferSize sets how many bytes are read from the file at a time.
+func WithBufferSize(size int) ChecksumOption {
+	return func(o *checksumOptions) {
+		o.bufferSize = size
+	}
+}
+
+// FileChecksum returns the hex encoded SHA-256 digest of the file's contents.
+func FileChecksum(file string, opts ...ChecksumOption) (string, error) {
+	o := checksumOptions{bufferSize: 2048}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if o.bufferSize < 1 {
+		return "", errors.New("buffer size must be at least 1")
+	}
+	f, err := os.Open(file)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	h := sha256.New()
+	// Hide the file's ReadFrom/WriteTo methods so CopyBuffer really uses our
+	// buffer
+	if _, err := io.CopyBuffer(h, struct{ io.Reader }{f}, make([]byte, o.bufferSize)); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// VerifyChecksum reports whether the file's SHA-256 digest matches expected.
+// The comparison ignores case.
+func VerifyChecksum(file, expected string, opts ...ChecksumOption) (bool, error) {
+	sum, err := FileChecksum(file, opts...)
+	if err != nil {
+		return false, err
+	}
+	return strings.EqualFold(sum, expected), nil
+}
+
+type chunkResult struct {
+	count int
+	err   error
+}
+
+// fileLenParallel splits the file into workers equally sized ranges and reads
+// each range in its own goroutine. ReadAt doesn't use the file's offset, so
+// all of the goroutines can safely share a single *os.File.
+func fileLenParallel(file string, workers int) (int, error) {
+	if workers < 1 {
+		return 0, errors.New("workers must be at least 1")
+	}
+	info, err := os.Stat(file)
+	if err != nil {
+		return 0, err
+	}
+	f, err := os.Open(file)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+
+	size := info.Size()
+	chunkSize := size / int64(workers)
+	results := make(chan chunkResult, workers)
+	for i := 0; i < workers; i++ {
+		start := int64(i) * chunkSize
+		end := start + chunkSize
+		// The last worker picks up whatever is left over from the division
+		if i == workers-1 {
+			end = size
+		}
+		go func(start, end int64) {
+			var total int
+			data := make([]byte, 2048)
+			for off := start; off < end; {
+				buf := data
+				if remaining := end - off; remaining < int64(len(buf)) {
+					buf = buf[:remaining]
+				}
+				count, err := f.ReadAt(buf, off)
+				total += count
+				off += int64(count)
+				if err != nil {
+					if err != io.EOF {
+						results <- chunkResult{err: err}
+						return
+					}
+					break
+				}
+			}
+			results <- chunkResult{count: total}
+		}(start, end)
+	}
+
+	var total int
+	var firstErr error
+	for i := 0; i < workers; i++ {
+		r := <-results
+		if r.err != nil && firstErr == nil {
+			firstErr = r.err
+		}
+		total += r.count
+	}
+	if firstErr != nil {
+		return 0, firstErr
+	}
+	return total, nil
+}
+
 func main() {
 	if len(os.Args) < 2 {
 		return
 	}
-	count, err := fileLen(os.Args[1])
+	count, err := fileLenWithProgress(context.Background(), os.Args[1], func(bytesRead int) {
+		fmt.Fprintf(os.Stderr, "\rread %d bytes", bytesRead)
+	})
+	fmt.Fprintln(os.Stderr)
 	if err != nil {
 		log.Fatal(err)
 	}
 	fmt.Println(count)
+	parallelCount, err := fileLenParallel(os.Args[1], 4)
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println(parallelCount)
+	bytes, lines, words, err := FileStats(os.Args[1])
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println(bytes, lines, words)
+	head, err := FileHead(os.Args[1], 3)
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Printf("%q\n", head)
+	tail, err := FileTail(os.Args[1], 3)
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Printf("%q\n", tail)
+	sum, err := FileChecksum(os.Args[1], WithBufferSize(4096))
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println(sum)
+
+	// Size up every file in the same directory as the one we were given
+	dir := filepath.Dir(os.Args[1])
+	stats, err := DirStatsConcurrent(dir, 4)
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println(len(stats), "files in", dir)
+
+	if len(os.Args) > 2 {
+		diff, err := FilesDiffer(os.Args[1], os.Args[2])
+		if err != nil {
+			log.Fatal(err)
+		}
+		for _, d := range diff {
+			fmt.Printf("%c %d %s\n", d.Kind, d.LineNum, d.Text)
+		}
+	}
 }
@@ -0,0 +1,242 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// fixture is checked in, and has a blank line, a tab, a CRLF line ending,
+// multi-byte characters and no newline at the end.
+const (
+	fixture      = "testdata/fixture.txt"
+	fixtureBytes = 104
+	fixtureLines = 5
+	fixtureWords = 18
+)
+
+func TestCountsFixture(t *testing.T) {
+	ctx := context.Background()
+	if n, err := fileLen(ctx, fixture); err != nil || n != fixtureBytes {
+		t.Errorf("fileLen = %d, %v, want %d", n, err, fixtureBytes)
+	}
+	if n, err := lineCount(ctx, fixture); err != nil || n != fixtureLines {
+		t.Errorf("lineCount = %d, %v, want %d", n, err, fixtureLines)
+	}
+	if n, err := wordCount(ctx, fixture); err != nil || n != fixtureWords {
+		t.Errorf("wordCount = %d, %v, want %d", n, err, fixtureWords)
+	}
+	bytes, lines, words, err := FileStats(fixture)
+	if err != nil || bytes != fixtureBytes || lines != fixtureLines || words != fixtureWords {
+		t.Errorf("FileStats = %d, %d, %d, %v, want %d, %d, %d", bytes, lines, words, err, fixtureBytes, fixtureLines, fixtureWords)
+	}
+}
+
+// TestCountsAgree checks FileStats and the separate counts agree on
+// awkward files.
+func TestCountsAgree(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"empty":           "",
+		"newline":         "\n",
+		"trailingNewline": "one two\nthree\n",
+		"onlySpaces":      "   \t  ",
+		// Longer than bufio.Scanner's default 64 KiB token limit
+		"longLine": strings.Repeat("word ", 20000) + "\n" + strings.Repeat("x", 100000) + "\nend",
+	}
+	ctx := context.Background()
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		bytes, lines, words, err := FileStats(path)
+		if err != nil {
+			t.Fatalf("%s: FileStats: %v", name, err)
+		}
+		if n, err := fileLen(ctx, path); err != nil || n != bytes {
+			t.Errorf("%s: fileLen = %d, %v, FileStats counted %d", name, n, err, bytes)
+		}
+		if n, err := lineCount(ctx, path); err != nil || n != lines {
+			t.Errorf("%s: lineCount = %d, %v, FileStats counted %d", name, n, err, lines)
+		}
+		if n, err := wordCount(ctx, path); err != nil || n != words {
+			t.Errorf("%s: wordCount = %d, %v, FileStats counted %d", name, n, err, words)
+		}
+	}
+}
+
+func TestCountsMissingFile(t *testing.T) {
+	ctx := context.Background()
+	missing := filepath.Join(t.TempDir(), "missing")
+	if n, err := lineCount(ctx, missing); err == nil || n != 0 {
+		t.Errorf("lineCount = %d, %v, want 0 and an error", n, err)
+	}
+	if n, err := wordCount(ctx, missing); err == nil || n != 0 {
+		t.Errorf("wordCount = %d, %v, want 0 and an error", n, err)
+	}
+	if _, _, _, err := FileStats(missing); err == nil {
+		t.Error("FileStats succeeded")
+	}
+}
+
+// fixtureSHA256 is the SHA-256 digest of the fixture, from sha256sum.
+const fixtureSHA256 = "9c6123408d9e8397edb4d70e6e9e1ed7f8e5bba298e4e4642829afd731f6c649"
+
+func TestFileChecksum(t *testing.T) {
+	// Buffers smaller than, the same size as and larger than the file
+	for _, size := range []int{1, 7, fixtureBytes, 2048} {
+		sum, err := FileChecksum(fixture, WithBufferSize(size))
+		if err != nil || sum != fixtureSHA256 {
+			t.Errorf("FileChecksum with a %d byte buffer = %s, %v, want %s", size, sum, err, fixtureSHA256)
+		}
+	}
+	if sum, err := FileChecksum(fixture); err != nil || sum != fixtureSHA256 {
+		t.Errorf("FileChecksum = %s, %v, want %s", sum, err, fixtureSHA256)
+	}
+
+	empty := filepath.Join(t.TempDir(), "empty")
+	if err := os.WriteFile(empty, nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	const emptySHA256 = "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+	if sum, err := FileChecksum(empty); err != nil || sum != emptySHA256 {
+		t.Errorf("FileChecksum(empty) = %s, %v, want %s", sum, err, emptySHA256)
+	}
+
+	if _, err := FileChecksum(fixture, WithBufferSize(0)); err == nil {
+		t.Error("FileChecksum with a 0 byte buffer succeeded")
+	}
+	if _, err := FileChecksum(filepath.Join(t.TempDir(), "missing")); err == nil {
+		t.Error("FileChecksum of a missing file succeeded")
+	}
+}
+
+func TestVerifyChecksum(t *testing.T) {
+	tests := []struct {
+		expected string
+		want     bool
+	}{
+		{fixtureSHA256, true},
+		{strings.ToUpper(fixtureSHA256), true},
+		{strings.Repeat("0", 64), false},
+		{"", false},
+	}
+	for _, tt := range tests {
+		if ok, err := VerifyChecksum(fixture, tt.expected); err != nil || ok != tt.want {
+			t.Errorf("VerifyChecksum(%q) = %t, %v, want %t", tt.expected, ok, err, tt.want)
+		}
+	}
+	if _, err := VerifyChecksum(filepath.Join(t.TempDir(), "missing"), fixtureSHA256); err == nil {
+		t.Error("VerifyChecksum of a missing file succeeded")
+	}
+}
+
+// textFile writes a file of size bytes of short lines of words to dir.
+func textFile(t testing.TB, dir string, size int) string {
+	t.Helper()
+	line := "the quick brown fox\n"
+	data := []byte(strings.Repeat(line, size/len(line)+1)[:size])
+	path := filepath.Join(dir, fmt.Sprintf("text-%d", size))
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// TestFileLenCancel cancels the context once 1 MiB of a 10 MiB file has
+// been read, and checks fileLen stops there.
+func TestFileLenCancel(t *testing.T) {
+	const size, cancelAt = 10 << 20, 1 << 20
+	path := textFile(t, t.TempDir(), size)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	read := 0
+	n, err := fileLenWithProgress(ctx, path, func(total int) {
+		read = total
+		if total >= cancelAt {
+			cancel()
+		}
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("fileLen = %d, %v, want context.Canceled", n, err)
+	}
+	if n != 0 {
+		t.Errorf("fileLen returned %d bytes along with the error", n)
+	}
+	if read >= size/2 {
+		t.Errorf("read %d bytes after cancelling at %d", read, cancelAt)
+	}
+}
+
+func TestCountsCancelled(t *testing.T) {
+	path := textFile(t, t.TempDir(), 1<<20)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	counts := map[string]func(context.Context, string) (int, error){
+		"fileLen":   fileLen,
+		"lineCount": lineCount,
+		"wordCount": wordCount,
+	}
+	for name, count := range counts {
+		if n, err := count(ctx, path); !errors.Is(err, context.Canceled) {
+			t.Errorf("%s = %d, %v, want context.Canceled", name, n, err)
+		}
+	}
+}
+
+func TestFileLenParallel(t *testing.T) {
+	dir := t.TempDir()
+	ctx := context.Background()
+	for _, size := range []int{0, 1, 2047, 2048, 2049, 100_003} {
+		path := textFile(t, dir, size)
+		want, err := fileLen(ctx, path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		// Including more workers than there are bytes
+		for _, workers := range []int{1, 2, 3, 7, 16} {
+			if n, err := fileLenParallel(path, workers); err != nil || n != want {
+				t.Errorf("fileLenParallel(%d byte file, %d workers) = %d, %v, want %d", size, workers, n, err, want)
+			}
+		}
+	}
+	if _, err := fileLenParallel(textFile(t, dir, 10), 0); err == nil {
+		t.Error("fileLenParallel with 0 workers succeeded")
+	}
+	if _, err := fileLenParallel(filepath.Join(dir, "missing"), 4); err == nil {
+		t.Error("fileLenParallel of a missing file succeeded")
+	}
+}
+
+const benchFileSize = 100 << 20
+
+func BenchmarkFileLen(b *testing.B) {
+	path := textFile(b, b.TempDir(), benchFileSize)
+	ctx := context.Background()
+	b.SetBytes(benchFileSize)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := fileLen(ctx, path); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkFileLenParallel(b *testing.B) {
+	path := textFile(b, b.TempDir(), benchFileSize)
+	for _, workers := range []int{1, 2, 4, 8} {
+		b.Run(fmt.Sprintf("workers=%d", workers), func(b *testing.B) {
+			b.SetBytes(benchFileSize)
+			for i := 0; i < b.N; i++ {
+				if _, err := fileLenParallel(path, workers); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
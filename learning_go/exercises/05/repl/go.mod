@@ -0,0 +1,3 @@
+module 05_repl
+
+go 1.21.3
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+type opFuncType func(int, int) (int, error)
+
+func add(i, j int) (int, error) { return i + j, nil }
+
+func sub(i, j int) (int, error) { return i - j, nil }
+
+func mul(i, j int) (int, error) { return i * j, nil }
+
+func div(i, j int) (int, error) {
+	if j == 0 {
+		return 0, errors.New("division by zero")
+	}
+	return i / j, nil
+}
+
+var opMap = map[string]opFuncType{
+	"+": add,
+	"-": sub,
+	"*": mul,
+	"/": div,
+}
+
+// eval evaluates a single "operand operator operand" expression, with each
+// token separated by whitespace.
+func eval(line string) (int, error) {
+	expression := strings.Fields(line)
+	if len(expression) != 3 {
+		return 0, fmt.Errorf("invalid expression: %v", expression)
+	}
+	p1, err := strconv.Atoi(expression[0])
+	if err != nil {
+		return 0, err
+	}
+	opFunc, ok := opMap[expression[1]]
+	if !ok {
+		return 0, fmt.Errorf("unsupported operator: %s", expression[1])
+	}
+	p2, err := strconv.Atoi(expression[2])
+	if err != nil {
+		return 0, err
+	}
+	return opFunc(p1, p2)
+}
+
+func main() {
+	scanner := bufio.NewScanner(os.Stdin)
+	for {
+		fmt.Print("> ")
+		// Scan returns false on EOF as well as on a read error
+		if !scanner.Scan() {
+			break
+		}
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || line == "quit" {
+			break
+		}
+		result, err := eval(line)
+		if err != nil {
+			fmt.Println("Error:", err)
+			continue
+		}
+		fmt.Println("=", result)
+	}
+	if err := scanner.Err(); err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,58 @@
+package main
+
+import "strings"
+
+type Address struct {
+	Street     string
+	City       string
+	State      string
+	PostalCode string
+	Country    string
+}
+
+func MakePersonWithAddress(firstName, lastName string, age int, addr Address) Person {
+	p := MakePerson(firstName, lastName, age)
+	p.Address = addr
+	return p
+}
+
+// FullAddress formats the person's name and address as a postal address:
+//
+//	Nitin Kunaparaju
+//	1 Main St
+//	Springfield, IL 62701
+//	USA
+//
+// Lines with nothing on them are left out.
+func (p Person) FullAddress() string {
+	cityLine := p.City
+	if p.State != "" {
+		if cityLine != "" {
+			cityLine += ", "
+		}
+		cityLine += p.State
+	}
+	if p.PostalCode != "" {
+		cityLine = strings.TrimSpace(cityLine + " " + p.PostalCode)
+	}
+	var lines []string
+	for _, line := range []string{
+		strings.TrimSpace(p.FirstName + " " + p.LastName),
+		p.Street,
+		cityLine,
+		p.Country,
+	} {
+		if line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// IsLocalTo reports whether both people live in the same city and state.
+// Someone without a city isn't local to anyone.
+func (p Person) IsLocalTo(other Person) bool {
+	return p.City != "" &&
+		strings.EqualFold(p.City, other.City) &&
+		strings.EqualFold(p.State, other.State)
+}
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+)
+
+var employeeHeader = []string{"id", "first_name", "last_name", "age", "salary"}
+
+// ExportEmployees writes the employees as CSV, starting with a header row.
+func ExportEmployees(employees []Employee, w io.Writer) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(employeeHeader); err != nil {
+		return err
+	}
+	for _, e := range employees {
+		record := []string{
+			e.EmployeeID,
+			e.FirstName,
+			e.LastName,
+			strconv.Itoa(e.Age),
+			strconv.FormatFloat(e.Salary, 'f', -1, 64),
+		}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// ImportEmployees reads CSV written by ExportEmployees. Rows that can't be
+// parsed or don't pass Validate are skipped and reported in the returned
+// error, which joins one error per bad row. The valid rows are returned
+// either way.
+func ImportEmployees(r io.Reader) ([]Employee, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = len(employeeHeader)
+	// Skip the header
+	if _, err := cr.Read(); err != nil {
+		if err == io.EOF {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var employees []Employee
+	var errs []error
+	for line := 2; ; line++ {
+		record, err := cr.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("line %d: %w", line, err))
+			// A row with the wrong number of fields can still be skipped,
+			// anything else means the CSV itself is broken
+			if !errors.Is(err, csv.ErrFieldCount) {
+				break
+			}
+			continue
+		}
+		e, err := parseEmployee(record)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("line %d: %w", line, err))
+			continue
+		}
+		employees = append(employees, e)
+	}
+	return employees, errors.Join(errs...)
+}
+
+func parseEmployee(record []string) (Employee, error) {
+	age, err := strconv.Atoi(record[3])
+	if err != nil {
+		return Employee{}, fmt.Errorf("invalid age: %w", err)
+	}
+	salary, err := strconv.ParseFloat(record[4], 64)
+	if err != nil {
+		return Employee{}, fmt.Errorf("invalid salary: %w", err)
+	}
+	e := Employee{
+		Person:     MakePerson(record[1], record[2], age),
+		EmployeeID: record[0],
+		Salary:     salary,
+	}
+	if err := e.Validate(); err != nil {
+		return Employee{}, err
+	}
+	return e, nil
+}
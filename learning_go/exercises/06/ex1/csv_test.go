@@ -0,0 +1,109 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// testEmployees returns n valid employees with different names, ages and
+// salaries. Some IDs have a comma, which has to be quoted.
+func testEmployees(n int) []Employee {
+	first := []string{"Ada", "Fred", "Maria", "Nitin", "Zoë", "José"}
+	last := []string{"Byron", "Williamson", "Kunaparaju", "Ørsted"}
+	employees := make([]Employee, n)
+	for i := range employees {
+		id := fmt.Sprintf("E%03d", i)
+		if i%10 == 0 {
+			id += ",X"
+		}
+		employees[i] = Employee{
+			Person:     MakePerson(first[i%len(first)], last[i%len(last)], 18+i%60),
+			EmployeeID: id,
+			Salary:     50_000 + float64(i)*1234.56,
+		}
+	}
+	return employees
+}
+
+func checkEmployee(t *testing.T, got, want Employee) {
+	t.Helper()
+	if got.EmployeeID != want.EmployeeID || got.FirstName != want.FirstName || got.LastName != want.LastName ||
+		got.Age != want.Age || got.Salary != want.Salary {
+		t.Errorf("employee = %s %s %s %d %g, want %s %s %s %d %g",
+			got.EmployeeID, got.FirstName, got.LastName, got.Age, got.Salary,
+			want.EmployeeID, want.FirstName, want.LastName, want.Age, want.Salary)
+	}
+}
+
+func TestEmployeesCSVRoundTrip(t *testing.T) {
+	want := testEmployees(100)
+	var b strings.Builder
+	if err := ExportEmployees(want, &b); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(b.String(), "id,first_name,last_name,age,salary\n") {
+		t.Errorf("CSV doesn't start with the header:\n%.80s", b.String())
+	}
+	got, err := ImportEmployees(strings.NewReader(b.String()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != len(want) {
+		t.Fatalf("imported %d employees, want %d", len(got), len(want))
+	}
+	for i := range want {
+		checkEmployee(t, got[i], want[i])
+	}
+}
+
+func TestImportEmployeesBadRows(t *testing.T) {
+	want := testEmployees(100)
+	var b strings.Builder
+	if err := ExportEmployees(want, &b); err != nil {
+		t.Fatal(err)
+	}
+	// Put bad rows after the header and the 10th and 50th employees
+	lines := strings.SplitAfter(b.String(), "\n")
+	bad := map[int]string{
+		1:  "B1,Ann,Smith,-5,1000\n",
+		11: "B2,Ann,Smith,forty,1000\n",
+		51: "B3,Ann,Smith,40\n",
+	}
+	var in strings.Builder
+	for i, line := range lines {
+		in.WriteString(bad[i])
+		in.WriteString(line)
+	}
+
+	got, err := ImportEmployees(strings.NewReader(in.String()))
+	if err == nil {
+		t.Fatal("ImportEmployees succeeded with bad rows")
+	}
+	// One error per bad row, naming the line
+	for _, wantErr := range []string{"line 2: ", "line 13: invalid age", "line 54: "} {
+		if !strings.Contains(err.Error(), wantErr) {
+			t.Errorf("error %q doesn't mention %q", err, wantErr)
+		}
+	}
+	var ve ValidationError
+	if !errors.As(err, &ve) || ve.Field != "Age" {
+		t.Errorf("error %v doesn't include the ValidationError for the negative age", err)
+	}
+	// The valid rows are all still there
+	if len(got) != len(want) {
+		t.Fatalf("imported %d employees, want %d", len(got), len(want))
+	}
+	for i := range want {
+		checkEmployee(t, got[i], want[i])
+	}
+}
+
+func TestImportEmployeesEmpty(t *testing.T) {
+	for _, in := range []string{"", "id,first_name,last_name,age,salary\n"} {
+		if got, err := ImportEmployees(strings.NewReader(in)); err != nil || len(got) != 0 {
+			t.Errorf("ImportEmployees(%q) = %v, %v, want nothing", in, got, err)
+		}
+	}
+}
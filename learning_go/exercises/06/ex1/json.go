@@ -0,0 +1,172 @@
+package main
+
+import "encoding/json"
+
+// personJSON is the wire format for a Person, using the snake_case names
+// common in JSON APIs.
+type personJSON struct {
+	FirstName string       `json:"first_name,omitempty"`
+	LastName  string       `json:"last_name,omitempty"`
+	Age       int          `json:"age"`
+	Address   *addressJSON `json:"address,omitempty"`
+}
+
+type addressJSON struct {
+	Street     string `json:"street,omitempty"`
+	City       string `json:"city,omitempty"`
+	State      string `json:"state,omitempty"`
+	PostalCode string `json:"postal_code,omitempty"`
+	Country    string `json:"country,omitempty"`
+}
+
+func (p Person) toJSON() personJSON {
+	out := personJSON{
+		FirstName: p.FirstName,
+		LastName:  p.LastName,
+		Age:       p.Age,
+	}
+	if p.Address != (Address{}) {
+		a := addressJSON(p.Address)
+		out.Address = &a
+	}
+	return out
+}
+
+func (p Person) MarshalJSON() ([]byte, error) {
+	return json.Marshal(p.toJSON())
+}
+
+// UnmarshalJSON accepts both the snake_case names written by MarshalJSON and
+// the Go field names, preferring snake_case if a document has both. The
+// decoded Person must pass Validate, otherwise p is left unchanged.
+func (p *Person) UnmarshalJSON(data []byte) error {
+	var in struct {
+		FirstName   *string `json:"first_name"`
+		LastName    *string `json:"last_name"`
+		GoFirstName *string `json:"FirstName"`
+		GoLastName  *string `json:"LastName"`
+		// Field names are matched case insensitively, so this handles
+		// both "age" and "Age"
+		Age     int          `json:"age"`
+		Address *addressJSON `json:"address"`
+	}
+	if err := json.Unmarshal(data, &in); err != nil {
+		return err
+	}
+	out := Person{Age: in.Age}
+	if in.Address != nil {
+		out.Address = Address(*in.Address)
+	}
+	switch {
+	case in.FirstName != nil:
+		out.FirstName = *in.FirstName
+	case in.GoFirstName != nil:
+		out.FirstName = *in.GoFirstName
+	}
+	switch {
+	case in.LastName != nil:
+		out.LastName = *in.LastName
+	case in.GoLastName != nil:
+		out.LastName = *in.GoLastName
+	}
+	if err := out.Validate(); err != nil {
+		return err
+	}
+	*p = out
+	return nil
+}
+
+// MustUnmarshalPerson decodes a Person and panics if the data is invalid.
+// It's meant for loading configuration at startup, where bad data should
+// stop the program.
+func MustUnmarshalPerson(data []byte) Person {
+	var p Person
+	if err := json.Unmarshal(data, &p); err != nil {
+		panic(err)
+	}
+	return p
+}
+
+// Employee and Manager embed Person, so without methods of their own they
+// would be encoded with Person's, losing every other field. Their JSON has
+// the person's fields alongside their own instead. Employee.Manager isn't
+// written, since the manager already lists the employee in its reports, and
+// is set again when the manager is decoded.
+
+type employeeJSON struct {
+	personJSON
+	EmployeeID string   `json:"employee_id"`
+	Salary     float64  `json:"salary"`
+	Manages    *Manager `json:"manages,omitempty"`
+}
+
+type managerJSON struct {
+	personJSON
+	Department string      `json:"department,omitempty"`
+	Reports    []*Employee `json:"reports"`
+}
+
+func (e Employee) MarshalJSON() ([]byte, error) {
+	return json.Marshal(employeeJSON{
+		personJSON: e.Person.toJSON(),
+		EmployeeID: e.EmployeeID,
+		Salary:     e.Salary,
+		Manages:    e.Manages,
+	})
+}
+
+// UnmarshalJSON decodes an employee written by MarshalJSON. Manager is left
+// nil, decoding the manager sets it.
+func (e *Employee) UnmarshalJSON(data []byte) error {
+	var p Person
+	if err := p.UnmarshalJSON(data); err != nil {
+		return err
+	}
+	var in struct {
+		EmployeeID string   `json:"employee_id"`
+		Salary     float64  `json:"salary"`
+		Manages    *Manager `json:"manages"`
+	}
+	if err := json.Unmarshal(data, &in); err != nil {
+		return err
+	}
+	*e = Employee{Person: p, EmployeeID: in.EmployeeID, Salary: in.Salary, Manages: in.Manages}
+	return nil
+}
+
+func (m Manager) MarshalJSON() ([]byte, error) {
+	return json.Marshal(managerJSON{
+		personJSON: m.Person.toJSON(),
+		Department: m.Department,
+		Reports:    m.Reports,
+	})
+}
+
+// UnmarshalJSON decodes a manager written by MarshalJSON, along with their
+// reports, and points each report's Manager back at m. Reports go through
+// AddReport, so duplicate employee IDs are rejected.
+func (m *Manager) UnmarshalJSON(data []byte) error {
+	var p Person
+	if err := p.UnmarshalJSON(data); err != nil {
+		return err
+	}
+	var in struct {
+		Department string      `json:"department"`
+		Reports    []*Employee `json:"reports"`
+	}
+	if err := json.Unmarshal(data, &in); err != nil {
+		return err
+	}
+	out := Manager{Person: p, Department: in.Department}
+	for _, r := range in.Reports {
+		if err := out.AddReport(r); err != nil {
+			return err
+		}
+	}
+	*m = out
+	// AddReport pointed the reports at out, which has just been copied
+	for _, r := range m.Reports {
+		r.Manager = m
+	}
+	return nil
+}
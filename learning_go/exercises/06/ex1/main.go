@@ -1,13 +1,32 @@
 package main
 
-import "fmt"
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"unicode"
+)
 
 type Person struct {
 	FirstName string
 	LastName  string
 	Age       int
+	Address
 }
 
+// ValidationError describes a problem with one field of a Person.
+type ValidationError struct {
+	Field   string
+	Message string
+}
+
+func (ve ValidationError) Error() string {
+	return ve.Field + ": " + ve.Message
+}
+
+const maxAge = 150
+
 func MakePerson(firstName, lastName string, age int) Person {
 	return Person{
 		FirstName: firstName,
@@ -24,10 +43,107 @@ func MakePersonPointer(firstName, lastName string, age int) *Person {
 	}
 }
 
+// MakePersonValidated builds a Person and returns an error if any of its
+// fields are invalid.
+func MakePersonValidated(firstName, lastName string, age int) (Person, error) {
+	p := MakePerson(firstName, lastName, age)
+	if err := p.Validate(); err != nil {
+		return Person{}, err
+	}
+	return p, nil
+}
+
+func validateName(field, name string) error {
+	if name == "" {
+		return ValidationError{Field: field, Message: "must not be empty"}
+	}
+	for _, r := range name {
+		if !unicode.IsLetter(r) {
+			return ValidationError{Field: field, Message: fmt.Sprintf("must only contain letters, found %q", r)}
+		}
+	}
+	return nil
+}
+
+// Validate checks every field of the Person. When more than one field is
+// invalid the ValidationErrors are combined with errors.Join, use errors.As
+// to get at the first one.
+func (p Person) Validate() error {
+	var errs []error
+	if err := validateName("FirstName", p.FirstName); err != nil {
+		errs = append(errs, err)
+	}
+	if err := validateName("LastName", p.LastName); err != nil {
+		errs = append(errs, err)
+	}
+	if p.Age < 0 || p.Age > maxAge {
+		errs = append(errs, ValidationError{Field: "Age", Message: fmt.Sprintf("must be between 0 and %d, got %d", maxAge, p.Age)})
+	}
+	return errors.Join(errs...)
+}
+
 // compile with -gcflags="-m"
 func main() {
 	p := MakePerson("Nitin", "Kunaparaju", 16)
 	fmt.Println(p)
 	p2 := MakePersonPointer("Anish", "Kunaparaju", 13)
 	fmt.Println(p2)
+	_, err := MakePersonValidated("", "Kunaparaju2", -1)
+	fmt.Println(err)
+
+	data, err := json.Marshal(p)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	fmt.Println(string(data))
+	fmt.Println(MustUnmarshalPerson([]byte(`{"FirstName":"Anish","LastName":"Kunaparaju","Age":13}`)))
+	var bad Person
+	fmt.Println(json.Unmarshal([]byte(`{"first_name":"Anish","last_name":"Kunaparaju","age":-13}`), &bad))
+
+	people := []Person{
+		p,
+		*p2,
+		MakePerson("Fred", "Williamson", 25),
+		MakePerson("Maria", "Williamson", 70),
+	}
+	if err := SortPeopleStable(people, "full"); err != nil {
+		fmt.Println(err)
+	}
+	fmt.Println(people)
+	fmt.Println(SortPeople(people, "height"))
+	fmt.Println(GroupByAge(people))
+
+	home := Address{Street: "1 Main St", City: "Springfield", State: "IL", PostalCode: "62701", Country: "USA"}
+	p3 := MakePersonWithAddress("Nitin", "Kunaparaju", 16, home)
+	p4 := MakePersonWithAddress("Anish", "Kunaparaju", 13, Address{City: "Springfield", State: "IL"})
+	fmt.Println(p3.FullAddress())
+	fmt.Println(p3.IsLocalTo(p4))
+	fmt.Println(p3.IsLocalTo(p))
+
+	cto := &Manager{Person: MakePerson("Maria", "Williamson", 52), Department: "Engineering"}
+	lead := &Employee{Person: MakePerson("Fred", "Williamson", 41), EmployeeID: "E1", Salary: 150_000}
+	lead.Manages = &Manager{Person: lead.Person, Department: "Platform"}
+	dev := &Employee{Person: MakePerson("Nitin", "Kunaparaju", 30), EmployeeID: "E2", Salary: 120_000}
+	for _, err := range []error{
+		cto.AddReport(lead),
+		lead.Manages.AddReport(dev),
+		lead.Manages.AddReport(dev),
+		dev.GiveRaise(10),
+	} {
+		if err != nil {
+			fmt.Println(err)
+		}
+	}
+	fmt.Println(cto.TeamSize(), cto.TotalTeamSalary())
+
+	var buf bytes.Buffer
+	if err := ExportEmployees([]Employee{*lead, *dev}, &buf); err != nil {
+		fmt.Println(err)
+		return
+	}
+	buf.WriteString("E3,Pat,,200,1000\n")
+	fmt.Print(buf.String())
+	imported, err := ImportEmployees(&buf)
+	fmt.Println(len(imported), err)
 }
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+)
+
+type Employee struct {
+	Person
+	EmployeeID string
+	Salary     float64
+	Manager    *Manager
+	// Manages is set when the employee runs a team of their own.
+	Manages *Manager
+}
+
+type Manager struct {
+	Person
+	Department string
+	Reports    []*Employee
+}
+
+// AddReport puts e on the manager's team. Every report needs a unique
+// EmployeeID.
+func (m *Manager) AddReport(e *Employee) error {
+	if e == nil {
+		return errors.New("can't add a nil employee")
+	}
+	for _, r := range m.Reports {
+		if r.EmployeeID == e.EmployeeID {
+			return fmt.Errorf("employee %s already reports to %s %s", e.EmployeeID, m.FirstName, m.LastName)
+		}
+	}
+	e.Manager = m
+	m.Reports = append(m.Reports, e)
+	return nil
+}
+
+// TeamSize counts everyone under the manager, including the teams of any
+// reports who are managers themselves.
+func (m *Manager) TeamSize() int {
+	size := 0
+	for _, r := range m.Reports {
+		size++
+		if r.Manages != nil {
+			size += r.Manages.TeamSize()
+		}
+	}
+	return size
+}
+
+// TotalTeamSalary adds up the salaries of everyone counted by TeamSize.
+func (m *Manager) TotalTeamSalary() float64 {
+	var total float64
+	for _, r := range m.Reports {
+		total += r.Salary
+		if r.Manages != nil {
+			total += r.Manages.TotalTeamSalary()
+		}
+	}
+	return total
+}
+
+// GiveRaise increases the employee's salary by percent, so 10 is a 10% raise.
+func (e *Employee) GiveRaise(percent float64) error {
+	if percent <= 0 {
+		return fmt.Errorf("raise must be a positive percentage, got %v", percent)
+	}
+	e.Salary *= 1 + percent/100
+	return nil
+}
@@ -0,0 +1,109 @@
+package main
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+)
+
+// orgTree builds a two level org: a CTO with a platform lead, who manages a
+// team of their own, and a developer reporting to the lead.
+func orgTree(t *testing.T) (cto *Manager, lead, dev, analyst *Employee) {
+	t.Helper()
+	cto = &Manager{Person: MakePerson("Maria", "Williamson", 52), Department: "Engineering"}
+	lead = &Employee{Person: MakePerson("Fred", "Williamson", 41), EmployeeID: "E1", Salary: 150_000}
+	lead.Manages = &Manager{Person: lead.Person, Department: "Platform"}
+	analyst = &Employee{Person: MakePerson("Ada", "Byron", 36), EmployeeID: "E3", Salary: 90_000}
+	dev = &Employee{Person: MakePerson("Nitin", "Kunaparaju", 30), EmployeeID: "E2", Salary: 120_000}
+	for _, add := range []struct {
+		m *Manager
+		e *Employee
+	}{{cto, lead}, {cto, analyst}, {lead.Manages, dev}} {
+		if err := add.m.AddReport(add.e); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return cto, lead, dev, analyst
+}
+
+func TestOrgTree(t *testing.T) {
+	cto, lead, dev, _ := orgTree(t)
+	if got := cto.TeamSize(); got != 3 {
+		t.Errorf("cto.TeamSize() = %d, want 3", got)
+	}
+	if got := lead.Manages.TeamSize(); got != 1 {
+		t.Errorf("lead.Manages.TeamSize() = %d, want 1", got)
+	}
+	if got := cto.TotalTeamSalary(); got != 360_000 {
+		t.Errorf("cto.TotalTeamSalary() = %v, want 360000", got)
+	}
+	if dev.Manager != lead.Manages || lead.Manager != cto {
+		t.Error("AddReport didn't set Manager")
+	}
+
+	if err := dev.GiveRaise(10); err != nil {
+		t.Fatal(err)
+	}
+	if math.Abs(dev.Salary-132_000) > 1e-6 {
+		t.Errorf("salary after a 10%% raise = %v, want 132000", dev.Salary)
+	}
+	if got := cto.TotalTeamSalary(); math.Abs(got-372_000) > 1e-6 {
+		t.Errorf("cto.TotalTeamSalary() after the raise = %v, want 372000", got)
+	}
+	for _, percent := range []float64{0, -5} {
+		if err := dev.GiveRaise(percent); err == nil {
+			t.Errorf("GiveRaise(%v) succeeded", percent)
+		}
+	}
+}
+
+func TestAddReportErrors(t *testing.T) {
+	cto, _, _, _ := orgTree(t)
+	if err := cto.AddReport(nil); err == nil {
+		t.Error("AddReport(nil) succeeded")
+	}
+	dup := &Employee{Person: MakePerson("Someone", "Else", 30), EmployeeID: "E1"}
+	if err := cto.AddReport(dup); err == nil {
+		t.Error("AddReport with a duplicate EmployeeID succeeded")
+	}
+	if cto.TeamSize() != 3 {
+		t.Errorf("TeamSize() = %d after failed AddReports, want 3", cto.TeamSize())
+	}
+}
+
+func TestOrgTreeJSON(t *testing.T) {
+	cto, _, _, _ := orgTree(t)
+	data, err := json.Marshal(cto)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var back Manager
+	if err := json.Unmarshal(data, &back); err != nil {
+		t.Fatalf("Unmarshal(%s): %v", data, err)
+	}
+	if back.FirstName != "Maria" || back.Department != "Engineering" || len(back.Reports) != 2 {
+		t.Fatalf("decoded %+v", back)
+	}
+	lead := back.Reports[0]
+	if lead.EmployeeID != "E1" || lead.Salary != 150_000 || lead.FirstName != "Fred" || lead.Manager != &back {
+		t.Errorf("decoded lead %+v", lead)
+	}
+	if lead.Manages == nil || lead.Manages.Department != "Platform" || len(lead.Manages.Reports) != 1 {
+		t.Fatalf("decoded lead's team %+v", lead.Manages)
+	}
+	if dev := lead.Manages.Reports[0]; dev.EmployeeID != "E2" || dev.Salary != 120_000 || dev.Manager != lead.Manages {
+		t.Errorf("decoded dev %+v", dev)
+	}
+	if back.TeamSize() != 3 || back.TotalTeamSalary() != 360_000 {
+		t.Errorf("decoded tree has TeamSize %d and TotalTeamSalary %v", back.TeamSize(), back.TotalTeamSalary())
+	}
+
+	// An employee on its own keeps its fields too
+	data, err = json.Marshal(cto.Reports[1])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `{"first_name":"Ada","last_name":"Byron","age":36,"employee_id":"E3","salary":90000}`; string(data) != want {
+		t.Errorf("Marshal(analyst) = %s, want %s", data, want)
+	}
+}
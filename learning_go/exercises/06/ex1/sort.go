@@ -0,0 +1,68 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+)
+
+// peopleLess returns the comparison for one of the sort keys accepted by
+// SortPeople.
+func peopleLess(people []Person, by string) (func(i, j int) bool, error) {
+	switch by {
+	case "last":
+		return func(i, j int) bool { return people[i].LastName < people[j].LastName }, nil
+	case "first":
+		return func(i, j int) bool { return people[i].FirstName < people[j].FirstName }, nil
+	case "age":
+		return func(i, j int) bool { return people[i].Age < people[j].Age }, nil
+	case "full":
+		return func(i, j int) bool {
+			if people[i].LastName != people[j].LastName {
+				return people[i].LastName < people[j].LastName
+			}
+			return people[i].FirstName < people[j].FirstName
+		}, nil
+	default:
+		return nil, fmt.Errorf("unknown sort key %q, expected last, first, age, or full", by)
+	}
+}
+
+// SortPeople sorts people in place by "last", "first", "age", or "full"
+// (last name, then first name). People with equal keys may end up in any
+// order, use SortPeopleStable to keep their original order.
+func SortPeople(people []Person, by string) error {
+	less, err := peopleLess(people, by)
+	if err != nil {
+		return err
+	}
+	sort.Slice(people, less)
+	return nil
+}
+
+// SortPeopleStable is SortPeople, but people with equal keys stay in the
+// order they were in.
+func SortPeopleStable(people []Person, by string) error {
+	less, err := peopleLess(people, by)
+	if err != nil {
+		return err
+	}
+	sort.SliceStable(people, less)
+	return nil
+}
+
+// GroupByAge puts each person into a "child" (0-17), "adult" (18-64), or
+// "senior" (65 and over) bucket.
+func GroupByAge(people []Person) map[string][]Person {
+	groups := map[string][]Person{}
+	for _, p := range people {
+		switch {
+		case p.Age < 18:
+			groups["child"] = append(groups["child"], p)
+		case p.Age < 65:
+			groups["adult"] = append(groups["adult"], p)
+		default:
+			groups["senior"] = append(groups["senior"], p)
+		}
+	}
+	return groups
+}
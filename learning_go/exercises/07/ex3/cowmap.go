@@ -0,0 +1,99 @@
+package main
+
+import (
+	"encoding/json"
+	"maps"
+	"sync"
+	"sync/atomic"
+)
+
+// COWMap is a copy-on-write map. Reads load the current map without taking
+// a lock, and each write copies the map, changes the copy and swaps it in.
+// That suits data that many goroutines read and few write, but every write
+// costs a copy of the whole map. The zero value is an empty
+// map ready to use. Like a map, a nil *COWMap can be read from and deleted
+// from, but not set.
+type COWMap[K comparable, V any] struct {
+	// mu serialises writers, so two of them can't copy the same map and
+	// lose one of the changes
+	mu sync.Mutex
+	m  atomic.Pointer[map[K]V]
+}
+
+// NewCOWMap returns a COWMap holding a copy of m.
+func NewCOWMap[K comparable, V any](m map[K]V) *COWMap[K, V] {
+	c := &COWMap[K, V]{}
+	cp := maps.Clone(m)
+	c.m.Store(&cp)
+	return c
+}
+
+// load returns the current map, which must not be changed.
+func (c *COWMap[K, V]) load() map[K]V {
+	if c == nil {
+		return nil
+	}
+	if p := c.m.Load(); p != nil {
+		return *p
+	}
+	return nil
+}
+
+// Get returns the value for k and whether it was present.
+func (c *COWMap[K, V]) Get(k K) (V, bool) {
+	v, ok := c.load()[k]
+	return v, ok
+}
+
+// Len returns the number of keys.
+func (c *COWMap[K, V]) Len() int {
+	return len(c.load())
+}
+
+// Snapshot returns the map as it is now. Later writes don't show up in it,
+// and it's shared with other readers, so it must not be changed.
+func (c *COWMap[K, V]) Snapshot() map[K]V {
+	return c.load()
+}
+
+// Set sets the value for k.
+func (c *COWMap[K, V]) Set(k K, v V) {
+	c.update(func(m map[K]V) { m[k] = v })
+}
+
+// Delete removes k.
+func (c *COWMap[K, V]) Delete(k K) {
+	if c == nil {
+		return
+	}
+	c.update(func(m map[K]V) { delete(m, k) })
+}
+
+// MarshalJSON encodes the map as a JSON object.
+func (c *COWMap[K, V]) MarshalJSON() ([]byte, error) {
+	return json.Marshal(c.load())
+}
+
+// UnmarshalJSON replaces the map with a JSON object.
+func (c *COWMap[K, V]) UnmarshalJSON(data []byte) error {
+	var m map[K]V
+	if err := json.Unmarshal(data, &m); err != nil {
+		return err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.m.Store(&m)
+	return nil
+}
+
+// update applies change to a copy of the map and makes the copy current.
+func (c *COWMap[K, V]) update(change func(map[K]V)) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	m := maps.Clone(c.load())
+	if m == nil {
+		m = map[K]V{}
+	}
+	change(m)
+	c.m.Store(&m)
+}
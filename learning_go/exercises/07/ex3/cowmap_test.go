@@ -0,0 +1,143 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+	"testing"
+)
+
+func TestCOWMap(t *testing.T) {
+	var c COWMap[string, int]
+	if _, ok := c.Get("USA"); ok || c.Len() != 0 {
+		t.Fatal("zero COWMap isn't empty")
+	}
+	c.Set("USA", 1)
+	c.Set("Canada", 2)
+	before := c.Snapshot()
+	c.Set("USA", 3)
+	c.Delete("Canada")
+	if v, ok := c.Get("USA"); !ok || v != 3 {
+		t.Errorf("Get(USA) = %d, %t, want 3, true", v, ok)
+	}
+	if _, ok := c.Get("Canada"); ok || c.Len() != 1 {
+		t.Errorf("Canada still there after Delete, Len() = %d", c.Len())
+	}
+	// A snapshot doesn't see later writes
+	if before["USA"] != 1 || before["Canada"] != 2 {
+		t.Errorf("snapshot changed to %v", before)
+	}
+
+	var nilMap *COWMap[string, int]
+	if _, ok := nilMap.Get("USA"); ok || nilMap.Len() != 0 {
+		t.Error("nil COWMap isn't empty")
+	}
+	nilMap.Delete("USA")
+}
+
+func TestNewCOWMapCopies(t *testing.T) {
+	m := map[string]int{"USA": 1}
+	c := NewCOWMap(m)
+	m["USA"] = 2
+	if v, _ := c.Get("USA"); v != 1 {
+		t.Errorf("Get(USA) = %d after changing the original map, want 1", v)
+	}
+}
+
+func TestCOWMapJSON(t *testing.T) {
+	c := NewCOWMap(map[string]int{"USA": 2, "Canada": 1})
+	data, err := json.Marshal(c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `{"Canada":1,"USA":2}`; string(data) != want {
+		t.Errorf("Marshal = %s, want %s", data, want)
+	}
+	var back *COWMap[string, int]
+	if err := json.Unmarshal(data, &back); err != nil {
+		t.Fatal(err)
+	}
+	if v, _ := back.Get("USA"); v != 2 || back.Len() != 2 {
+		t.Errorf("round trip gave %v", back.Snapshot())
+	}
+}
+
+// TestCOWMapConcurrent is mostly for the race detector: readers never lock,
+// and concurrent writers mustn't lose each other's keys.
+func TestCOWMapConcurrent(t *testing.T) {
+	var c COWMap[int, int]
+	var wg sync.WaitGroup
+	for w := 0; w < 8; w++ {
+		wg.Add(2)
+		go func(w int) {
+			defer wg.Done()
+			for i := 0; i < 100; i++ {
+				c.Set(w*100+i, i)
+			}
+		}(w)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 1000; i++ {
+				c.Get(i)
+			}
+		}()
+	}
+	wg.Wait()
+	if c.Len() != 800 {
+		t.Errorf("Len() = %d after 800 concurrent Sets, want 800", c.Len())
+	}
+}
+
+// benchmarkWins does 100 000 reads spread over 8 goroutines per op, while
+// another goroutine makes 1000 writes, on the wins of 32 teams.
+func benchmarkWins(b *testing.B, get func(string) int, set func(string, int)) {
+	const readers, reads, writes = 8, 100_000, 1000
+	teams := make([]string, 32)
+	for i := range teams {
+		teams[i] = fmt.Sprintf("Team %d", i)
+		set(teams[i], i)
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		var wg sync.WaitGroup
+		for r := 0; r < readers; r++ {
+			wg.Add(1)
+			go func(r int) {
+				defer wg.Done()
+				for j := 0; j < reads/readers; j++ {
+					get(teams[(r+j)%len(teams)])
+				}
+			}(r)
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < writes; j++ {
+				set(teams[j%len(teams)], j)
+			}
+		}()
+		wg.Wait()
+	}
+}
+
+func BenchmarkWinsCOWMap(b *testing.B) {
+	var c COWMap[string, int]
+	benchmarkWins(b, func(k string) int {
+		v, _ := c.Get(k)
+		return v
+	}, c.Set)
+}
+
+func BenchmarkWinsRWMutex(b *testing.B) {
+	var mu sync.RWMutex
+	m := map[string]int{}
+	benchmarkWins(b, func(k string) int {
+		mu.RLock()
+		defer mu.RUnlock()
+		return m[k]
+	}, func(k string, v int) {
+		mu.Lock()
+		defer mu.Unlock()
+		m[k] = v
+	})
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// defaultBusBuffer is the channel buffer a zero Bus gives its subscribers.
+const defaultBusBuffer = 16
+
+// Bus delivers events to every subscriber. Publish never waits: a
+// subscriber whose channel is full misses the event, and the miss is
+// counted by Dropped. The zero value is ready to use.
+type Bus[T any] struct {
+	mu     sync.RWMutex
+	subs   map[<-chan T]chan T
+	buffer int
+	// dropped is read without the lock, Publish only holds it for reading
+	dropped atomic.Int64
+}
+
+// NewBus returns a Bus whose subscribers' channels hold up to buffer events
+// that haven't been received yet.
+func NewBus[T any](buffer int) *Bus[T] {
+	return &Bus[T]{buffer: max(buffer, 0)}
+}
+
+// Subscribe returns a channel that receives every event published from now
+// on, until Unsubscribe is called with it.
+func (b *Bus[T]) Subscribe() <-chan T {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.subs == nil {
+		b.subs = map[<-chan T]chan T{}
+		if b.buffer == 0 {
+			b.buffer = defaultBusBuffer
+		}
+	}
+	ch := make(chan T, b.buffer)
+	b.subs[ch] = ch
+	return ch
+}
+
+// Unsubscribe stops sending events to ch and closes it. Events already in
+// its buffer can still be received.
+func (b *Bus[T]) Unsubscribe(ch <-chan T) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if c, ok := b.subs[ch]; ok {
+		delete(b.subs, ch)
+		close(c)
+	}
+}
+
+// Publish sends event to every subscriber that has room for it.
+func (b *Bus[T]) Publish(event T) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	for _, ch := range b.subs {
+		select {
+		case ch <- event:
+		default:
+			b.dropped.Add(1)
+		}
+	}
+}
+
+// Dropped returns how many times an event was skipped because a
+// subscriber's channel was full.
+func (b *Bus[T]) Dropped() int64 {
+	return b.dropped.Load()
+}
+
+// MatchEvent is published on League.EventBus for each result recorded by
+// MatchResult or Forfeit.
+type MatchEvent struct {
+	Team1     string    `json:"team1"`
+	Score1    int       `json:"score1"`
+	Team2     string    `json:"team2"`
+	Score2    int       `json:"score2"`
+	Forfeit   bool      `json:"forfeit,omitempty"`
+	Timestamp time.Time `json:"timestamp"`
+}
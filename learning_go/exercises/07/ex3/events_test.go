@@ -0,0 +1,139 @@
+package main
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestBusEverySubscriber(t *testing.T) {
+	b := NewBus[int](10)
+	subs := []<-chan int{b.Subscribe(), b.Subscribe(), b.Subscribe()}
+	for i := 1; i <= 5; i++ {
+		b.Publish(i)
+	}
+	for n, ch := range subs {
+		for want := 1; want <= 5; want++ {
+			select {
+			case got := <-ch:
+				if got != want {
+					t.Errorf("subscriber %d got %d, want %d", n, got, want)
+				}
+			default:
+				t.Fatalf("subscriber %d is missing event %d", n, want)
+			}
+		}
+	}
+	if d := b.Dropped(); d != 0 {
+		t.Errorf("Dropped = %d, want 0", d)
+	}
+}
+
+// TestBusSlowSubscriber checks a subscriber that never receives doesn't
+// hold up Publish or the other subscribers, and its misses are counted.
+func TestBusSlowSubscriber(t *testing.T) {
+	b := NewBus[int](2)
+	b.Subscribe() // never read
+	fast := b.Subscribe()
+
+	const events = 100
+	received := make(chan int, events)
+	go func() {
+		for e := range fast {
+			received <- e
+		}
+		close(received)
+	}()
+	done := make(chan struct{})
+	go func() {
+		for i := 0; i < events; i++ {
+			b.Publish(i)
+			// Give the fast subscriber a chance to keep up
+			time.Sleep(100 * time.Microsecond)
+		}
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Publish blocked on a subscriber that isn't receiving")
+	}
+	// The slow subscriber missed everything past its buffer of 2
+	if d := b.Dropped(); d < events-2 {
+		t.Errorf("Dropped = %d, want at least %d", d, events-2)
+	}
+	// Unsubscribing closes fast, which ends the receiving goroutine
+	b.Unsubscribe(fast)
+	n := 0
+	for range received {
+		n++
+	}
+	if n == 0 {
+		t.Error("the fast subscriber received nothing")
+	}
+}
+
+func TestBusUnsubscribe(t *testing.T) {
+	var b Bus[string]
+	ch := b.Subscribe()
+	b.Publish("before")
+	b.Unsubscribe(ch)
+	b.Publish("after")
+	// Buffered events can still be received, then the channel is closed
+	if e, ok := <-ch; !ok || e != "before" {
+		t.Errorf("got %q, %t, want before", e, ok)
+	}
+	if e, ok := <-ch; ok {
+		t.Errorf("got %q after Unsubscribe", e)
+	}
+	// Unsubscribing twice is harmless
+	b.Unsubscribe(ch)
+}
+
+func TestBusConcurrent(t *testing.T) {
+	b := NewBus[int](1000)
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				b.Publish(j)
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			ch := b.Subscribe()
+			b.Unsubscribe(ch)
+			for range ch {
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func TestMatchResultPublished(t *testing.T) {
+	l := &League{Teams: map[string]Team{"A": {Name: "A"}, "B": {Name: "B"}}, EventBus: NewBus[MatchEvent](4)}
+	events := l.EventBus.Subscribe()
+	if err := l.MatchResult("A", 2, "B", 1); err != nil {
+		t.Fatal(err)
+	}
+	// A rejected result isn't published
+	if err := l.MatchResult("A", 2, "nobody", 1); err == nil {
+		t.Fatal("MatchResult with an unknown team succeeded")
+	}
+	select {
+	case e := <-events:
+		want := MatchEvent{Team1: "A", Score1: 2, Team2: "B", Score2: 1, Timestamp: e.Timestamp}
+		if e != want || e.Timestamp.IsZero() {
+			t.Errorf("got event %+v, want %+v", e, want)
+		}
+	default:
+		t.Fatal("the result wasn't published")
+	}
+	select {
+	case e := <-events:
+		t.Errorf("got a second event %+v", e)
+	default:
+	}
+}
@@ -0,0 +1,133 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Fixture is a game that has been scheduled between two teams.
+type Fixture struct {
+	Round int
+	Home  string
+	Away  string
+}
+
+// GenerateFixtures replaces the league's schedule with a round robin where
+// every team plays every other team once, or twice (home and away) when
+// DoubleRoundRobin is set. Rounds are numbered from 1.
+func (l *League) GenerateFixtures() {
+	names := make([]string, 0, len(l.Teams)+1)
+	for k := range l.Teams {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+	// The circle method needs an even number of teams, whoever is paired
+	// with the empty name has a bye that round
+	if len(names)%2 == 1 {
+		names = append(names, "")
+	}
+	n := len(names)
+	var fixtures []Fixture
+	for round := 1; round < n; round++ {
+		for i := 0; i < n/2; i++ {
+			home, away := names[i], names[n-1-i]
+			if home == "" || away == "" {
+				continue
+			}
+			// Keep the fixed team from always playing at home
+			if i == 0 && round%2 == 0 {
+				home, away = away, home
+			}
+			fixtures = append(fixtures, Fixture{Round: round, Home: home, Away: away})
+		}
+		// Rotate every team but the first one place clockwise
+		last := names[n-1]
+		copy(names[2:], names[1:n-1])
+		names[1] = last
+	}
+	if l.DoubleRoundRobin {
+		rounds := n - 1
+		for _, f := range fixtures {
+			if f.Round > rounds {
+				break
+			}
+			fixtures = append(fixtures, Fixture{Round: f.Round + rounds, Home: f.Away, Away: f.Home})
+		}
+	}
+	l.Fixtures = fixtures
+}
+
+// playedFixtures reports, for each of the league's fixtures, whether a
+// result has been recorded for it. Each match in the history can only
+// account for a single fixture. Unless DoubleRoundRobin is set, it doesn't
+// matter which team was listed first in the result.
+func (l League) playedFixtures() []bool {
+	played := make([]bool, len(l.Fixtures))
+	used := make([]bool, len(l.history))
+	for i, f := range l.Fixtures {
+		for j, m := range l.history {
+			if used[j] {
+				continue
+			}
+			sameOrder := m.Team1 == f.Home && m.Team2 == f.Away
+			swapped := m.Team1 == f.Away && m.Team2 == f.Home
+			if sameOrder || (swapped && !l.DoubleRoundRobin) {
+				used[j] = true
+				played[i] = true
+				break
+			}
+		}
+	}
+	return played
+}
+
+// unplayedFixtures returns the fixtures that don't have a result yet, in
+// schedule order.
+func (l League) unplayedFixtures() []Fixture {
+	var out []Fixture
+	for i, played := range l.playedFixtures() {
+		if !played {
+			out = append(out, l.Fixtures[i])
+		}
+	}
+	return out
+}
+
+// unplayedFixture returns the first fixture between a and b, in schedule
+// order and at either team's home, that doesn't have a result yet.
+func (l League) unplayedFixture(a, b string) (Fixture, bool) {
+	for _, f := range l.unplayedFixtures() {
+		if (f.Home == a && f.Away == b) || (f.Home == b && f.Away == a) {
+			return f, true
+		}
+	}
+	return Fixture{}, false
+}
+
+// RemainingFixtures returns the fixtures that team still has to play, in
+// round order.
+func (l League) RemainingFixtures(team string) ([]Fixture, error) {
+	if _, ok := l.Teams[team]; !ok {
+		return nil, fmt.Errorf("unknown team: %s", team)
+	}
+	var out []Fixture
+	for _, f := range l.unplayedFixtures() {
+		if f.Home == team || f.Away == team {
+			out = append(out, f)
+		}
+	}
+	sort.SliceStable(out, func(i, j int) bool {
+		return out[i].Round < out[j].Round
+	})
+	return out, nil
+}
+
+// NextFixture returns the next game team has to play. It returns false if the
+// team is unknown or has no games left.
+func (l League) NextFixture(team string) (Fixture, bool) {
+	remaining, err := l.RemainingFixtures(team)
+	if err != nil || len(remaining) == 0 {
+		return Fixture{}, false
+	}
+	return remaining[0], true
+}
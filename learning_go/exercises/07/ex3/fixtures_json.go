@@ -0,0 +1,52 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// leagueDocument is the JSON layout read by NewLeagueFromJSON.
+type leagueDocument struct {
+	Name  string `json:"name"`
+	Teams []struct {
+		Name    string   `json:"name"`
+		Group   string   `json:"group"`
+		Players []string `json:"players"`
+	} `json:"teams"`
+}
+
+// NewLeagueFromJSON builds a league from a document like:
+//
+//	{"name": "Big League", "teams": [{"name": "USA", "players": ["Player1"]}]}
+//
+// Teams may also have a "group". Empty or duplicate team names and players
+// listed twice on the same team are rejected.
+func NewLeagueFromJSON(r io.Reader) (*League, error) {
+	var doc leagueDocument
+	if err := json.NewDecoder(r).Decode(&doc); err != nil {
+		return nil, err
+	}
+	l := &League{
+		Name:  doc.Name,
+		Teams: make(map[string]Team, len(doc.Teams)),
+		Wins:  make(map[string]int, len(doc.Teams)),
+	}
+	for i, t := range doc.Teams {
+		if t.Name == "" {
+			return nil, fmt.Errorf("team %d has no name", i+1)
+		}
+		if _, ok := l.Teams[t.Name]; ok {
+			return nil, fmt.Errorf("duplicate team %q", t.Name)
+		}
+		seen := map[string]bool{}
+		for _, p := range t.Players {
+			if seen[p] {
+				return nil, fmt.Errorf("team %q lists player %q more than once", t.Name, p)
+			}
+			seen[p] = true
+		}
+		l.Teams[t.Name] = Team{Name: t.Name, Group: t.Group, Players: t.Players}
+	}
+	return l, nil
+}
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"os"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestNewLeagueFromJSON(t *testing.T) {
+	f, err := os.Open("league.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	l, err := NewLeagueFromJSON(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if l.Name != "Big League" {
+		t.Errorf("Name = %q, want Big League", l.Name)
+	}
+	groups := map[string]string{"USA": "North America", "Canada": "North America", "Serbia": "Europe", "Germany": "Europe"}
+	if len(l.Teams) != len(groups) {
+		t.Errorf("got %d teams, want %d", len(l.Teams), len(groups))
+	}
+	for name, group := range groups {
+		team, ok := l.Teams[name]
+		if !ok {
+			t.Errorf("%s is missing", name)
+			continue
+		}
+		if team.Name != name || team.Group != group || len(team.Players) != 5 {
+			t.Errorf("%s = %+v, want group %s and 5 players", name, team, group)
+		}
+	}
+	if l.Wins == nil {
+		t.Error("Wins isn't initialized")
+	}
+	// The league is ready to record results
+	if err := l.MatchResult("USA", 2, "Canada", 1); err != nil {
+		t.Fatal(err)
+	}
+	if got := l.Ranking(); got[0] != "USA" {
+		t.Errorf("Ranking = %v, want USA first", got)
+	}
+}
+
+func TestNewLeagueFromJSONErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		doc     string
+		wantErr string
+	}{
+		{"empty team name", `{"teams": [{"name": "USA"}, {"name": ""}]}`, "team 2 has no name"},
+		{"missing team name", `{"teams": [{"players": ["P1"]}]}`, "team 1 has no name"},
+		{"duplicate team", `{"teams": [{"name": "USA"}, {"name": "Canada"}, {"name": "USA"}]}`, `duplicate team "USA"`},
+		{"duplicate player", `{"teams": [{"name": "USA", "players": ["P1", "P2", "P1"]}]}`, `team "USA" lists player "P1" more than once`},
+		{"not JSON", `{"teams": [`, "unexpected EOF"},
+		{"wrong shape", `{"teams": {"name": "USA"}}`, "cannot unmarshal object"},
+	}
+	for _, tt := range tests {
+		l, err := NewLeagueFromJSON(strings.NewReader(tt.doc))
+		if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+			t.Errorf("%s: NewLeagueFromJSON error = %v, want %q", tt.name, err, tt.wantErr)
+		}
+		if l != nil {
+			t.Errorf("%s: got a league along with the error", tt.name)
+		}
+	}
+	// The same player can be on two teams
+	l, err := NewLeagueFromJSON(strings.NewReader(`{"teams": [{"name": "A", "players": ["P1"]}, {"name": "B", "players": ["P1"]}]}`))
+	if err != nil || !slices.Equal(l.Teams["B"].Players, []string{"P1"}) {
+		t.Errorf("NewLeagueFromJSON with a player on two teams = %v, %v", l, err)
+	}
+}
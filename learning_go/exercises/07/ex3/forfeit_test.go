@@ -0,0 +1,132 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+// forfeitLeague has two teams that meet home and away, with a penalty of one
+// win for forfeiting.
+func forfeitLeague(t *testing.T) *League {
+	t.Helper()
+	l := &League{
+		Teams:            map[string]Team{"A": {Name: "A"}, "B": {Name: "B"}},
+		DoubleRoundRobin: true,
+		ForfeitPenalty:   1,
+	}
+	l.GenerateFixtures()
+	if len(l.Fixtures) != 2 {
+		t.Fatalf("got %d fixtures, want 2", len(l.Fixtures))
+	}
+	return l
+}
+
+func TestForfeit(t *testing.T) {
+	l := forfeitLeague(t)
+	first := l.Fixtures[0]
+	if err := l.MatchResult(first.Home, 2, first.Away, 1); err != nil {
+		t.Fatal(err)
+	}
+	second := l.Fixtures[1]
+	// The home team of the second leg forfeits
+	if err := l.Forfeit(second.Home, second.Away); err != nil {
+		t.Fatalf("second leg forfeit: %v", err)
+	}
+
+	matches := l.Matches()
+	if len(matches) != 2 {
+		t.Fatalf("got %d matches, want 2", len(matches))
+	}
+	want := Match{ID: 2, Team1: second.Home, Score1: 0, Team2: second.Away, Score2: defaultForfeitScore, Forfeit: true}
+	if matches[1] != want {
+		t.Errorf("forfeit recorded as %+v, want %+v", matches[1], want)
+	}
+	if got := l.unplayedFixtures(); len(got) != 0 {
+		t.Errorf("fixtures still to play: %v", got)
+	}
+	// The first leg's loser forfeited the second, and loses a win for it
+	if got := l.WinCount(first.Home); got != 2 {
+		t.Errorf("%s has %d wins, want 2", first.Home, got)
+	}
+	if got := l.WinCount(first.Away); got != -1 {
+		t.Errorf("%s has %d wins, want -1", first.Away, got)
+	}
+	losses := map[string]int{first.Home: 0, first.Away: 2}
+	for _, row := range l.Standings() {
+		if row.Played != 2 || row.Losses != losses[row.Team] {
+			t.Errorf("standings row %+v, want 2 played and %d losses", row, losses[row.Team])
+		}
+	}
+}
+
+func TestForfeitAwayTeam(t *testing.T) {
+	l := forfeitLeague(t)
+	f := l.Fixtures[0]
+	if err := l.Forfeit(f.Away, f.Home); err != nil {
+		t.Fatal(err)
+	}
+	want := Match{ID: 1, Team1: f.Home, Score1: defaultForfeitScore, Team2: f.Away, Score2: 0, Forfeit: true}
+	if got := l.Matches()[0]; got != want {
+		t.Errorf("forfeit recorded as %+v, want %+v", got, want)
+	}
+	if got := l.unplayedFixtures(); len(got) != 1 || got[0] != l.Fixtures[1] {
+		t.Errorf("fixtures still to play: %v, want %v", got, l.Fixtures[1:])
+	}
+}
+
+func TestForfeitErrors(t *testing.T) {
+	l := forfeitLeague(t)
+	for _, f := range l.Fixtures {
+		if err := l.MatchResult(f.Home, 1, f.Away, 0); err != nil {
+			t.Fatal(err)
+		}
+	}
+	noFixtures := &League{Teams: map[string]Team{"A": {Name: "A"}, "B": {Name: "B"}}}
+	if err := noFixtures.MatchResult("B", 1, "A", 0); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name           string
+		l              *League
+		team, opponent string
+		want           string
+	}{
+		{"unknown team", l, "C", "A", "unknown team"},
+		{"unknown opponent", l, "A", "C", "unknown opponent"},
+		{"itself", l, "A", "A", "itself"},
+		{"both legs played", l, "A", "B", "no unplayed fixture"},
+		{"already played", noFixtures, "A", "B", "already played"},
+		{"negative score", &League{Teams: l.Teams, ForfeitScore: -3}, "A", "B", "forfeit score can't be negative"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			before := len(tt.l.Matches())
+			err := tt.l.Forfeit(tt.team, tt.opponent)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("got error %v, want one containing %q", err, tt.want)
+			}
+			if got := len(tt.l.Matches()); got != before {
+				t.Errorf("a match was recorded: %d matches, want %d", got, before)
+			}
+		})
+	}
+}
+
+func TestForfeitPublished(t *testing.T) {
+	l := forfeitLeague(t)
+	l.EventBus = NewBus[MatchEvent](4)
+	events := l.EventBus.Subscribe()
+	f := l.Fixtures[0]
+	if err := l.Forfeit(f.Away, f.Home); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case e := <-events:
+		want := MatchEvent{Team1: f.Home, Score1: defaultForfeitScore, Team2: f.Away, Score2: 0, Forfeit: true, Timestamp: e.Timestamp}
+		if e != want {
+			t.Errorf("got event %+v, want %+v", e, want)
+		}
+	default:
+		t.Fatal("the forfeit wasn't published")
+	}
+}
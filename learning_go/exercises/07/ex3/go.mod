@@ -1,3 +1,5 @@
 module 07_ex3
 
 go 1.21.3
+
+require nhooyr.io/websocket v1.8.17
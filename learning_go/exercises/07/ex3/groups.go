@@ -0,0 +1,52 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"sort"
+)
+
+// DefaultGroup holds the teams that don't have a Group set.
+const DefaultGroup = "Ungrouped"
+
+// GroupStandings returns the ranking within each group. Every match counts
+// towards a team's record, including matches against teams in other groups.
+func (l League) GroupStandings() map[string][]string {
+	groups := map[string][]string{}
+	for _, name := range l.Ranking() {
+		group := l.Teams[name].Group
+		if group == "" {
+			group = DefaultGroup
+		}
+		groups[group] = append(groups[group], name)
+	}
+	return groups
+}
+
+type GroupRanker interface {
+	GroupStandings() map[string][]string
+}
+
+// RankPrinterGrouped prints each group's standings under a header with the
+// group's name. Groups are printed in name order, with DefaultGroup last.
+// Like RankPrinter, win counts are included if r is a StatRanker.
+func RankPrinterGrouped(r GroupRanker, w io.Writer) {
+	groups := r.GroupStandings()
+	names := make([]string, 0, len(groups))
+	for name := range groups {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		if (names[i] == DefaultGroup) != (names[j] == DefaultGroup) {
+			return names[j] == DefaultGroup
+		}
+		return names[i] < names[j]
+	})
+	for i, name := range names {
+		if i > 0 {
+			io.WriteString(w, "\n")
+		}
+		fmt.Fprintf(w, "%s\n", name)
+		writeRanking(w, groups[name], r)
+	}
+}
@@ -0,0 +1,291 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"unicode"
+	"unicode/utf8"
+)
+
+// matchRequest is the body of POST /match.
+type matchRequest struct {
+	Team1  string `json:"team1"`
+	Score1 int    `json:"score1"`
+	Team2  string `json:"team2"`
+	Score2 int    `json:"score2"`
+}
+
+// teamRequest is the body of POST /teams.
+type teamRequest struct {
+	Name    string   `json:"name"`
+	Players []string `json:"players"`
+	Group   string   `json:"group"`
+}
+
+// teamResponse is a team's details and its line in the league table.
+type teamResponse struct {
+	StandingsRow
+	Group   string   `json:"group,omitempty"`
+	Players []string `json:"players"`
+}
+
+// maxNameLength limits team and player names sent to POST /teams.
+const maxNameLength = 100
+
+// maxRequestBody limits how much of a POST body is read.
+const maxRequestBody = 1 << 20
+
+// SyncLeague guards a League that's shared between handlers. League isn't
+// safe for concurrent use, even Ranking updates its cache, so LeagueHandler
+// and LiveMatchHandler hold the lock for every call they make on it. Handlers
+// made from the same SyncLeague can be mounted side by side.
+type SyncLeague struct {
+	mu sync.Mutex
+	l  *League
+}
+
+// NewSyncLeague returns a SyncLeague for l. Once it's been passed to a
+// handler, l must not be used except through Do.
+func NewSyncLeague(l *League) *SyncLeague {
+	return &SyncLeague{l: l}
+}
+
+// Do calls f with the league, holding the lock.
+func (s *SyncLeague) Do(f func(l *League)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	f(s.l)
+}
+
+// leagueHandler serves a League over HTTP. Every request holds mu, which is
+// the SyncLeague's lock.
+type leagueHandler struct {
+	mu  *sync.Mutex
+	l   *League
+	mux *http.ServeMux
+}
+
+// LeagueHandler returns a handler with these routes:
+//
+//	GET    /standings            the league table as a JSON array of StandingsRow
+//	POST   /match                record {"team1":"A","score1":3,"team2":"B","score2":1}
+//	                             and return the new table
+//	GET    /teams                the team names as a sorted JSON array
+//	POST   /teams                add {"name":"A","players":["P1"],"group":"G"},
+//	                             409 if the name is taken
+//	GET    /teams/{name}         the team's details and standings row
+//	DELETE /teams/{name}         remove the team, see League.RemoveTeam
+//	GET    /teams/{name}/players the team's players as a JSON array
+//
+// Names in paths are URL escaped. Invalid input gets a 400 and an unknown
+// team a 404, with the reason as {"error": "..."}. Requests are serialised
+// on s's lock.
+func LeagueHandler(s *SyncLeague) http.Handler {
+	h := &leagueHandler{mu: &s.mu, l: s.l, mux: http.NewServeMux()}
+	h.mux.HandleFunc("/standings", h.standings)
+	h.mux.HandleFunc("/match", h.match)
+	h.mux.HandleFunc("/teams", h.teams)
+	h.mux.HandleFunc("/teams/", h.team)
+	return h
+}
+
+func (h *leagueHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.mux.ServeHTTP(w, r)
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, map[string]string{"error": err.Error()})
+}
+
+// allowMethod writes a 405 and reports false unless r uses one of methods.
+func allowMethod(w http.ResponseWriter, r *http.Request, methods ...string) bool {
+	for _, m := range methods {
+		if r.Method == m {
+			return true
+		}
+	}
+	w.Header().Set("Allow", strings.Join(methods, ", "))
+	writeError(w, http.StatusMethodNotAllowed, errors.New("method not allowed"))
+	return false
+}
+
+// decodeBody decodes a JSON request body into v, rejecting unknown fields,
+// and writes a 400 if it can't.
+func decodeBody(w http.ResponseWriter, r *http.Request, v any) bool {
+	dec := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxRequestBody))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(v); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return false
+	}
+	return true
+}
+
+// teamStatus picks the status code for an error from AddTeam or
+// RemoveTeam.
+func teamStatus(err error) int {
+	switch {
+	case errors.Is(err, ErrUnknownTeam):
+		return http.StatusNotFound
+	case errors.Is(err, ErrDuplicateTeam):
+		return http.StatusConflict
+	default:
+		return http.StatusBadRequest
+	}
+}
+
+func (h *leagueHandler) standings(w http.ResponseWriter, r *http.Request) {
+	if !allowMethod(w, r, http.MethodGet) {
+		return
+	}
+	h.mu.Lock()
+	rows := h.l.Standings()
+	h.mu.Unlock()
+	writeJSON(w, http.StatusOK, rows)
+}
+
+func (h *leagueHandler) match(w http.ResponseWriter, r *http.Request) {
+	if !allowMethod(w, r, http.MethodPost) {
+		return
+	}
+	var req matchRequest
+	if !decodeBody(w, r, &req) {
+		return
+	}
+	h.mu.Lock()
+	err := h.l.MatchResult(req.Team1, req.Score1, req.Team2, req.Score2)
+	var rows []StandingsRow
+	if err == nil {
+		rows = h.l.Standings()
+	}
+	h.mu.Unlock()
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, rows)
+}
+
+func (h *leagueHandler) teams(w http.ResponseWriter, r *http.Request) {
+	if !allowMethod(w, r, http.MethodGet, http.MethodPost) {
+		return
+	}
+	if r.Method == http.MethodPost {
+		h.addTeam(w, r)
+		return
+	}
+	h.mu.Lock()
+	names := make([]string, 0, len(h.l.Teams))
+	for name := range h.l.Teams {
+		names = append(names, name)
+	}
+	h.mu.Unlock()
+	sort.Strings(names)
+	writeJSON(w, http.StatusOK, names)
+}
+
+// validName checks a team or player name from a request.
+func validName(kind, name string) error {
+	switch {
+	case strings.TrimSpace(name) == "":
+		return fmt.Errorf("%s name can't be empty", kind)
+	case len(name) > maxNameLength:
+		return fmt.Errorf("%s name is longer than %d bytes", kind, maxNameLength)
+	case !utf8.ValidString(name) || strings.IndexFunc(name, unicode.IsControl) >= 0:
+		return fmt.Errorf("%s name %q has invalid characters", kind, name)
+	}
+	return nil
+}
+
+func (h *leagueHandler) addTeam(w http.ResponseWriter, r *http.Request) {
+	var req teamRequest
+	if !decodeBody(w, r, &req) {
+		return
+	}
+	err := validName("team", req.Name)
+	for _, p := range req.Players {
+		if err == nil {
+			err = validName("player", p)
+		}
+	}
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	h.mu.Lock()
+	err = h.l.AddTeam(Team{Name: req.Name, Players: req.Players, Group: req.Group})
+	var resp teamResponse
+	if err == nil {
+		resp = h.teamDetails(req.Name)
+	}
+	h.mu.Unlock()
+	if err != nil {
+		writeError(w, teamStatus(err), err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, resp)
+}
+
+// teamDetails builds the response for a team that's known to exist. h.mu
+// must be held.
+func (h *leagueHandler) teamDetails(name string) teamResponse {
+	t := h.l.Teams[name]
+	resp := teamResponse{Group: t.Group, Players: append([]string{}, t.Players...)}
+	for _, row := range h.l.Standings() {
+		if row.Team == name {
+			resp.StandingsRow = row
+		}
+	}
+	return resp
+}
+
+// team serves /teams/{name} and /teams/{name}/players.
+func (h *leagueHandler) team(w http.ResponseWriter, r *http.Request) {
+	parts := strings.Split(strings.TrimPrefix(r.URL.EscapedPath(), "/teams/"), "/")
+	name, err := url.PathUnescape(parts[0])
+	if err != nil || name == "" || len(parts) > 2 || (len(parts) == 2 && parts[1] != "players") {
+		writeError(w, http.StatusNotFound, errors.New("not found"))
+		return
+	}
+	players := len(parts) == 2
+	if players && !allowMethod(w, r, http.MethodGet) {
+		return
+	}
+	if !players && !allowMethod(w, r, http.MethodGet, http.MethodDelete) {
+		return
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if r.Method == http.MethodDelete {
+		if err := h.l.RemoveTeam(name); err != nil {
+			writeError(w, teamStatus(err), err)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	t, ok := h.l.Teams[name]
+	if !ok {
+		err := fmt.Errorf("%w: %s", ErrUnknownTeam, name)
+		writeError(w, teamStatus(err), err)
+		return
+	}
+	if players {
+		writeJSON(w, http.StatusOK, append([]string{}, t.Players...))
+		return
+	}
+	writeJSON(w, http.StatusOK, h.teamDetails(name))
+}
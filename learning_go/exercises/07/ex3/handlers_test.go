@@ -0,0 +1,294 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"slices"
+	"sort"
+	"strings"
+	"testing"
+)
+
+// newTestHandler returns LeagueHandler for a league of USA, Canada and
+// Mexico with no results.
+func newTestHandler() http.Handler {
+	return LeagueHandler(NewSyncLeague(&League{Teams: map[string]Team{
+		"USA":    {Name: "USA", Players: []string{"Pulisic"}},
+		"Canada": {Name: "Canada"},
+		"Mexico": {Name: "Mexico"},
+	}}))
+}
+
+// serve sends a request straight to h and returns the recorded response.
+func serve(h http.Handler, method, path, body string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(method, path, strings.NewReader(body)))
+	return w
+}
+
+// decodeJSON checks the response is JSON with the given status and decodes
+// it into v, failing on any field v doesn't have.
+func decodeJSON(t *testing.T, status int, resp *http.Response, v any) {
+	t.Helper()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.StatusCode != status {
+		t.Fatalf("%s %s: status %d, want %d, body %s", resp.Request.Method, resp.Request.URL.Path, resp.StatusCode, status, body)
+	}
+	if ct := resp.Header.Get("Content-Type"); ct != "application/json" {
+		t.Fatalf("%s %s: Content-Type %q, want application/json", resp.Request.Method, resp.Request.URL.Path, ct)
+	}
+	dec := json.NewDecoder(bytes.NewReader(body))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(v); err != nil {
+		t.Fatalf("%s %s: decoding %s: %v", resp.Request.Method, resp.Request.URL.Path, body, err)
+	}
+}
+
+func TestLeagueHandlerStandings(t *testing.T) {
+	h := newTestHandler()
+	w := serve(h, http.MethodPost, "/match", `{"team1":"USA","score1":2,"team2":"Canada","score2":1}`)
+	var rows []StandingsRow
+	decodeJSON(t, http.StatusOK, w.Result(), &rows)
+	if len(rows) != 3 || rows[0] != (StandingsRow{Rank: 1, Team: "USA", Played: 1, Wins: 1, Points: 3}) {
+		t.Errorf("POST /match returned %+v", rows)
+	}
+
+	serve(h, http.MethodPost, "/match", `{"team1":"Mexico","score1":1,"team2":"Canada","score2":1}`)
+	w = serve(h, http.MethodGet, "/standings", "")
+	rows = nil
+	decodeJSON(t, http.StatusOK, w.Result(), &rows)
+	want := []StandingsRow{
+		{Rank: 1, Team: "USA", Played: 1, Wins: 1, Points: 3},
+		{Rank: 2, Team: "Canada", Played: 2, Draws: 1, Losses: 1, Points: 1},
+		{Rank: 3, Team: "Mexico", Played: 1, Draws: 1, Points: 1},
+	}
+	if !reflect.DeepEqual(rows, want) {
+		t.Errorf("GET /standings = %+v, want %+v", rows, want)
+	}
+
+	// The JSON field names are part of the API
+	var raw []map[string]any
+	decodeJSON(t, http.StatusOK, serve(h, http.MethodGet, "/standings", "").Result(), &raw)
+	var keys []string
+	for k := range raw[0] {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	if want := []string{"draws", "losses", "played", "points", "rank", "team", "wins"}; !slices.Equal(keys, want) {
+		t.Errorf("standings row fields = %v, want %v", keys, want)
+	}
+}
+
+func TestLeagueHandlerTeams(t *testing.T) {
+	var names []string
+	decodeJSON(t, http.StatusOK, serve(newTestHandler(), http.MethodGet, "/teams", "").Result(), &names)
+	if want := []string{"Canada", "Mexico", "USA"}; !slices.Equal(names, want) {
+		t.Errorf("GET /teams = %v, want %v", names, want)
+	}
+}
+
+func TestLeagueHandlerErrors(t *testing.T) {
+	tests := []struct {
+		name, method, path, body string
+		status                   int
+		wantErr                  string
+	}{
+		{"unknown team", "POST", "/match", `{"team1":"USA","score1":1,"team2":"Brazil","score2":0}`, 400, "invalid team2 Brazil: unknown team"},
+		{"same team", "POST", "/match", `{"team1":"USA","score1":1,"team2":"USA","score2":0}`, 400, "invalid team2 USA: a team can't play itself"},
+		{"negative score", "POST", "/match", `{"team1":"USA","score1":-1,"team2":"Canada","score2":0}`, 400, "invalid score1 -1: score can't be negative"},
+		{"malformed JSON", "POST", "/match", `{"team1":`, 400, "unexpected EOF"},
+		{"wrong type", "POST", "/match", `{"team1":"USA","score1":"two"}`, 400, ""},
+		{"unknown field", "POST", "/match", `{"team1":"USA","goals":3}`, 400, `json: unknown field "goals"`},
+		{"empty body", "POST", "/match", ``, 400, "EOF"},
+		{"GET /match", "GET", "/match", ``, 405, "method not allowed"},
+		{"POST /standings", "POST", "/standings", `{}`, 405, "method not allowed"},
+		{"PUT /teams", "PUT", "/teams", `{}`, 405, "method not allowed"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := newTestHandler()
+			w := serve(h, tt.method, tt.path, tt.body)
+			var body struct {
+				Error string `json:"error"`
+			}
+			decodeJSON(t, tt.status, w.Result(), &body)
+			if body.Error == "" || (tt.wantErr != "" && body.Error != tt.wantErr) {
+				t.Errorf("error %q, want %q", body.Error, tt.wantErr)
+			}
+			// A rejected result changes nothing
+			var rows []StandingsRow
+			decodeJSON(t, http.StatusOK, serve(h, http.MethodGet, "/standings", "").Result(), &rows)
+			for _, row := range rows {
+				if row.Played != 0 {
+					t.Errorf("after the error, %s has played %d", row.Team, row.Played)
+				}
+			}
+		})
+	}
+	w := serve(newTestHandler(), http.MethodGet, "/match", "")
+	if allow := w.Header().Get("Allow"); allow != "POST" {
+		t.Errorf("GET /match: Allow %q, want POST", allow)
+	}
+}
+
+// TestLeagueHandlerServer runs the handler on a real server, so requests go
+// through net/http's client and server.
+func TestLeagueHandlerServer(t *testing.T) {
+	srv := httptest.NewServer(newTestHandler())
+	defer srv.Close()
+
+	resp, err := http.Post(srv.URL+"/match", "application/json", strings.NewReader(`{"team1":"Canada","score1":0,"team2":"Mexico","score2":3}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	var rows []StandingsRow
+	decodeJSON(t, http.StatusOK, resp, &rows)
+	if rows[0].Team != "Mexico" || rows[0].Points != 3 {
+		t.Errorf("POST /match returned %+v", rows)
+	}
+
+	resp, err = http.Get(srv.URL + "/standings")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	var got []StandingsRow
+	decodeJSON(t, http.StatusOK, resp, &got)
+	if !reflect.DeepEqual(got, rows) {
+		t.Errorf("GET /standings = %+v, want %+v", got, rows)
+	}
+
+	resp, err = http.Post(srv.URL+"/match", "application/json", strings.NewReader(strings.Repeat(" ", maxRequestBody+1)+"{}"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	var body map[string]string
+	decodeJSON(t, http.StatusBadRequest, resp, &body)
+}
+
+// do sends a request to srv and decodes the JSON response into v, checking
+// the status. v can be nil for a response with no body.
+func do(t *testing.T, srv *httptest.Server, method, path, body string, status int, v any) {
+	t.Helper()
+	req, err := http.NewRequest(method, srv.URL+path, strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := srv.Client().Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if v == nil {
+		if resp.StatusCode != status {
+			t.Fatalf("%s %s: status %d, want %d", method, path, resp.StatusCode, status)
+		}
+		return
+	}
+	decodeJSON(t, status, resp, v)
+}
+
+// TestTeamLifecycle adds a team, plays it, looks it up and removes it again,
+// all through a real server.
+func TestTeamLifecycle(t *testing.T) {
+	srv := httptest.NewServer(newTestHandler())
+	defer srv.Close()
+
+	var created teamResponse
+	do(t, srv, "POST", "/teams", `{"name":"Costa Rica","players":["Navas","Campbell"],"group":"A"}`, http.StatusCreated, &created)
+	want := teamResponse{
+		StandingsRow: StandingsRow{Rank: 2, Team: "Costa Rica"},
+		Group:        "A",
+		Players:      []string{"Navas", "Campbell"},
+	}
+	if !reflect.DeepEqual(created, want) {
+		t.Errorf("POST /teams = %+v, want %+v", created, want)
+	}
+	var names []string
+	do(t, srv, "GET", "/teams", "", http.StatusOK, &names)
+	if want := []string{"Canada", "Costa Rica", "Mexico", "USA"}; !slices.Equal(names, want) {
+		t.Errorf("GET /teams = %v, want %v", names, want)
+	}
+
+	var rows []StandingsRow
+	do(t, srv, "POST", "/match", `{"team1":"Costa Rica","score1":2,"team2":"USA","score2":0}`, http.StatusOK, &rows)
+	do(t, srv, "POST", "/match", `{"team1":"Canada","score1":1,"team2":"Costa Rica","score2":1}`, http.StatusOK, &rows)
+
+	var got teamResponse
+	do(t, srv, "GET", "/teams/Costa%20Rica", "", http.StatusOK, &got)
+	want.StandingsRow = StandingsRow{Rank: 1, Team: "Costa Rica", Played: 2, Wins: 1, Draws: 1, Points: 4}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("GET /teams/Costa%%20Rica = %+v, want %+v", got, want)
+	}
+	var players []string
+	do(t, srv, "GET", "/teams/Costa%20Rica/players", "", http.StatusOK, &players)
+	if !slices.Equal(players, want.Players) {
+		t.Errorf("GET /teams/Costa%%20Rica/players = %v, want %v", players, want.Players)
+	}
+
+	do(t, srv, "DELETE", "/teams/Costa%20Rica", "", http.StatusNoContent, nil)
+	var body map[string]string
+	do(t, srv, "GET", "/teams/Costa%20Rica", "", http.StatusNotFound, &body)
+	if body["error"] != "unknown team: Costa Rica" {
+		t.Errorf("GET a deleted team: error %q", body["error"])
+	}
+	// Its matches go with it
+	do(t, srv, "GET", "/standings", "", http.StatusOK, &rows)
+	for _, row := range rows {
+		if row.Team == "Costa Rica" || row.Played != 0 || row.Points != 0 {
+			t.Errorf("after DELETE, standings have %+v", row)
+		}
+	}
+	// and the name is free again
+	do(t, srv, "POST", "/teams", `{"name":"Costa Rica"}`, http.StatusCreated, &created)
+	if created.Players == nil || len(created.Players) != 0 {
+		t.Errorf("POST /teams without players returned players %#v, want []", created.Players)
+	}
+}
+
+func TestTeamHandlerErrors(t *testing.T) {
+	tests := []struct {
+		name, method, path, body string
+		status                   int
+		wantErr                  string
+	}{
+		{"duplicate", "POST", "/teams", `{"name":"USA"}`, 409, "team already exists: USA"},
+		{"empty name", "POST", "/teams", `{"name":"  "}`, 400, "team name can't be empty"},
+		{"long name", "POST", "/teams", `{"name":"` + strings.Repeat("a", maxNameLength+1) + `"}`, 400, "team name is longer than 100 bytes"},
+		{"control character", "POST", "/teams", `{"name":"US\u0000A"}`, 400, `team name "US\x00A" has invalid characters`},
+		{"empty player", "POST", "/teams", `{"name":"Brazil","players":[""]}`, 400, "player name can't be empty"},
+		{"unknown field", "POST", "/teams", `{"name":"Brazil","coach":"Tite"}`, 400, `json: unknown field "coach"`},
+		{"get unknown", "GET", "/teams/Brazil", ``, 404, "unknown team: Brazil"},
+		{"players of unknown", "GET", "/teams/Brazil/players", ``, 404, "unknown team: Brazil"},
+		{"delete unknown", "DELETE", "/teams/Brazil", ``, 404, "unknown team: Brazil"},
+		{"unknown subpath", "GET", "/teams/USA/coach", ``, 404, "not found"},
+		{"too deep", "GET", "/teams/USA/players/1", ``, 404, "not found"},
+		{"no name", "GET", "/teams/", ``, 404, "not found"},
+		{"delete players", "DELETE", "/teams/USA/players", ``, 405, "method not allowed"},
+		{"post to a team", "POST", "/teams/USA", `{}`, 405, "method not allowed"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := newTestHandler()
+			var body map[string]string
+			decodeJSON(t, tt.status, serve(h, tt.method, tt.path, tt.body).Result(), &body)
+			if body["error"] != tt.wantErr {
+				t.Errorf("error %q, want %q", body["error"], tt.wantErr)
+			}
+			var names []string
+			decodeJSON(t, http.StatusOK, serve(h, http.MethodGet, "/teams", "").Result(), &names)
+			if want := []string{"Canada", "Mexico", "USA"}; !slices.Equal(names, want) {
+				t.Errorf("after the error, GET /teams = %v, want %v", names, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"text/tabwriter"
+)
+
+// Record is a team's results against a single opponent.
+type Record struct {
+	Wins   int
+	Losses int
+	Draws  int
+}
+
+func (r Record) String() string {
+	return fmt.Sprintf("%d-%d-%d", r.Wins, r.Losses, r.Draws)
+}
+
+// HeadToHead returns every team's record against every other team, so
+// HeadToHead()["USA"]["Canada"] is how USA did against Canada. Teams that
+// haven't played each other have a zero Record.
+func (l League) HeadToHead() map[string]map[string]Record {
+	h2h := make(map[string]map[string]Record, len(l.Teams))
+	for a := range l.Teams {
+		h2h[a] = make(map[string]Record, len(l.Teams)-1)
+		for b := range l.Teams {
+			if a != b {
+				h2h[a][b] = Record{}
+			}
+		}
+	}
+	for _, m := range l.history {
+		r1, ok1 := h2h[m.Team1][m.Team2]
+		r2, ok2 := h2h[m.Team2][m.Team1]
+		// Skip matches involving teams that have since left the league
+		if !ok1 || !ok2 {
+			continue
+		}
+		switch {
+		case m.Score1 > m.Score2:
+			r1.Wins++
+			r2.Losses++
+		case m.Score1 < m.Score2:
+			r1.Losses++
+			r2.Wins++
+		default:
+			r1.Draws++
+			r2.Draws++
+		}
+		h2h[m.Team1][m.Team2] = r1
+		h2h[m.Team2][m.Team1] = r2
+	}
+	return h2h
+}
+
+// WriteHeadToHeadTable writes the head to head records as a grid, with rows
+// and columns in ranking order. Each cell is the row team's wins, losses,
+// and draws against the column team.
+func (l League) WriteHeadToHeadTable(w io.Writer) error {
+	h2h := l.HeadToHead()
+	ranking := l.Ranking()
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	for _, name := range ranking {
+		fmt.Fprintf(tw, "\t%s", name)
+	}
+	fmt.Fprintln(tw)
+	for _, row := range ranking {
+		fmt.Fprint(tw, row)
+		for _, col := range ranking {
+			if row == col {
+				fmt.Fprint(tw, "\t-")
+				continue
+			}
+			fmt.Fprintf(tw, "\t%s", h2h[row][col])
+		}
+		fmt.Fprintln(tw)
+	}
+	return tw.Flush()
+}
@@ -0,0 +1,103 @@
+package main
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+// headToHeadLeague has A and B meeting three times, a draw between A and C,
+// C beating B, and D not playing at all.
+func headToHeadLeague(t *testing.T) *League {
+	t.Helper()
+	l := &League{Teams: map[string]Team{}}
+	for _, name := range []string{"A", "B", "C", "D"} {
+		l.Teams[name] = Team{Name: name}
+	}
+	for _, m := range []Match{
+		{Team1: "A", Score1: 2, Team2: "B", Score2: 0},
+		{Team1: "B", Score1: 1, Team2: "A", Score2: 3},
+		{Team1: "B", Score1: 2, Team2: "A", Score2: 1},
+		{Team1: "A", Score1: 1, Team2: "C", Score2: 1},
+		{Team1: "C", Score1: 4, Team2: "B", Score2: 0},
+	} {
+		if err := l.MatchResult(m.Team1, m.Score1, m.Team2, m.Score2); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return l
+}
+
+func TestHeadToHead(t *testing.T) {
+	h2h := headToHeadLeague(t).HeadToHead()
+	want := map[[2]string]Record{
+		{"A", "B"}: {Wins: 2, Losses: 1},
+		{"A", "C"}: {Draws: 1},
+		{"C", "B"}: {Wins: 1},
+		{"A", "D"}: {},
+		{"B", "D"}: {},
+	}
+	for pair, rec := range want {
+		if got := h2h[pair[0]][pair[1]]; got != rec {
+			t.Errorf("%s against %s = %v, want %v", pair[0], pair[1], got, rec)
+		}
+	}
+
+	// Every pair's records mirror each other, and no team has a record
+	// against itself
+	for a, row := range h2h {
+		if _, ok := row[a]; ok {
+			t.Errorf("%s has a record against itself", a)
+		}
+		if len(row) != 3 {
+			t.Errorf("%s has records against %d teams, want 3", a, len(row))
+		}
+		for b, rec := range row {
+			back := h2h[b][a]
+			if rec.Wins != back.Losses || rec.Losses != back.Wins || rec.Draws != back.Draws {
+				t.Errorf("%s against %s is %v, but %s against %s is %v", a, b, rec, b, a, back)
+			}
+		}
+	}
+}
+
+func TestHeadToHeadRemovedTeam(t *testing.T) {
+	l := headToHeadLeague(t)
+	if err := l.RemoveTeam("C"); err != nil {
+		t.Fatal(err)
+	}
+	h2h := l.HeadToHead()
+	if _, ok := h2h["C"]; ok {
+		t.Error("removed team C still has records")
+	}
+	if _, ok := h2h["A"]["C"]; ok {
+		t.Error("A still has a record against removed team C")
+	}
+	if got := h2h["A"]["B"]; got != (Record{Wins: 2, Losses: 1}) {
+		t.Errorf("A against B = %v, want 2-1-0", got)
+	}
+}
+
+func TestWriteHeadToHeadTable(t *testing.T) {
+	var b strings.Builder
+	if err := headToHeadLeague(t).WriteHeadToHeadTable(&b); err != nil {
+		t.Fatal(err)
+	}
+	// Rows and columns are in ranking order: A has two wins, B and C one
+	want := [][]string{
+		{"A", "B", "C", "D"},
+		{"A", "-", "2-1-0", "0-0-1", "0-0-0"},
+		{"B", "1-2-0", "-", "0-1-0", "0-0-0"},
+		{"C", "0-0-1", "1-0-0", "-", "0-0-0"},
+		{"D", "0-0-0", "0-0-0", "0-0-0", "-"},
+	}
+	lines := strings.Split(strings.TrimSuffix(b.String(), "\n"), "\n")
+	if len(lines) != len(want) {
+		t.Fatalf("table has %d lines, want %d:\n%s", len(lines), len(want), b.String())
+	}
+	for i, line := range lines {
+		if got := strings.Fields(line); !slices.Equal(got, want[i]) {
+			t.Errorf("line %d = %q, want %q", i+1, got, want[i])
+		}
+	}
+}
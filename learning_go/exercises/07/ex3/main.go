@@ -1,92 +1,486 @@
 package main
 
 import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
 	"io"
+	"math/rand"
+	"net/http"
+	"net/http/httptest"
 	"os"
-	"sort"
+	"strings"
+	"time"
 )
 
 type Team struct {
 	Name    string
 	Players []string
+	// Group is the conference or group the team plays in. Teams without one
+	// are listed under DefaultGroup in GroupStandings.
+	Group string
 }
 
 type League struct {
 	Teams map[string]Team
 	Wins  map[string]int
 	Name  string
+	// ForfeitScore is the score awarded to the opponent of a team that
+	// forfeits, the forfeiting team is recorded as scoring zero. Defaults to
+	// 20 when unset, and Forfeit fails if it's negative.
+	ForfeitScore int
+	// ForfeitPenalty is the number of wins taken away from a team that
+	// forfeits.
+	ForfeitPenalty int
+	// Fixtures is the league's schedule, see GenerateFixtures.
+	Fixtures []Fixture
+	// DoubleRoundRobin makes GenerateFixtures schedule every pair of teams
+	// twice, once at each team's home.
+	DoubleRoundRobin bool
+	// SimulationWorkers is the number of goroutines FinishProbabilities
+	// uses. Defaults to GOMAXPROCS when unset.
+	SimulationWorkers int
+	// MaxScore is the highest score MatchResult accepts. Defaults to 200 when
+	// unset.
+	MaxScore int
+	// Tiebreakers decides the order of the ranking, each one is tried in
+	// turn until one separates two teams. Defaults to ByWins then ByName.
+	// Funcs can't be encoded, so the chain isn't saved with the league.
+	Tiebreakers []Tiebreaker `json:"-"`
+	// EventBus, if set, gets a MatchEvent for every result MatchResult or
+	// Forfeit records. Simulations don't publish to it.
+	EventBus *Bus[MatchEvent] `json:"-"`
+	// Scoring decides how many points Standings and ByPoints give a team.
+	// Defaults to StandardScoring when unset. It isn't saved with the league.
+	Scoring ScoringSystem `json:"-"`
+	history []Match
+	nextID  int
+	rank    *rankCache
 }
 
-func (l *League) MatchResult(team1 string, score1 int, team2 string, score2 int) {
-	if _, ok := l.Teams[team1]; !ok {
-		return
+// Match is a single game recorded in the league's history. IDs are assigned
+// in the order matches are recorded, starting at 1.
+type Match struct {
+	ID      int
+	Team1   string
+	Score1  int
+	Team2   string
+	Score2  int
+	Forfeit bool
+}
+
+const defaultForfeitScore = 20
+
+// MatchResult records a match, updates the winner's wins and publishes a
+// MatchEvent if the league has an EventBus. Invalid input is rejected with a
+// *ValidationError and nothing is recorded.
+func (l *League) MatchResult(team1 string, score1 int, team2 string, score2 int) error {
+	if err := l.validateMatch(team1, score1, team2, score2); err != nil {
+		return err
 	}
-	if _, ok := l.Teams[team2]; !ok {
-		return
+	m := l.recordMatch(Match{Team1: team1, Score1: score1, Team2: team2, Score2: score2})
+	l.applyResult(m, 1)
+	l.publish(m)
+	return nil
+}
+
+// publish sends m to the EventBus, if there is one.
+func (l *League) publish(m Match) {
+	if l.EventBus != nil {
+		l.EventBus.Publish(MatchEvent{Team1: m.Team1, Team2: m.Team2, Score1: m.Score1, Score2: m.Score2, Forfeit: m.Forfeit, Timestamp: time.Now()})
 	}
-	if score1 == score2 {
+}
+
+// recordMatch gives m the next match ID and adds it to the history.
+func (l *League) recordMatch(m Match) Match {
+	l.nextID++
+	m.ID = l.nextID
+	l.history = append(l.history, m)
+	return m
+}
+
+// applyResult adds delta wins to the winner of m, use -1 to undo a result.
+// Ties don't change anything.
+func (l *League) applyResult(m Match, delta int) {
+	winner := m.Team1
+	switch {
+	case m.Score1 == m.Score2:
 		return
+	case m.Score2 > m.Score1:
+		winner = m.Team2
 	}
-	if score1 > score2 {
-		l.Wins[team1]++
+	l.addWins(winner, delta)
+	l.adjustRanking(winner)
+}
+
+// addWins adds delta to team's wins, which may be negative.
+func (l *League) addWins(team string, delta int) {
+	if l.Wins == nil {
+		l.Wins = map[string]int{}
+	}
+	l.Wins[team] += delta
+}
+
+// MatchByID returns the match with the given ID.
+func (l League) MatchByID(id int) (Match, error) {
+	for _, m := range l.history {
+		if m.ID == id {
+			return m, nil
+		}
+	}
+	return Match{}, fmt.Errorf("no match with id %d", id)
+}
+
+// CorrectMatch replaces the scores of an already recorded match, moving the
+// win to the other team if the winner changed. Forfeits can't be corrected.
+func (l *League) CorrectMatch(id int, score1, score2 int) error {
+	for i, m := range l.history {
+		if m.ID != id {
+			continue
+		}
+		if m.Forfeit {
+			return fmt.Errorf("match %d is a forfeit and can't be corrected", id)
+		}
+		if err := l.validateMatch(m.Team1, score1, m.Team2, score2); err != nil {
+			return err
+		}
+		l.applyResult(m, -1)
+		m.Score1, m.Score2 = score1, score2
+		l.history[i] = m
+		l.applyResult(m, 1)
+		return nil
+	}
+	return fmt.Errorf("no match with id %d", id)
+}
+
+// Forfeit records a walkover win for opponent and applies the league's
+// forfeit penalty to forfeitingTeam. If the league has fixtures, the result
+// is for the first unplayed fixture between the two teams, with the teams in
+// that fixture's home and away order, and it's an error if there isn't one
+// left. Without fixtures, it's an error if the two teams have already
+// played each other. Either team being unknown is an error too. The result
+// is published to the EventBus like any other.
+func (l *League) Forfeit(forfeitingTeam, opponent string) error {
+	if _, ok := l.Teams[forfeitingTeam]; !ok {
+		return fmt.Errorf("unknown team: %s", forfeitingTeam)
+	}
+	if _, ok := l.Teams[opponent]; !ok {
+		return fmt.Errorf("unknown opponent: %s", opponent)
+	}
+	if forfeitingTeam == opponent {
+		return errors.New("a team can't forfeit against itself")
+	}
+	score := l.ForfeitScore
+	switch {
+	case score < 0:
+		return fmt.Errorf("forfeit score can't be negative, got %d", score)
+	case score == 0:
+		score = defaultForfeitScore
+	}
+	m := Match{Team1: forfeitingTeam, Score1: 0, Team2: opponent, Score2: score, Forfeit: true}
+	if len(l.Fixtures) > 0 {
+		f, ok := l.unplayedFixture(forfeitingTeam, opponent)
+		if !ok {
+			return fmt.Errorf("%s and %s have no unplayed fixture", forfeitingTeam, opponent)
+		}
+		if f.Home == opponent {
+			m = Match{Team1: opponent, Score1: score, Team2: forfeitingTeam, Score2: 0, Forfeit: true}
+		}
 	} else {
-		l.Wins[team2]++
+		for _, played := range l.history {
+			if (played.Team1 == forfeitingTeam && played.Team2 == opponent) || (played.Team1 == opponent && played.Team2 == forfeitingTeam) {
+				return fmt.Errorf("%s and %s have already played", forfeitingTeam, opponent)
+			}
+		}
 	}
+	m = l.recordMatch(m)
+	l.applyResult(m, 1)
+	l.addWins(forfeitingTeam, -l.ForfeitPenalty)
+	l.adjustRanking(forfeitingTeam)
+	l.publish(m)
+	return nil
+}
+
+// Matches returns a copy of every match recorded so far, in the order they
+// were played.
+func (l League) Matches() []Match {
+	out := make([]Match, len(l.history))
+	copy(out, l.history)
+	return out
 }
 
+// Ranking returns the team names ordered by the Tiebreakers chain, or by wins
+// and then name if no chain is set. With the default order, once a result
+// has been recorded the order is served from a cache that MatchResult keeps
+// up to date, see rankCache.
 func (l League) Ranking() []string {
-	names := make([]string, 0, len(l.Teams))
-	for k := range l.Teams {
-		names = append(names, k)
+	if l.rank == nil || len(l.Tiebreakers) > 0 {
+		return l.sortedRanking()
 	}
-	sort.Slice(names, func(i, j int) bool {
-		return l.Wins[names[i]] > l.Wins[names[j]]
-	})
-	return names
+	if !l.rank.valid(l) {
+		l.rank.rebuild(l)
+	}
+	out := make([]string, len(l.rank.order))
+	copy(out, l.rank.order)
+	return out
+}
+
+// WinCount returns the number of wins recorded for the named team.
+func (l League) WinCount(name string) int {
+	return l.Wins[name]
 }
 
 type Ranker interface {
 	Ranking() []string
 }
 
+// StatRanker is a Ranker that can also report each team's wins. The method
+// can't be called Wins because League already has a field with that name.
+type StatRanker interface {
+	Ranker
+	WinCount(name string) int
+}
+
+// RankPrinter writes one team per line. If r is a StatRanker each line also
+// includes the team's position and number of wins, e.g. "1. Serbia (3 wins)".
 func RankPrinter(r Ranker, w io.Writer) {
-	results := r.Ranking()
-	for _, v := range results {
-		io.WriteString(w, v)
-		w.Write([]byte("\n"))
+	writeRanking(w, r.Ranking(), r)
+}
+
+func writeRanking(w io.Writer, results []string, r any) {
+	sr, hasStats := r.(StatRanker)
+	for i, v := range results {
+		if !hasStats {
+			io.WriteString(w, v)
+			w.Write([]byte("\n"))
+			continue
+		}
+		wins := sr.WinCount(v)
+		unit := "wins"
+		if wins == 1 {
+			unit = "win"
+		}
+		fmt.Fprintf(w, "%d. %s (%d %s)\n", i+1, v, wins, unit)
 	}
 }
 
 func main() {
+	matchDB := flag.String("matchdb", "", "print the standings for the matches in this SQLite `file` instead of running the demo")
+	flag.Parse()
+	if *matchDB != "" {
+		db, err := LoadFromSQLite(*matchDB)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		RankPrinter(db, os.Stdout)
+		return
+	}
+
 	l := League{
 		Name: "Big League",
 		Teams: map[string]Team{
 			"USA": {
 				Name:    "USA",
 				Players: []string{"Player1", "Player2", "Player3", "Player4", "Player5"},
+				Group:   "North America",
 			},
 			"Canada": {
 				Name:    "Canada",
 				Players: []string{"Player1", "Player2", "Player3", "Player4", "Player5"},
+				Group:   "North America",
 			},
 			"Serbia": {
 				Name:    "Serbia",
 				Players: []string{"Player1", "Player2", "Player3", "Player4", "Player5"},
+				Group:   "Europe",
 			},
 			"Germany": {
 				Name:    "Germany",
 				Players: []string{"Player1", "Player2", "Player3", "Player4", "Player5"},
+				Group:   "Europe",
 			},
 		},
-		Wins: map[string]int{},
-	}
-	l.MatchResult("USA", 50, "Canada", 70)
-	l.MatchResult("Serbia", 85, "Germany", 80)
-	l.MatchResult("USA", 60, "Serbia", 55)
-	l.MatchResult("Canada", 100, "Germany", 110)
-	l.MatchResult("USA", 65, "Germany", 70)
-	l.MatchResult("Canada", 95, "Serbia", 80)
-	l.MatchResult("Germany", 100, "USA", 98)
-	l.MatchResult("Serbia", 70, "Canada", 68)
+		DoubleRoundRobin: true,
+	}
+	l.GenerateFixtures()
+	l.EventBus = NewBus[MatchEvent](16)
+	events := l.EventBus.Subscribe()
+	results := []Match{
+		{Team1: "USA", Score1: 50, Team2: "Canada", Score2: 70},
+		{Team1: "Serbia", Score1: 85, Team2: "Germany", Score2: 80},
+		{Team1: "USA", Score1: 60, Team2: "Serbia", Score2: 55},
+		{Team1: "Canada", Score1: 100, Team2: "Germany", Score2: 110},
+		{Team1: "USA", Score1: 65, Team2: "Germany", Score2: 70},
+		{Team1: "Canada", Score1: 95, Team2: "Serbia", Score2: 80},
+		{Team1: "Germany", Score1: 100, Team2: "USA", Score2: 98},
+		{Team1: "Serbia", Score1: 70, Team2: "Canada", Score2: 68},
+		{Team1: "USA", Score1: -5, Team2: "Canada", Score2: 70},
+	}
+	for _, m := range results {
+		err := l.MatchResult(m.Team1, m.Score1, m.Team2, m.Score2)
+		var ve *ValidationError
+		if errors.As(err, &ve) {
+			fmt.Println("rejected", ve.Field+":", err)
+		}
+	}
+	l.EventBus.Unsubscribe(events)
+	published := 0
+	for range events {
+		published++
+	}
+	fmt.Println("published", published, "results, dropped", l.EventBus.Dropped())
+
+	before := l.Snapshot()
+	if err := l.Forfeit("Germany", "Serbia"); err != nil {
+		fmt.Println(err)
+	}
+	// The scorekeeper swapped the scores of the first game
+	if err := l.CorrectMatch(1, 70, 50); err != nil {
+		fmt.Println(err)
+	}
+	for _, m := range DiffStandings(before, l.Snapshot()) {
+		dir, places := "up", m.Delta
+		if places < 0 {
+			dir, places = "down", -places
+		}
+		unit := "places"
+		if places == 1 {
+			unit = "place"
+		}
+		if places != 0 {
+			fmt.Printf("%s moved %s %d %s\n", m.Team, dir, places, unit)
+		}
+	}
+
+	var saved bytes.Buffer
+	if err := l.Save(&saved); err != nil {
+		fmt.Println(err)
+		return
+	}
+	loaded, err := Load(&saved)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	m, err := loaded.MatchByID(1)
+	fmt.Println(m, err)
+	RankPrinter(l, os.Stdout)
+	fmt.Println()
+	RankPrinterGrouped(l, os.Stdout)
+	fmt.Println()
+	// Rank on points, and then on who won the games between tied teams
+	l.Tiebreakers = []Tiebreaker{ByPoints, ByHeadToHead, ByGoalDifference, ByName}
 	RankPrinter(l, os.Stdout)
+	l.Tiebreakers = nil
+	fmt.Println()
+	if err := l.WriteHeadToHeadTable(os.Stdout); err != nil {
+		fmt.Println(err)
+	}
+	fmt.Println()
+	if err := l.WriteStandingsCSV(os.Stdout); err != nil {
+		fmt.Println(err)
+	}
+
+	remaining, err := l.RemainingFixtures("USA")
+	fmt.Println(remaining, err)
+	if next, ok := l.NextFixture("USA"); ok {
+		fmt.Printf("USA next: round %d, %s v %s\n", next.Round, next.Home, next.Away)
+	}
+	_, err = l.RemainingFixtures("Brazil")
+	fmt.Println(err)
+
+	fmt.Println("Projected:")
+	RankPrinter(l.SimulateRemaining(rand.NewSource(1)), os.Stdout)
+
+	probs := l.FinishProbabilities(10_000, rand.NewSource(1))
+	for _, name := range l.Ranking() {
+		fmt.Printf("%s %.2f\n", name, probs[name])
+	}
+
+	if err := httpDemo(&l); err != nil {
+		fmt.Println(err)
+	}
+	if err := pmemDemo(l.Teams); err != nil {
+		fmt.Println(err)
+	}
+
+	_, err = NewLeagueFromJSON(strings.NewReader(`{"name": "Bad League", "teams": [{"name": "USA"}, {"name": "USA"}]}`))
+	fmt.Println(err)
+}
+
+// pmemDemo records matches through a PmemLeague, cuts the file off part way
+// through the last one as if the machine died while writing it, and then
+// recovers the matches before it.
+func pmemDemo(teams map[string]Team) error {
+	dir, err := os.MkdirTemp("", "pmem")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(dir)
+	p, err := CreatePmemLeague(&League{Teams: teams}, dir)
+	if err != nil {
+		return err
+	}
+	for _, m := range []Match{
+		{Team1: "USA", Score1: 50, Team2: "Canada", Score2: 70},
+		{Team1: "Serbia", Score1: 85, Team2: "Germany", Score2: 80},
+		{Team1: "USA", Score1: 60, Team2: "Serbia", Score2: 55},
+	} {
+		if err := p.MatchResult(m.Team1, m.Score1, m.Team2, m.Score2); err != nil {
+			return err
+		}
+	}
+	path, torn := p.Path(), p.end-5
+	if err := p.Close(); err != nil {
+		return err
+	}
+	if err := os.Truncate(path, int64(torn)); err != nil {
+		return err
+	}
+	p, err = OpenPmemLeague(path, &League{Teams: teams})
+	if err != nil {
+		return err
+	}
+	defer p.Close()
+	fmt.Println("recovered", p.Matches())
+	return nil
+}
+
+// httpDemo runs LeagueHandler on a local test server and takes a team
+// through its whole life: added, playing a match and removed again.
+func httpDemo(l *League) error {
+	srv := httptest.NewServer(LeagueHandler(NewSyncLeague(l)))
+	defer srv.Close()
+	for _, req := range []struct{ method, path, body string }{
+		{"GET", "/teams", ""},
+		{"POST", "/teams", `{"name":"Brazil","players":["Player1","Player2"],"group":"South America"}`},
+		{"POST", "/teams", `{"name":"Brazil"}`},
+		{"POST", "/match", `{"team1":"USA","score1":3,"team2":"Serbia","score2":1}`},
+		{"POST", "/match", `{"team1":"Brazil","score1":3,"team2":"Peru","score2":1}`},
+		{"POST", "/match", `{"team1":"Brazil","score1":90,"team2":"USA","score2":80}`},
+		{"GET", "/teams/Brazil", ""},
+		{"GET", "/teams/Brazil/players", ""},
+		{"GET", "/standings", ""},
+		{"DELETE", "/teams/Brazil", ""},
+		{"GET", "/teams/Brazil", ""},
+		{"GET", "/standings", ""},
+	} {
+		r, err := http.NewRequest(req.method, srv.URL+req.path, strings.NewReader(req.body))
+		if err != nil {
+			return err
+		}
+		resp, err := http.DefaultClient.Do(r)
+		if err != nil {
+			return err
+		}
+		body, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return err
+		}
+		fmt.Printf("%s %s: %s %s\n", req.method, req.path, resp.Status, bytes.TrimSpace(body))
+	}
+	return nil
 }
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+// plainRanker is a Ranker that can't report wins.
+type plainRanker []string
+
+func (r plainRanker) Ranking() []string { return r }
+
+// statRanker is a StatRanker with made up win counts.
+type statRanker struct {
+	plainRanker
+	wins map[string]int
+}
+
+func (r statRanker) WinCount(name string) int { return r.wins[name] }
+
+func TestRankPrinter(t *testing.T) {
+	tests := []struct {
+		name string
+		r    Ranker
+		want string
+	}{
+		{"plain Ranker", plainRanker{"Serbia", "USA", "Canada"}, "Serbia\nUSA\nCanada\n"},
+		{
+			"StatRanker",
+			statRanker{plainRanker{"Serbia", "USA", "Canada"}, map[string]int{"Serbia": 3, "USA": 1}},
+			"1. Serbia (3 wins)\n2. USA (1 win)\n3. Canada (0 wins)\n",
+		},
+		{"empty", plainRanker{}, ""},
+	}
+	for _, tt := range tests {
+		var b strings.Builder
+		RankPrinter(tt.r, &b)
+		if got := b.String(); got != tt.want {
+			t.Errorf("%s: RankPrinter wrote %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+// TestRankPrinterLeague checks that a League is printed as a StatRanker.
+func TestRankPrinterLeague(t *testing.T) {
+	l := &League{Teams: map[string]Team{"A": {Name: "A"}, "B": {Name: "B"}}}
+	if err := l.MatchResult("A", 2, "B", 1); err != nil {
+		t.Fatal(err)
+	}
+	var b strings.Builder
+	RankPrinter(l, &b)
+	if want := "1. A (1 win)\n2. B (0 wins)\n"; b.String() != want {
+		t.Errorf("RankPrinter wrote %q, want %q", b.String(), want)
+	}
+}
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+)
+
+// leagueFields has the same fields as League but none of its methods, so it
+// can be encoded without calling League.MarshalJSON again.
+type leagueFields League
+
+// savedLeague is the on-disk form of a League. It adds the unexported match
+// history so match IDs survive a save and load.
+type savedLeague struct {
+	leagueFields
+	Matches     []Match
+	NextMatchID int
+}
+
+func (l League) MarshalJSON() ([]byte, error) {
+	return json.Marshal(savedLeague{
+		leagueFields: leagueFields(l),
+		Matches:      l.history,
+		NextMatchID:  l.nextID,
+	})
+}
+
+func (l *League) UnmarshalJSON(data []byte) error {
+	var s savedLeague
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	*l = League(s.leagueFields)
+	l.history = s.Matches
+	l.nextID = s.NextMatchID
+	l.invalidateRanking()
+	if l.Teams == nil {
+		l.Teams = map[string]Team{}
+	}
+	return nil
+}
+
+// Save writes the league, including its match history, as JSON.
+func (l League) Save(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(l)
+}
+
+// Load reads a league written by Save.
+func Load(r io.Reader) (*League, error) {
+	var l League
+	if err := json.NewDecoder(r).Decode(&l); err != nil {
+		return nil, err
+	}
+	return &l, nil
+}
+
+// SaveToFile saves the league to the named file, replacing it if it exists.
+func (l League) SaveToFile(path string) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := l.Save(f); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// LoadFromFile loads a league saved with SaveToFile.
+func LoadFromFile(path string) (*League, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return Load(f)
+}
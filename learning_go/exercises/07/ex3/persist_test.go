@@ -0,0 +1,79 @@
+package main
+
+import (
+	"bytes"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// TestSaveLoadMatchIDs saves and loads a league and checks every match ID
+// still finds the same match, and that new matches don't reuse an ID.
+func TestSaveLoadMatchIDs(t *testing.T) {
+	l := bigLeague(4)
+	results := [][2]string{{"Team 0", "Team 1"}, {"Team 2", "Team 3"}, {"Team 1", "Team 3"}, {"Team 0", "Team 2"}}
+	for i, r := range results {
+		if err := l.MatchResult(r[0], i+1, r[1], 0); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// Removing a team takes its matches with it, leaving gaps in the IDs
+	if err := l.RemoveTeam("Team 3"); err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	if err := l.Save(&buf); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := Load(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(loaded.Matches(), l.Matches()) {
+		t.Fatalf("loaded matches %+v, want %+v", loaded.Matches(), l.Matches())
+	}
+	used := map[int]bool{}
+	for _, want := range l.Matches() {
+		got, err := loaded.MatchByID(want.ID)
+		if err != nil || got != want {
+			t.Errorf("MatchByID(%d) = %+v, %v, want %+v", want.ID, got, err, want)
+		}
+		used[want.ID] = true
+	}
+	// IDs of removed matches stay unused too
+	for id := 1; id <= len(results); id++ {
+		used[id] = true
+	}
+
+	if err := loaded.MatchResult("Team 1", 1, "Team 2", 1); err != nil {
+		t.Fatal(err)
+	}
+	m := loaded.Matches()[len(loaded.Matches())-1]
+	if used[m.ID] {
+		t.Errorf("new match after Load got ID %d, which was already used", m.ID)
+	}
+	if got, err := loaded.MatchByID(m.ID); err != nil || got != m {
+		t.Errorf("MatchByID(%d) = %+v, %v, want %+v", m.ID, got, err, m)
+	}
+	checkRanking(t, loaded, "MatchResult after Load")
+}
+
+func TestSaveToFile(t *testing.T) {
+	l := bigLeague(3)
+	l.MatchResult("Team 0", 2, "Team 1", 1)
+	path := filepath.Join(t.TempDir(), "league.json")
+	if err := l.SaveToFile(path); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := LoadFromFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(loaded.Standings(), l.Standings()) {
+		t.Errorf("loaded standings %+v, want %+v", loaded.Standings(), l.Standings())
+	}
+	if _, err := LoadFromFile(filepath.Join(t.TempDir(), "missing.json")); err == nil {
+		t.Error("LoadFromFile of a missing file succeeded")
+	}
+}
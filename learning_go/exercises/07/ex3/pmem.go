@@ -0,0 +1,243 @@
+package main
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"os"
+)
+
+// This file simulates keeping the league in persistent memory. On pmem
+// hardware a store to mapped memory is durable as soon as it leaves the CPU
+// cache, with no write or fsync call. Here the file is mapped with mmap and
+// every change is flushed with msync(MS_SYNC), which gives the same
+// guarantee, only slower.
+//
+// The file starts with pmemMagic and then holds one record per match:
+// a 4 byte length, a 4 byte CRC-32 of the data and the match as JSON. The
+// rest of the file is zeros, and a zero length marks the end.
+
+const (
+	pmemMagic         = "LGPMEM01"
+	pmemInitialSize   = 64 << 10
+	pmemRecordHeader  = 8
+	pmemMaxRecordSize = 1 << 20
+)
+
+// PmemLeague is a League whose match results are saved to a memory mapped
+// file before they're applied, so every result MatchResult has accepted
+// survives a crash. Match results are the only change the file records, so
+// the league itself is kept private and League's other methods that change
+// it, such as Forfeit and CorrectMatch, aren't available.
+type PmemLeague struct {
+	league *League
+	f      *os.File
+	mem    []byte
+	// end is where the next record goes
+	end int
+}
+
+// CreatePmemLeague makes a new, empty match file in dir, or the default
+// temporary directory if dir is empty, and returns l backed by it. Matches
+// l already has aren't written to the file.
+func CreatePmemLeague(l *League, dir string) (*PmemLeague, error) {
+	f, err := os.CreateTemp(dir, "league-*.pmem")
+	if err != nil {
+		return nil, err
+	}
+	if err := f.Truncate(pmemInitialSize); err != nil {
+		f.Close()
+		os.Remove(f.Name())
+		return nil, err
+	}
+	mem, err := mmapFile(f, pmemInitialSize)
+	if err != nil {
+		f.Close()
+		os.Remove(f.Name())
+		return nil, err
+	}
+	p := &PmemLeague{league: l, f: f, mem: mem, end: len(pmemMagic)}
+	copy(mem, pmemMagic)
+	if err := p.sync(0, len(pmemMagic)); err != nil {
+		p.Close()
+		os.Remove(f.Name())
+		return nil, err
+	}
+	return p, nil
+}
+
+// OpenPmemLeague opens a match file made by CreatePmemLeague and replays its
+// matches onto l, which should have the league's teams but no matches. A
+// record that was only partly written when the process died, including one
+// cut short because the file was truncated, is dropped along with anything
+// after it.
+func OpenPmemLeague(path string, l *League) (*PmemLeague, error) {
+	f, err := os.OpenFile(path, os.O_RDWR, 0)
+	if err != nil {
+		return nil, err
+	}
+	fi, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	if fi.Size() < int64(len(pmemMagic)) {
+		f.Close()
+		return nil, fmt.Errorf("%s is not a league match file", path)
+	}
+	mem, err := mmapFile(f, int(fi.Size()))
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	p := &PmemLeague{league: l, f: f, mem: mem, end: len(pmemMagic)}
+	if string(mem[:len(pmemMagic)]) != pmemMagic {
+		p.Close()
+		return nil, fmt.Errorf("%s is not a league match file", path)
+	}
+
+	for {
+		data, ok := p.record(p.end)
+		if !ok {
+			break
+		}
+		var m Match
+		if err := json.Unmarshal(data, &m); err != nil {
+			p.Close()
+			return nil, fmt.Errorf("%s: match at offset %d: %w", path, p.end, err)
+		}
+		if err := l.MatchResult(m.Team1, m.Score1, m.Team2, m.Score2); err != nil {
+			p.Close()
+			return nil, fmt.Errorf("%s: replaying match at offset %d: %w", path, p.end, err)
+		}
+		p.end += pmemRecordHeader + len(data)
+	}
+	// Clear whatever is left of a torn record, so an older record further on
+	// can't be mistaken for one written after the next
+	clear(mem[p.end:])
+	if err := p.sync(p.end, len(mem)-p.end); err != nil {
+		p.Close()
+		return nil, err
+	}
+	return p, nil
+}
+
+// record returns the data of the record at off, and false if there's no
+// complete, undamaged record there.
+func (p *PmemLeague) record(off int) ([]byte, bool) {
+	if off+pmemRecordHeader > len(p.mem) {
+		return nil, false
+	}
+	n := int(binary.LittleEndian.Uint32(p.mem[off:]))
+	sum := binary.LittleEndian.Uint32(p.mem[off+4:])
+	start := off + pmemRecordHeader
+	if n == 0 || n > pmemMaxRecordSize || start+n > len(p.mem) {
+		return nil, false
+	}
+	data := p.mem[start : start+n]
+	if crc32.ChecksumIEEE(data) != sum {
+		return nil, false
+	}
+	return data, true
+}
+
+// MatchResult checks the result, saves it to the file and then records it
+// like League.MatchResult. If saving fails the league isn't changed.
+func (p *PmemLeague) MatchResult(team1 string, score1 int, team2 string, score2 int) error {
+	if err := p.league.validateMatch(team1, score1, team2, score2); err != nil {
+		return err
+	}
+	data, err := json.Marshal(Match{Team1: team1, Score1: score1, Team2: team2, Score2: score2})
+	if err != nil {
+		return err
+	}
+	if err := p.append(data); err != nil {
+		return fmt.Errorf("saving match: %w", err)
+	}
+	return p.league.MatchResult(team1, score1, team2, score2)
+}
+
+// Matches returns every match recorded so far, see League.Matches.
+func (p *PmemLeague) Matches() []Match {
+	return p.league.Matches()
+}
+
+// Ranking returns the team names in ranking order, see League.Ranking.
+func (p *PmemLeague) Ranking() []string {
+	return p.league.Ranking()
+}
+
+// Standings returns the league table, see League.Standings.
+func (p *PmemLeague) Standings() []StandingsRow {
+	return p.league.Standings()
+}
+
+// WinCount returns the number of wins recorded for the named team.
+func (p *PmemLeague) WinCount(name string) int {
+	return p.league.WinCount(name)
+}
+
+func (p *PmemLeague) append(data []byte) error {
+	if p.mem == nil {
+		return errors.New("match file is closed")
+	}
+	need := pmemRecordHeader + len(data)
+	if p.end+need > len(p.mem) {
+		if err := p.grow(p.end + need); err != nil {
+			return err
+		}
+	}
+	rec := p.mem[p.end : p.end+need]
+	binary.LittleEndian.PutUint32(rec, uint32(len(data)))
+	binary.LittleEndian.PutUint32(rec[4:], crc32.ChecksumIEEE(data))
+	copy(rec[pmemRecordHeader:], data)
+	if err := p.sync(p.end, need); err != nil {
+		return err
+	}
+	p.end += need
+	return nil
+}
+
+// grow makes the file and its mapping at least size bytes, doubling so
+// appends don't remap every time.
+func (p *PmemLeague) grow(size int) error {
+	newSize := max(2*len(p.mem), size)
+	if err := munmap(p.mem); err != nil {
+		return err
+	}
+	p.mem = nil
+	if err := p.f.Truncate(int64(newSize)); err != nil {
+		return err
+	}
+	mem, err := mmapFile(p.f, newSize)
+	if err != nil {
+		return err
+	}
+	p.mem = mem
+	return nil
+}
+
+// sync flushes n bytes at off to the file. msync needs a page aligned
+// start, so the range is widened to the start of its page.
+func (p *PmemLeague) sync(off, n int) error {
+	start := off &^ (os.Getpagesize() - 1)
+	return msync(p.mem[start : off+n])
+}
+
+// Path returns the name of the match file.
+func (p *PmemLeague) Path() string {
+	return p.f.Name()
+}
+
+// Close unmaps and closes the match file. Everything MatchResult accepted is
+// already on disk, so there's nothing to flush.
+func (p *PmemLeague) Close() error {
+	var err error
+	if p.mem != nil {
+		err = munmap(p.mem)
+		p.mem = nil
+	}
+	return errors.Join(err, p.f.Close())
+}
@@ -0,0 +1,16 @@
+//go:build !linux
+
+package main
+
+import (
+	"errors"
+	"os"
+)
+
+// The pmem simulation is only implemented on Linux.
+
+func mmapFile(f *os.File, size int) ([]byte, error) { return nil, errors.ErrUnsupported }
+
+func munmap(b []byte) error { return errors.ErrUnsupported }
+
+func msync(b []byte) error { return errors.ErrUnsupported }
@@ -0,0 +1,175 @@
+//go:build linux
+
+package main
+
+import (
+	"os"
+	"reflect"
+	"testing"
+)
+
+func pmemTeams() map[string]Team {
+	return map[string]Team{"A": {Name: "A"}, "B": {Name: "B"}, "C": {Name: "C"}}
+}
+
+var pmemMatches = []Match{
+	{ID: 1, Team1: "A", Score1: 2, Team2: "B", Score2: 1},
+	{ID: 2, Team1: "B", Score1: 0, Team2: "C", Score2: 3},
+	{ID: 3, Team1: "C", Score1: 1, Team2: "A", Score2: 1},
+}
+
+// createPmem records pmemMatches in a new match file, closes it and returns
+// its path and the offset each record starts at.
+func createPmem(t *testing.T) (string, []int) {
+	t.Helper()
+	p, err := CreatePmemLeague(&League{Teams: pmemTeams()}, t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	var offsets []int
+	for _, m := range pmemMatches {
+		offsets = append(offsets, p.end)
+		if err := p.MatchResult(m.Team1, m.Score1, m.Team2, m.Score2); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := p.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return p.Path(), offsets
+}
+
+// reopen opens the match file and checks it replays want.
+func reopen(t *testing.T, path string, want []Match) *PmemLeague {
+	t.Helper()
+	p, err := OpenPmemLeague(path, &League{Teams: pmemTeams()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { p.Close() })
+	if got := p.Matches(); !reflect.DeepEqual(got, want) {
+		t.Fatalf("recovered %+v, want %+v", got, want)
+	}
+	return p
+}
+
+func TestPmemReopen(t *testing.T) {
+	path, _ := createPmem(t)
+	p := reopen(t, path, pmemMatches)
+	if got := p.WinCount("C"); got != 1 {
+		t.Errorf("WinCount(C) = %d, want 1", got)
+	}
+	if got, want := p.Ranking(), []string{"A", "C", "B"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Ranking() = %v, want %v", got, want)
+	}
+}
+
+// TestPmemTornRecord cuts the file off at every byte of the last record, as
+// if the machine died while writing it, and checks the matches before it
+// survive and new ones can be added after them.
+func TestPmemTornRecord(t *testing.T) {
+	path, offsets := createPmem(t)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	last := offsets[len(offsets)-1]
+	for cut := last; cut < last+pmemRecordHeader+40; cut++ {
+		if err := os.WriteFile(path, data[:cut], 0o644); err != nil {
+			t.Fatal(err)
+		}
+		p := reopen(t, path, pmemMatches[:2])
+		if err := p.MatchResult("A", 5, "C", 0); err != nil {
+			t.Fatal(err)
+		}
+		p.Close()
+		want := append(pmemMatches[:2:2], Match{ID: 3, Team1: "A", Score1: 5, Team2: "C", Score2: 0})
+		reopen(t, path, want)
+	}
+}
+
+// TestPmemCRCMismatch damages a record that was written completely, and
+// checks it and everything after it are dropped, and stay dropped.
+func TestPmemCRCMismatch(t *testing.T) {
+	for i, off := range []int{
+		0,                    // the length
+		4,                    // the CRC
+		pmemRecordHeader + 3, // the data
+	} {
+		path, offsets := createPmem(t)
+		data, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		data[offsets[1]+off] ^= 0x40
+		if err := os.WriteFile(path, data, 0o644); err != nil {
+			t.Fatal(err)
+		}
+		p := reopen(t, path, pmemMatches[:1])
+		p.Close()
+		// Opening it cleared the damaged record and the one after it, so a
+		// new record written there can't be followed by the stale one
+		reopen(t, path, pmemMatches[:1])
+		if t.Failed() {
+			t.Fatalf("damaging byte %d of the second record", i)
+		}
+	}
+}
+
+func TestPmemGrow(t *testing.T) {
+	p, err := CreatePmemLeague(&League{Teams: pmemTeams()}, t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Enough records to outgrow the initial mapping a few times
+	for i := 0; i < 5000; i++ {
+		if err := p.MatchResult("A", i%100, "B", 0); err != nil {
+			t.Fatal(err)
+		}
+	}
+	want := p.Matches()
+	if p.end <= pmemInitialSize {
+		t.Fatalf("only wrote %d bytes, want more than %d", p.end, pmemInitialSize)
+	}
+	p.Close()
+	reopen(t, p.Path(), want)
+}
+
+func TestPmemErrors(t *testing.T) {
+	p, err := CreatePmemLeague(&League{Teams: pmemTeams()}, t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	end := p.end
+	// A result the league rejects isn't written
+	if err := p.MatchResult("A", 1, "Z", 0); err == nil {
+		t.Error("MatchResult with an unknown team succeeded")
+	}
+	if p.end != end {
+		t.Errorf("a rejected result was written, end moved from %d to %d", end, p.end)
+	}
+	p.Close()
+	if err := p.MatchResult("A", 1, "B", 0); err == nil {
+		t.Error("MatchResult after Close succeeded")
+	}
+	if len(p.Matches()) != 0 {
+		t.Errorf("a result was recorded after Close: %+v", p.Matches())
+	}
+
+	dir := t.TempDir()
+	for name, content := range map[string]string{
+		"short":    "LGPM",
+		"badMagic": "NOTPMEM1" + string(make([]byte, 64)),
+	} {
+		path := dir + "/" + name
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := OpenPmemLeague(path, &League{Teams: pmemTeams()}); err == nil {
+			t.Errorf("OpenPmemLeague(%s) succeeded", name)
+		}
+	}
+	if _, err := OpenPmemLeague(dir+"/missing", &League{}); err == nil {
+		t.Error("OpenPmemLeague of a missing file succeeded")
+	}
+}
@@ -0,0 +1,110 @@
+package main
+
+import "sort"
+
+// rankCache holds the teams in ranking order along with each team's index in
+// that order. A team's position only changes by a few places when it wins a
+// match, so it can be moved into place without re-sorting the whole league.
+//
+// League's methods that add or remove teams drop the cache. Wins can also be
+// changed directly, so Ranking checks the cached order is still sorted, which
+// costs no more than copying it, and rebuilds it if not. Teams must only be
+// changed with AddTeam and RemoveTeam. The cache is only used for the default
+// order, a custom Tiebreakers chain can be affected by every match in the
+// history so the ranking is sorted from scratch instead.
+type rankCache struct {
+	order []string
+	pos   map[string]int
+}
+
+// rankLess reports whether team a should be ranked above team b, walking the
+// tiebreaker chain until one of them separates the two teams.
+func (l League) rankLess(a, b string) bool {
+	if len(l.Tiebreakers) == 0 {
+		if winsA, winsB := l.WinCount(a), l.WinCount(b); winsA != winsB {
+			return winsA > winsB
+		}
+		return a < b
+	}
+	for _, tb := range l.Tiebreakers {
+		if c := tb(&l, a, b); c != 0 {
+			return c < 0
+		}
+	}
+	return false
+}
+
+// sortedRanking computes the ranking from scratch.
+func (l League) sortedRanking() []string {
+	names := make([]string, 0, len(l.Teams))
+	for k := range l.Teams {
+		names = append(names, k)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		return l.rankLess(names[i], names[j])
+	})
+	return names
+}
+
+func (rc *rankCache) rebuild(l League) {
+	rc.order = l.sortedRanking()
+	rc.pos = make(map[string]int, len(rc.order))
+	for i, name := range rc.order {
+		rc.pos[name] = i
+	}
+}
+
+// valid reports whether the cached order is still the ranking, which it is
+// as long as it has every team and is still sorted.
+func (rc *rankCache) valid(l League) bool {
+	if len(rc.order) != len(l.Teams) {
+		return false
+	}
+	for i := 1; i < len(rc.order); i++ {
+		if l.rankLess(rc.order[i], rc.order[i-1]) {
+			return false
+		}
+	}
+	return true
+}
+
+// invalidateRanking drops the ranking cache, it has to be called whenever
+// Teams changes.
+func (l *League) invalidateRanking() {
+	l.rank = nil
+}
+
+func (rc *rankCache) swap(i, j int) {
+	rc.order[i], rc.order[j] = rc.order[j], rc.order[i]
+	rc.pos[rc.order[i]] = i
+	rc.pos[rc.order[j]] = j
+}
+
+// adjustRanking moves team to its correct place after a single change to its
+// wins. If Wins was also changed directly, the order may still be out of
+// place elsewhere, and Ranking finds that and rebuilds it.
+func (l *League) adjustRanking(team string) {
+	if len(l.Tiebreakers) > 0 {
+		l.invalidateRanking()
+		return
+	}
+	if l.rank == nil {
+		l.rank = &rankCache{}
+		l.rank.rebuild(*l)
+		return
+	}
+	i, ok := l.rank.pos[team]
+	if !ok {
+		l.rank.rebuild(*l)
+		return
+	}
+	order := l.rank.order
+	for i > 0 && l.rankLess(order[i], order[i-1]) {
+		l.rank.swap(i, i-1)
+		i--
+	}
+	for i < len(order)-1 && l.rankLess(order[i+1], order[i]) {
+		l.rank.swap(i, i+1)
+		i++
+	}
+}
@@ -0,0 +1,144 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"maps"
+	"math/rand"
+	"slices"
+	"testing"
+)
+
+// bigLeague returns a league of n teams named "Team 0" onwards.
+func bigLeague(n int) *League {
+	l := &League{Teams: make(map[string]Team, n)}
+	for i := 0; i < n; i++ {
+		name := fmt.Sprintf("Team %d", i)
+		l.Teams[name] = Team{Name: name}
+	}
+	return l
+}
+
+func checkRanking(t *testing.T, l *League, step string) {
+	t.Helper()
+	if got, want := l.Ranking(), l.sortedRanking(); !slices.Equal(got, want) {
+		t.Fatalf("after %s: Ranking() = %v, want %v", step, got, want)
+	}
+}
+
+// TestRankingMatchesSort makes random changes to a league through every
+// method that touches wins or teams, and directly, and checks the cached
+// ranking against a sort from scratch after each one.
+func TestRankingMatchesSort(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	l := bigLeague(30)
+	l.ForfeitPenalty = 1
+	pick := func() string {
+		names := l.sortedRanking()
+		return names[r.Intn(len(names))]
+	}
+	for i := 0; i < 2000; i++ {
+		var step string
+		switch op := r.Intn(20); {
+		case op < 12:
+			a, b := pick(), pick()
+			step = fmt.Sprintf("MatchResult(%s, %s)", a, b)
+			l.MatchResult(a, r.Intn(5), b, r.Intn(5))
+		case op < 14:
+			a, b := pick(), pick()
+			step = fmt.Sprintf("Forfeit(%s, %s)", a, b)
+			l.Forfeit(a, b)
+		case op < 16 && l.nextID > 0:
+			id := r.Intn(l.nextID) + 1
+			step = fmt.Sprintf("CorrectMatch(%d)", id)
+			l.CorrectMatch(id, r.Intn(5), r.Intn(5))
+		case op < 17:
+			name := fmt.Sprintf("New %d", i)
+			step = "AddTeam(" + name + ")"
+			if err := l.AddTeam(Team{Name: name}); err != nil {
+				t.Fatal(err)
+			}
+		case op < 18 && len(l.Teams) > 10:
+			name := pick()
+			step = "RemoveTeam(" + name + ")"
+			if err := l.RemoveTeam(name); err != nil {
+				t.Fatal(err)
+			}
+		case op < 19:
+			name := pick()
+			step = "Wins[" + name + "]"
+			l.Wins[name] = r.Intn(10)
+		default:
+			step = "replacing Wins"
+			l.Wins = maps.Clone(l.Wins)
+			l.Wins[pick()] = r.Intn(10)
+		}
+		checkRanking(t, l, step)
+	}
+}
+
+func TestRankingAfterClone(t *testing.T) {
+	l := bigLeague(5)
+	l.MatchResult("Team 3", 2, "Team 1", 0)
+	checkRanking(t, l, "MatchResult")
+	c := l.clone()
+	c.MatchResult("Team 4", 2, "Team 3", 0)
+	checkRanking(t, c, "MatchResult on a clone")
+	checkRanking(t, l, "MatchResult on a clone of l")
+}
+
+// TestRankingAfterLoad checks a loaded league starts with no cached ranking
+// of its own, and keeps it up to date from there.
+func TestRankingAfterLoad(t *testing.T) {
+	l := bigLeague(5)
+	l.MatchResult("Team 3", 2, "Team 1", 0)
+	l.MatchResult("Team 2", 2, "Team 3", 0)
+	checkRanking(t, l, "MatchResult")
+	var buf bytes.Buffer
+	if err := l.Save(&buf); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := Load(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkRanking(t, loaded, "Load")
+	if !slices.Equal(loaded.Ranking(), l.Ranking()) {
+		t.Errorf("loaded Ranking() = %v, want %v", loaded.Ranking(), l.Ranking())
+	}
+	for _, r := range [][2]string{{"Team 4", "Team 2"}, {"Team 4", "Team 3"}, {"Team 0", "Team 4"}} {
+		if err := loaded.MatchResult(r[0], 1, r[1], 0); err != nil {
+			t.Fatal(err)
+		}
+		checkRanking(t, loaded, "MatchResult after Load")
+	}
+	checkRanking(t, l, "MatchResult on the loaded copy")
+}
+
+// benchmarkRanking records a random result and then asks for the ranking,
+// the way a caller refreshing a table after every result would.
+func benchmarkRanking(b *testing.B, teams int, ranking func(*League) []string) {
+	l := bigLeague(teams)
+	names := l.sortedRanking()
+	r := rand.New(rand.NewSource(1))
+	// Warm the cache and give teams different numbers of wins
+	for i := 0; i < teams; i++ {
+		l.MatchResult(names[r.Intn(teams)], 1, names[r.Intn(teams)], 0)
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		l.MatchResult(names[r.Intn(teams)], 1, names[r.Intn(teams)], 0)
+		ranking(l)
+	}
+}
+
+func BenchmarkRanking(b *testing.B) {
+	for _, teams := range []int{1000, 10_000} {
+		b.Run(fmt.Sprintf("incremental/%d", teams), func(b *testing.B) {
+			benchmarkRanking(b, teams, (*League).Ranking)
+		})
+		b.Run(fmt.Sprintf("sort/%d", teams), func(b *testing.B) {
+			benchmarkRanking(b, teams, (*League).sortedRanking)
+		})
+	}
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"fmt"
+	"plugin"
+)
+
+// ScoringSystem turns a team's record into league points. Set
+// League.Scoring to change how Standings and ByPoints award points.
+type ScoringSystem interface {
+	Points(wins, draws, losses int) int
+}
+
+// StandardScoring awards three points for a win, one for a draw and none for
+// a loss. It's used when League.Scoring is unset.
+type StandardScoring struct{}
+
+func (StandardScoring) Points(wins, draws, losses int) int {
+	return wins*pointsPerWin + draws*pointsPerDraw
+}
+
+// points returns the named team's points under the league's scoring system.
+func (l *League) points(name string, draws, losses int) int {
+	scoring := l.Scoring
+	if scoring == nil {
+		scoring = StandardScoring{}
+	}
+	return scoring.Points(l.WinCount(name), draws, losses)
+}
+
+// losses counts the matches the named team has lost.
+func (l *League) losses(name string) int {
+	n := 0
+	for _, m := range l.history {
+		if (m.Team1 == name && m.Score1 < m.Score2) || (m.Team2 == name && m.Score2 < m.Score1) {
+			n++
+		}
+	}
+	return n
+}
+
+// LoadScoringPlugin opens a plugin built with -buildmode=plugin and returns
+// its exported ScoringSystem variable, see scoringplugin for a template. The
+// plugin must be built with the same Go version as this program.
+func LoadScoringPlugin(path string) (ScoringSystem, error) {
+	p, err := plugin.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	sym, err := p.Lookup("ScoringSystem")
+	if err != nil {
+		return nil, err
+	}
+	// Lookup returns a pointer to a variable, so a plugin that exports
+	// `var ScoringSystem Scoring` gives us a *Scoring
+	scoring, ok := sym.(ScoringSystem)
+	if !ok {
+		return nil, fmt.Errorf("%s: ScoringSystem is a %T, which has no Points(wins, draws, losses int) int method", path, sym)
+	}
+	return scoring, nil
+}
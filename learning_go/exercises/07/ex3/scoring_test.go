@@ -0,0 +1,71 @@
+package main
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+// buildPlugin builds the package or file at src as a plugin and returns
+// the path to it. Plugins need cgo and a supported platform, so the test is
+// skipped where it can't be built.
+func buildPlugin(t *testing.T, src string) string {
+	t.Helper()
+	if runtime.GOOS != "linux" && runtime.GOOS != "darwin" && runtime.GOOS != "freebsd" {
+		t.Skipf("plugins aren't supported on %s", runtime.GOOS)
+	}
+	if testing.Short() {
+		t.Skip("building a plugin is slow")
+	}
+	gocmd := filepath.Join(runtime.GOROOT(), "bin", "go")
+	if env, err := exec.Command(gocmd, "env", "CGO_ENABLED").Output(); err != nil || strings.TrimSpace(string(env)) != "1" {
+		t.Skip("plugins need cgo")
+	}
+	out := filepath.Join(t.TempDir(), "scoring.so")
+	cmd := exec.Command(gocmd, "build", "-buildmode=plugin", "-o", out, src)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("building %s: %v\n%s", src, err, output)
+	}
+	return out
+}
+
+func TestLoadScoringPlugin(t *testing.T) {
+	scoring, err := LoadScoringPlugin(buildPlugin(t, "./scoringplugin"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Two points for a win and one for a draw
+	if got := scoring.Points(3, 2, 1); got != 8 {
+		t.Errorf("Points(3, 2, 1) = %d, want 8", got)
+	}
+
+	l := &League{Teams: map[string]Team{"A": {Name: "A"}, "B": {Name: "B"}}, Scoring: scoring}
+	for _, m := range [][2]int{{2, 0}, {1, 1}} {
+		if err := l.MatchResult("A", m[0], "B", m[1]); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if rows := l.Standings(); rows[0].Team != "A" || rows[0].Points != 3 || rows[1].Points != 1 {
+		t.Errorf("Standings = %+v, want A on 3 points and B on 1", rows)
+	}
+}
+
+func TestLoadScoringPluginErrors(t *testing.T) {
+	if _, err := LoadScoringPlugin(filepath.Join(t.TempDir(), "missing.so")); err == nil {
+		t.Error("loading a missing plugin succeeded")
+	}
+
+	// A ScoringSystem without a Points method
+	src := filepath.Join(t.TempDir(), "bad.go")
+	code := "package main\n\nvar ScoringSystem = 3\n\nfunc main() {}\n"
+	if err := os.WriteFile(src, []byte(code), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	_, err := LoadScoringPlugin(buildPlugin(t, src))
+	if err == nil || !strings.Contains(err.Error(), "has no Points") {
+		t.Errorf("loading a plugin with the wrong type: error = %v", err)
+	}
+}
@@ -0,0 +1,24 @@
+// Command scoringplugin is a template for a League scoring plugin. Build it
+// with
+//
+//	go build -buildmode=plugin -o scoring.so ./scoringplugin
+//
+// and load it with LoadScoringPlugin("scoring.so"). A plugin can't import the
+// league's package, so it only has to export a variable named ScoringSystem
+// whose type has a Points(wins, draws, losses int) int method.
+package main
+
+// Scoring gives two points for a win and one for a draw, the system many
+// leagues used before three points for a win.
+type Scoring struct{}
+
+func (Scoring) Points(wins, draws, losses int) int {
+	return wins*2 + draws
+}
+
+// ScoringSystem is the symbol LoadScoringPlugin looks up.
+var ScoringSystem Scoring
+
+// main is never run when built as a plugin, it's only here so the package
+// also builds as a normal program.
+func main() {}
@@ -0,0 +1,114 @@
+package main
+
+import (
+	"maps"
+	"math/rand"
+	"runtime"
+)
+
+// clone returns a deep copy of the league, so changes to the copy never
+// show up in the original. The copy has no EventBus, so results recorded on
+// it aren't published.
+func (l League) clone() *League {
+	out := l
+	out.Teams = make(map[string]Team, len(l.Teams))
+	for k, v := range l.Teams {
+		v.Players = append([]string(nil), v.Players...)
+		out.Teams[k] = v
+	}
+	out.Wins = maps.Clone(l.Wins)
+	out.Fixtures = append([]Fixture(nil), l.Fixtures...)
+	out.history = append([]Match(nil), l.history...)
+	out.invalidateRanking()
+	out.EventBus = nil
+	return &out
+}
+
+// gamesPlayed returns how many matches each team has in the history.
+func (l League) gamesPlayed() map[string]int {
+	played := map[string]int{}
+	for _, m := range l.history {
+		played[m.Team1]++
+		played[m.Team2]++
+	}
+	return played
+}
+
+// SimulateRemaining plays out every fixture that doesn't have a result yet
+// and returns a copy of the league with the simulated results added. The
+// original league isn't changed. Each game is won at random, weighted by how
+// often each team has won so far. Passing a source with a fixed seed gives
+// the same results every time.
+func (l League) SimulateRemaining(src rand.Source) *League {
+	out := l.clone()
+	r := rand.New(src)
+	played := out.gamesPlayed()
+	for _, f := range out.unplayedFixtures() {
+		// Add one win and one loss to every team so teams that haven't
+		// played yet still have a chance
+		home := float64(out.WinCount(f.Home)+1) / float64(played[f.Home]+2)
+		away := float64(out.WinCount(f.Away)+1) / float64(played[f.Away]+2)
+		if r.Float64()*(home+away) < home {
+			out.MatchResult(f.Home, 1, f.Away, 0)
+		} else {
+			out.MatchResult(f.Home, 0, f.Away, 1)
+		}
+		played[f.Home]++
+		played[f.Away]++
+	}
+	return out
+}
+
+// FinishProbabilities simulates the rest of the season iterations times and
+// returns, for each team, the probability of finishing in each position.
+// Index 0 is the probability of finishing first.
+//
+// The simulations are split across SimulationWorkers goroutines. Each worker
+// gets its own random source seeded from src, so results are reproducible for
+// a fixed seed and worker count.
+func (l League) FinishProbabilities(iterations int, src rand.Source) map[string][]float64 {
+	probs := make(map[string][]float64, len(l.Teams))
+	for name := range l.Teams {
+		probs[name] = make([]float64, len(l.Teams))
+	}
+	if iterations <= 0 {
+		return probs
+	}
+	workers := l.SimulationWorkers
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	if workers > iterations {
+		workers = iterations
+	}
+
+	results := make(chan map[string][]int, workers)
+	for i := 0; i < workers; i++ {
+		n := iterations / workers
+		if i < iterations%workers {
+			n++
+		}
+		go func(n int, seed int64) {
+			counts := make(map[string][]int, len(l.Teams))
+			for name := range l.Teams {
+				counts[name] = make([]int, len(l.Teams))
+			}
+			r := rand.NewSource(seed)
+			for j := 0; j < n; j++ {
+				for pos, name := range l.SimulateRemaining(r).Ranking() {
+					counts[name][pos]++
+				}
+			}
+			results <- counts
+		}(n, src.Int63())
+	}
+
+	for i := 0; i < workers; i++ {
+		for name, counts := range <-results {
+			for pos, c := range counts {
+				probs[name][pos] += float64(c) / float64(iterations)
+			}
+		}
+	}
+	return probs
+}
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"maps"
+	"math"
+	"math/rand"
+	"slices"
+	"testing"
+)
+
+// simulationLeague has six teams that meet once, with a few results already
+// played.
+func simulationLeague(t *testing.T, workers int) *League {
+	t.Helper()
+	l := bigLeague(6)
+	l.SimulationWorkers = workers
+	l.GenerateFixtures()
+	for _, f := range l.Fixtures[:5] {
+		if err := l.MatchResult(f.Home, 2, f.Away, 1); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return l
+}
+
+func TestFinishProbabilitiesSumToOne(t *testing.T) {
+	for _, workers := range []int{1, 4} {
+		l := simulationLeague(t, workers)
+		probs := l.FinishProbabilities(2000, rand.NewSource(1))
+		if len(probs) != len(l.Teams) {
+			t.Fatalf("%d workers: got probabilities for %d teams, want %d", workers, len(probs), len(l.Teams))
+		}
+		// Each team finishes somewhere, and each position is taken by
+		// someone
+		byPos := make([]float64, len(l.Teams))
+		for name, p := range probs {
+			if len(p) != len(l.Teams) {
+				t.Fatalf("%d workers: %s has %d positions, want %d", workers, name, len(p), len(l.Teams))
+			}
+			sum := 0.0
+			for pos, v := range p {
+				if v < 0 || v > 1 {
+					t.Errorf("%d workers: %s finishes %d with probability %g", workers, name, pos+1, v)
+				}
+				sum += v
+				byPos[pos] += v
+			}
+			if math.Abs(sum-1) > 1e-9 {
+				t.Errorf("%d workers: %s's probabilities sum to %g, want 1", workers, name, sum)
+			}
+		}
+		for pos, sum := range byPos {
+			if math.Abs(sum-1) > 1e-9 {
+				t.Errorf("%d workers: position %d's probabilities sum to %g, want 1", workers, pos+1, sum)
+			}
+		}
+	}
+}
+
+func TestFinishProbabilitiesDeterministic(t *testing.T) {
+	l := simulationLeague(t, 1)
+	first := l.FinishProbabilities(1000, rand.NewSource(42))
+	second := l.FinishProbabilities(1000, rand.NewSource(42))
+	if !maps.EqualFunc(first, second, slices.Equal[[]float64]) {
+		t.Errorf("the same seed gave different probabilities:\n%v\n%v", first, second)
+	}
+	other := l.FinishProbabilities(1000, rand.NewSource(43))
+	if maps.EqualFunc(first, other, slices.Equal[[]float64]) {
+		t.Error("different seeds gave the same probabilities")
+	}
+}
+
+func TestFinishProbabilitiesNoIterations(t *testing.T) {
+	l := simulationLeague(t, 2)
+	for name, p := range l.FinishProbabilities(0, rand.NewSource(1)) {
+		if slices.ContainsFunc(p, func(v float64) bool { return v != 0 }) {
+			t.Errorf("%s has probabilities %v with no iterations", name, p)
+		}
+	}
+}
+
+func TestSimulateRemainingLeavesLeague(t *testing.T) {
+	l := simulationLeague(t, 1)
+	matches, wins := len(l.Matches()), maps.Clone(l.Wins)
+	out := l.SimulateRemaining(rand.NewSource(1))
+	if len(out.unplayedFixtures()) != 0 {
+		t.Errorf("%d fixtures left unplayed", len(out.unplayedFixtures()))
+	}
+	if len(out.Matches()) != len(l.Fixtures) {
+		t.Errorf("simulated league has %d matches, want %d", len(out.Matches()), len(l.Fixtures))
+	}
+	if len(l.Matches()) != matches || !maps.Equal(l.Wins, wins) {
+		t.Error("SimulateRemaining changed the original league")
+	}
+}
@@ -0,0 +1,46 @@
+package main
+
+// StandingsSnapshot is a copy of the league table at one point in time.
+type StandingsSnapshot []StandingsRow
+
+// Movement is how far a team moved between two snapshots. A positive Delta
+// means the team moved up the table. A team that is only in the new snapshot
+// has an OldRank of 0, a team that is only in the old one has a NewRank of 0,
+// and both have a Delta of 0.
+type Movement struct {
+	Team    string
+	OldRank int
+	NewRank int
+	Delta   int
+}
+
+// Snapshot returns the current league table. Rows don't share any memory
+// with the league, so later results don't change the snapshot.
+func (l League) Snapshot() StandingsSnapshot {
+	return StandingsSnapshot(l.Standings())
+}
+
+// DiffStandings returns the movement of every team in new, in new's order,
+// followed by the teams that were dropped since old, in old's order.
+func DiffStandings(old, new StandingsSnapshot) []Movement {
+	oldRanks := make(map[string]int, len(old))
+	for _, row := range old {
+		oldRanks[row.Team] = row.Rank
+	}
+	seen := make(map[string]bool, len(new))
+	out := make([]Movement, 0, len(new))
+	for _, row := range new {
+		seen[row.Team] = true
+		m := Movement{Team: row.Team, OldRank: oldRanks[row.Team], NewRank: row.Rank}
+		if m.OldRank != 0 {
+			m.Delta = m.OldRank - m.NewRank
+		}
+		out = append(out, m)
+	}
+	for _, row := range old {
+		if !seen[row.Team] {
+			out = append(out, Movement{Team: row.Team, OldRank: row.Rank})
+		}
+	}
+	return out
+}
@@ -0,0 +1,424 @@
+package main
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"strings"
+)
+
+// This file reads match logs straight from an SQLite database file, without
+// cgo or an SQLite library. It only understands as much of the file format
+// as it needs to walk a table: the header, table b-tree pages, overflow pages
+// and records. Indexes are ignored. Changes still sitting in a -wal file
+// aren't seen, so checkpoint the database before reading it.
+
+const sqliteMagic = "SQLite format 3\x00"
+
+// sqliteFile is an SQLite database loaded into memory.
+type sqliteFile struct {
+	data     []byte
+	pageSize int
+	// usable is the page size minus the bytes each page reserves for
+	// extensions
+	usable int
+}
+
+// sqliteRow is one row of a table, with its rowid.
+type sqliteRow struct {
+	rowid  int64
+	values []any
+}
+
+func openSQLite(path string) (*sqliteFile, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	f, err := newSQLiteFile(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return f, nil
+}
+
+func newSQLiteFile(data []byte) (*sqliteFile, error) {
+	if len(data) < 100 || string(data[:16]) != sqliteMagic {
+		return nil, errors.New("not an SQLite database")
+	}
+	pageSize := int(binary.BigEndian.Uint16(data[16:18]))
+	if pageSize == 1 {
+		pageSize = 65536
+	}
+	if pageSize < 512 || len(data)%pageSize != 0 {
+		return nil, fmt.Errorf("bad page size %d", pageSize)
+	}
+	return &sqliteFile{data: data, pageSize: pageSize, usable: pageSize - int(data[20])}, nil
+}
+
+func (f *sqliteFile) page(n uint32) ([]byte, error) {
+	start := (int(n) - 1) * f.pageSize
+	if n == 0 || start+f.pageSize > len(f.data) {
+		return nil, fmt.Errorf("page %d out of range", n)
+	}
+	return f.data[start : start+f.pageSize], nil
+}
+
+// readVarint decodes an SQLite varint, which is big endian with 7 bits per
+// byte, except that the ninth byte contributes all 8 of its bits.
+func readVarint(b []byte) (int64, int, error) {
+	var v uint64
+	for i := 0; i < 9; i++ {
+		if i >= len(b) {
+			return 0, 0, errors.New("truncated varint")
+		}
+		if i == 8 {
+			return int64(v<<8 | uint64(b[i])), 9, nil
+		}
+		v = v<<7 | uint64(b[i]&0x7f)
+		if b[i]&0x80 == 0 {
+			return int64(v), i + 1, nil
+		}
+	}
+	panic("unreachable")
+}
+
+// tableRows walks the table b-tree rooted at root and returns its rows in
+// rowid order.
+func (f *sqliteFile) tableRows(root uint32) ([]sqliteRow, error) {
+	var rows []sqliteRow
+	// visited guards against looping forever on a corrupt file
+	visited := map[uint32]bool{}
+	var walk func(n uint32) error
+	walk = func(n uint32) error {
+		if visited[n] {
+			return fmt.Errorf("page %d is linked twice", n)
+		}
+		visited[n] = true
+		p, err := f.page(n)
+		if err != nil {
+			return err
+		}
+		// Page 1 starts with the database header
+		hdr := 0
+		if n == 1 {
+			hdr = 100
+		}
+		kind := p[hdr]
+		cells := int(binary.BigEndian.Uint16(p[hdr+3:]))
+		if hdr+12+2*cells > len(p) {
+			return fmt.Errorf("page %d: too many cells", n)
+		}
+		switch kind {
+		case 0x05:
+			// Interior page: each cell points at the subtree to its left,
+			// the right most subtree is in the header
+			for i := 0; i < cells; i++ {
+				off := int(binary.BigEndian.Uint16(p[hdr+12+2*i:]))
+				if off+4 > len(p) {
+					return fmt.Errorf("page %d: bad cell offset", n)
+				}
+				if err := walk(binary.BigEndian.Uint32(p[off:])); err != nil {
+					return err
+				}
+			}
+			return walk(binary.BigEndian.Uint32(p[hdr+8:]))
+		case 0x0d:
+			for i := 0; i < cells; i++ {
+				off := int(binary.BigEndian.Uint16(p[hdr+8+2*i:]))
+				if off >= len(p) {
+					return fmt.Errorf("page %d: bad cell offset", n)
+				}
+				row, err := f.leafCell(p[off:])
+				if err != nil {
+					return fmt.Errorf("page %d cell %d: %w", n, i, err)
+				}
+				rows = append(rows, row)
+			}
+			return nil
+		}
+		return fmt.Errorf("page %d: not a table b-tree page (type %#x)", n, kind)
+	}
+	if err := walk(root); err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
+
+// leafCell decodes a table leaf cell: the payload size, the rowid and the
+// record, part of which may have spilled onto overflow pages.
+func (f *sqliteFile) leafCell(cell []byte) (sqliteRow, error) {
+	size, n, err := readVarint(cell)
+	if err != nil {
+		return sqliteRow{}, err
+	}
+	rowid, m, err := readVarint(cell[n:])
+	if err != nil {
+		return sqliteRow{}, err
+	}
+	cell = cell[n+m:]
+	// A payload can't be bigger than the file, this also stops a corrupt
+	// size from following overflow pages round in a loop for ever
+	if size < 0 || size > int64(len(f.data)) {
+		return sqliteRow{}, fmt.Errorf("bad payload size %d", size)
+	}
+
+	// How much of the payload is stored on the page itself is worked out
+	// from the usable page size, as described in the file format docs
+	local := int(size)
+	maxLocal := f.usable - 35
+	if local > maxLocal {
+		minLocal := (f.usable-12)*32/255 - 23
+		local = minLocal + (int(size)-minLocal)%(f.usable-4)
+		if local > maxLocal {
+			local = minLocal
+		}
+	}
+	if local > len(cell) {
+		return sqliteRow{}, errors.New("payload runs past the end of the page")
+	}
+	payload := append([]byte(nil), cell[:local]...)
+	if local < int(size) {
+		if local+4 > len(cell) {
+			return sqliteRow{}, errors.New("overflow page number runs past the end of the page")
+		}
+		next := binary.BigEndian.Uint32(cell[local:])
+		for len(payload) < int(size) {
+			p, err := f.page(next)
+			if err != nil {
+				return sqliteRow{}, fmt.Errorf("overflow: %w", err)
+			}
+			chunk := p[4:f.usable]
+			if rest := int(size) - len(payload); len(chunk) > rest {
+				chunk = chunk[:rest]
+			}
+			payload = append(payload, chunk...)
+			next = binary.BigEndian.Uint32(p)
+		}
+	}
+	values, err := decodeRecord(payload)
+	if err != nil {
+		return sqliteRow{}, err
+	}
+	return sqliteRow{rowid: rowid, values: values}, nil
+}
+
+// decodeRecord turns a record into Go values: nil, int64, float64, string or
+// []byte.
+func decodeRecord(rec []byte) ([]any, error) {
+	hdrSize, n, err := readVarint(rec)
+	if err != nil {
+		return nil, err
+	}
+	if int(hdrSize) < n || int(hdrSize) > len(rec) {
+		return nil, errors.New("record header runs past the end of the record")
+	}
+	header, body := rec[n:hdrSize], rec[hdrSize:]
+	var values []any
+	for len(header) > 0 {
+		st, n, err := readVarint(header)
+		if err != nil {
+			return nil, err
+		}
+		header = header[n:]
+		if st < 0 {
+			return nil, fmt.Errorf("unknown serial type %d", st)
+		}
+		size := serialSize(st)
+		if size > len(body) {
+			return nil, errors.New("record value runs past the end of the record")
+		}
+		v := body[:size]
+		body = body[size:]
+		switch {
+		case st == 0:
+			values = append(values, nil)
+		case st >= 1 && st <= 6:
+			// Big endian two's complement, sign extended from its width
+			var x int64
+			if v[0]&0x80 != 0 {
+				x = -1
+			}
+			for _, b := range v {
+				x = x<<8 | int64(b)
+			}
+			values = append(values, x)
+		case st == 7:
+			values = append(values, math.Float64frombits(binary.BigEndian.Uint64(v)))
+		case st == 8 || st == 9:
+			values = append(values, st-8)
+		case st >= 12 && st%2 == 0:
+			values = append(values, append([]byte(nil), v...))
+		case st >= 13:
+			values = append(values, string(v))
+		default:
+			return nil, fmt.Errorf("unknown serial type %d", st)
+		}
+	}
+	return values, nil
+}
+
+// serialSize returns how many bytes a value of serial type st takes up in
+// the record body.
+func serialSize(st int64) int {
+	switch {
+	case st <= 4:
+		return int(st)
+	case st == 5:
+		return 6
+	case st == 6 || st == 7:
+		return 8
+	case st >= 12:
+		return int(st-12) / 2
+	}
+	return 0
+}
+
+// columnNames pulls the column names out of a CREATE TABLE statement,
+// skipping table constraints. It's not a full SQL parser, but handles the
+// statements SQLite stores for plain tables.
+func columnNames(sql string) []string {
+	start, end := strings.Index(sql, "("), strings.LastIndex(sql, ")")
+	if start < 0 || end < start {
+		return nil
+	}
+	var defs []string
+	depth, last := 0, start+1
+	for i := start + 1; i < end; i++ {
+		switch sql[i] {
+		case '(':
+			depth++
+		case ')':
+			depth--
+		case ',':
+			if depth == 0 {
+				defs = append(defs, sql[last:i])
+				last = i + 1
+			}
+		}
+	}
+	defs = append(defs, sql[last:end])
+
+	var names []string
+	for _, def := range defs {
+		fields := strings.Fields(def)
+		if len(fields) == 0 {
+			continue
+		}
+		switch strings.ToUpper(fields[0]) {
+		case "PRIMARY", "UNIQUE", "CHECK", "FOREIGN", "CONSTRAINT":
+			continue
+		}
+		names = append(names, strings.ToLower(strings.Trim(fields[0], "\"`[]")))
+	}
+	return names
+}
+
+// ReadMatchesFromDB reads the matches table of an SQLite database. The table
+// needs team1, score1, team2 and score2 columns, and may have id and
+// forfeit columns. Without an id column, or when it's the INTEGER PRIMARY
+// KEY, the rowid is used as the match ID.
+func ReadMatchesFromDB(path string) ([]Match, error) {
+	f, err := openSQLite(path)
+	if err != nil {
+		return nil, err
+	}
+	matches, err := f.matches()
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return matches, nil
+}
+
+// matches does the work for ReadMatchesFromDB.
+func (f *sqliteFile) matches() ([]Match, error) {
+	schema, err := f.tableRows(1)
+	if err != nil {
+		return nil, fmt.Errorf("reading schema: %w", err)
+	}
+	var root int64
+	var sql string
+	for _, row := range schema {
+		// type, name, tbl_name, rootpage, sql
+		if len(row.values) < 5 || row.values[0] != "table" || row.values[1] != "matches" {
+			continue
+		}
+		root, _ = row.values[3].(int64)
+		sql, _ = row.values[4].(string)
+	}
+	if root == 0 {
+		return nil, errors.New("no matches table")
+	}
+	col := map[string]int{}
+	for i, name := range columnNames(sql) {
+		col[name] = i
+	}
+	for _, name := range []string{"team1", "score1", "team2", "score2"} {
+		if _, ok := col[name]; !ok {
+			return nil, fmt.Errorf("matches table has no %s column", name)
+		}
+	}
+
+	rows, err := f.tableRows(uint32(root))
+	if err != nil {
+		return nil, fmt.Errorf("reading matches: %w", err)
+	}
+	matches := make([]Match, 0, len(rows))
+	for _, row := range rows {
+		// Columns added with ALTER TABLE are missing from older rows
+		value := func(name string) any {
+			i, ok := col[name]
+			if !ok || i >= len(row.values) {
+				return nil
+			}
+			return row.values[i]
+		}
+		m := Match{ID: int(row.rowid)}
+		if id, ok := value("id").(int64); ok {
+			m.ID = int(id)
+		}
+		var ok1, ok2, ok3, ok4 bool
+		var s1, s2 int64
+		m.Team1, ok1 = value("team1").(string)
+		s1, ok2 = value("score1").(int64)
+		m.Team2, ok3 = value("team2").(string)
+		s2, ok4 = value("score2").(int64)
+		if !ok1 || !ok2 || !ok3 || !ok4 {
+			return nil, fmt.Errorf("match row %d: unexpected column types", row.rowid)
+		}
+		m.Score1, m.Score2 = int(s1), int(s2)
+		if forfeit, ok := value("forfeit").(int64); ok {
+			m.Forfeit = forfeit != 0
+		}
+		matches = append(matches, m)
+	}
+	return matches, nil
+}
+
+// LoadFromSQLite builds a league from the matches table of an SQLite
+// database, see ReadMatchesFromDB. Every team named in a match is added, and
+// the matches keep their IDs. Forfeit penalties aren't stored in the
+// database, so they aren't applied.
+func LoadFromSQLite(path string) (*League, error) {
+	matches, err := ReadMatchesFromDB(path)
+	if err != nil {
+		return nil, err
+	}
+	l := &League{Teams: map[string]Team{}}
+	for _, m := range matches {
+		for _, name := range []string{m.Team1, m.Team2} {
+			if _, ok := l.Teams[name]; ok {
+				continue
+			}
+			if err := l.AddTeam(Team{Name: name}); err != nil {
+				return nil, fmt.Errorf("match %d: %w", m.ID, err)
+			}
+		}
+		l.history = append(l.history, m)
+		l.nextID = max(l.nextID, m.ID)
+		l.applyResult(m, 1)
+	}
+	return l, nil
+}
@@ -0,0 +1,116 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// testdata/matches.db is written by the sqlite3 shell from
+// testdata/matches.sql, it has interior pages, an overflow chain and a
+// column added with ALTER TABLE.
+const matchesDB = "testdata/matches.db"
+
+func wantDBMatches() []Match {
+	var want []Match
+	for i := 1; i <= 200; i++ {
+		want = append(want, Match{
+			ID:     i,
+			Team1:  fmt.Sprintf("Team%d", i%7),
+			Score1: i % 5,
+			Team2:  fmt.Sprintf("Team%d", i%7+1),
+			Score2: i * 3 % 5,
+		})
+	}
+	return append(want,
+		Match{ID: 201, Team1: strings.Repeat("X", 1000), Score1: 100000, Team2: "Team1", Score2: -3},
+		Match{ID: 202, Team1: "Team2", Score1: 0, Team2: "Team3", Score2: 20, Forfeit: true},
+	)
+}
+
+func TestReadMatchesFromDB(t *testing.T) {
+	got, err := ReadMatchesFromDB(matchesDB)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := wantDBMatches()
+	if len(got) != len(want) {
+		t.Fatalf("got %d matches, want %d", len(got), len(want))
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("match %d: got %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestLoadFromSQLite(t *testing.T) {
+	l, err := LoadFromSQLite(matchesDB)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := &League{Teams: map[string]Team{}}
+	for _, m := range wantDBMatches() {
+		for _, name := range []string{m.Team1, m.Team2} {
+			if _, ok := want.Teams[name]; !ok {
+				want.AddTeam(Team{Name: name})
+			}
+		}
+		want.recordMatch(m)
+		want.applyResult(m, 1)
+	}
+	if !reflect.DeepEqual(l.Matches(), want.Matches()) {
+		t.Error("matches differ from the database")
+	}
+	if !reflect.DeepEqual(l.Standings(), want.Standings()) {
+		t.Errorf("got standings %v, want %v", l.Standings(), want.Standings())
+	}
+	if err := l.MatchResult("Team1", 1, "Team2", 0); err != nil {
+		t.Fatal(err)
+	}
+	if got := l.Matches()[len(l.Matches())-1].ID; got != 203 {
+		t.Errorf("next match got ID %d, want 203", got)
+	}
+}
+
+// TestSQLiteCorrupt damages every byte of the database in turn. Reading it
+// may fail, but it mustn't panic or hang.
+func TestSQLiteCorrupt(t *testing.T) {
+	data, err := os.ReadFile(matchesDB)
+	if err != nil {
+		t.Fatal(err)
+	}
+	damaged := make([]byte, len(data))
+	for i := range data {
+		for _, b := range []byte{0x00, 0xff, data[i] ^ 0x80} {
+			copy(damaged, data)
+			damaged[i] = b
+			f, err := newSQLiteFile(damaged)
+			if err != nil {
+				continue
+			}
+			func() {
+				defer func() {
+					if r := recover(); r != nil {
+						t.Fatalf("byte %d set to %#x: panic: %v", i, b, r)
+					}
+				}()
+				f.matches()
+			}()
+		}
+	}
+}
+
+func TestLeafCellTruncated(t *testing.T) {
+	f := &sqliteFile{data: make([]byte, 4*512), pageSize: 512, usable: 512}
+	// A 1000 byte payload keeps 39 bytes on a 512 byte page, and needs the
+	// number of its first overflow page after them
+	cell := append([]byte{0x87, 0x68, 0x01}, make([]byte, 39)...)
+	for _, c := range [][]byte{cell, append(cell, 0, 0)} {
+		if _, err := f.leafCell(c); err == nil || !strings.Contains(err.Error(), "overflow") {
+			t.Errorf("%d byte cell: got error %v, want one about the overflow page", len(c), err)
+		}
+	}
+}
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"encoding/csv"
+	"io"
+	"strconv"
+)
+
+const (
+	pointsPerWin  = 3
+	pointsPerDraw = 1
+)
+
+// StandingsRow is one team's line in the league table. Wins includes any
+// forfeit penalties, Points comes from the league's ScoringSystem, which
+// defaults to three per win and one per draw.
+type StandingsRow struct {
+	Rank   int    `json:"rank"`
+	Team   string `json:"team"`
+	Played int    `json:"played"`
+	Wins   int    `json:"wins"`
+	Draws  int    `json:"draws"`
+	Losses int    `json:"losses"`
+	Points int    `json:"points"`
+}
+
+// standingsHeader names the columns written by WriteStandingsCSV, in the
+// same order as the fields of StandingsRow.
+var standingsHeader = []string{"rank", "team", "played", "wins", "draws", "losses", "points"}
+
+// Standings returns the league table in ranking order.
+func (l League) Standings() []StandingsRow {
+	draws := map[string]int{}
+	losses := map[string]int{}
+	for _, m := range l.history {
+		switch {
+		case m.Score1 == m.Score2:
+			draws[m.Team1]++
+			draws[m.Team2]++
+		case m.Score1 > m.Score2:
+			losses[m.Team2]++
+		default:
+			losses[m.Team1]++
+		}
+	}
+	played := l.gamesPlayed()
+	ranking := l.Ranking()
+	rows := make([]StandingsRow, 0, len(ranking))
+	for i, name := range ranking {
+		rows = append(rows, StandingsRow{
+			Rank:   i + 1,
+			Team:   name,
+			Played: played[name],
+			Wins:   l.WinCount(name),
+			Draws:  draws[name],
+			Losses: losses[name],
+			Points: l.points(name, draws[name], losses[name]),
+		})
+	}
+	return rows
+}
+
+// WriteStandingsCSV writes the league table as CSV with a header row.
+func (l League) WriteStandingsCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(standingsHeader); err != nil {
+		return err
+	}
+	for _, row := range l.Standings() {
+		record := []string{
+			strconv.Itoa(row.Rank),
+			row.Team,
+			strconv.Itoa(row.Played),
+			strconv.Itoa(row.Wins),
+			strconv.Itoa(row.Draws),
+			strconv.Itoa(row.Losses),
+			strconv.Itoa(row.Points),
+		}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"encoding/csv"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestWriteStandingsCSV(t *testing.T) {
+	// Names with a comma and quotes have to be quoted in the CSV
+	l := &League{Teams: map[string]Team{}}
+	for _, name := range []string{"A, B", `Q "uoted"`, "C", "D"} {
+		l.Teams[name] = Team{Name: name}
+	}
+	for _, m := range []Match{
+		{Team1: "A, B", Score1: 2, Team2: "C", Score2: 1},
+		{Team1: "A, B", Score1: 1, Team2: `Q "uoted"`, Score2: 1},
+		{Team1: `Q "uoted"`, Score1: 3, Team2: "C", Score2: 0},
+	} {
+		if err := l.MatchResult(m.Team1, m.Score1, m.Team2, m.Score2); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var b strings.Builder
+	if err := l.WriteStandingsCSV(&b); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(b.String(), `"A, B"`) || !strings.Contains(b.String(), `"Q ""uoted"""`) {
+		t.Errorf("team names aren't quoted:\n%s", b.String())
+	}
+
+	records, err := csv.NewReader(strings.NewReader(b.String())).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := [][]string{
+		{"rank", "team", "played", "wins", "draws", "losses", "points"},
+		{"1", "A, B", "2", "1", "1", "0", "4"},
+		{"2", `Q "uoted"`, "2", "1", "1", "0", "4"},
+		{"3", "C", "2", "0", "0", "2", "0"},
+		{"4", "D", "0", "0", "0", "0", "0"},
+	}
+	if !slices.EqualFunc(records, want, slices.Equal[[]string]) {
+		t.Errorf("CSV records =\n%q\nwant\n%q", records, want)
+	}
+	// The rows are the same as Standings, in the same order
+	rows := l.Standings()
+	if len(rows) != len(records)-1 {
+		t.Fatalf("Standings has %d rows, the CSV has %d", len(rows), len(records)-1)
+	}
+	for i, row := range rows {
+		if records[i+1][1] != row.Team {
+			t.Errorf("CSV row %d is %s, Standings has %s", i+1, records[i+1][1], row.Team)
+		}
+	}
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var (
+	// ErrUnknownTeam is wrapped with the name of a team that isn't in the
+	// league.
+	ErrUnknownTeam = errors.New("unknown team")
+	// ErrDuplicateTeam is wrapped with the name of a team that's already in
+	// the league.
+	ErrDuplicateTeam = errors.New("team already exists")
+)
+
+// AddTeam adds t to the league with no wins. The schedule isn't changed,
+// call GenerateFixtures to include the new team.
+func (l *League) AddTeam(t Team) error {
+	if strings.TrimSpace(t.Name) == "" {
+		return errors.New("team name can't be empty")
+	}
+	if _, ok := l.Teams[t.Name]; ok {
+		return fmt.Errorf("%w: %s", ErrDuplicateTeam, t.Name)
+	}
+	if l.Teams == nil {
+		l.Teams = map[string]Team{}
+	}
+	t.Players = append([]string(nil), t.Players...)
+	l.Teams[t.Name] = t
+	l.invalidateRanking()
+	return nil
+}
+
+// RemoveTeam takes the named team out of the league along with its fixtures
+// and matches. The results of its matches are undone, so the teams it played
+// lose the wins they had against it. Forfeit penalties are kept.
+func (l *League) RemoveTeam(name string) error {
+	if _, ok := l.Teams[name]; !ok {
+		return fmt.Errorf("%w: %s", ErrUnknownTeam, name)
+	}
+	history := l.history[:0]
+	for _, m := range l.history {
+		if m.Team1 == name || m.Team2 == name {
+			l.applyResult(m, -1)
+			continue
+		}
+		history = append(history, m)
+	}
+	l.history = history
+	fixtures := l.Fixtures[:0]
+	for _, f := range l.Fixtures {
+		if f.Home != name && f.Away != name {
+			fixtures = append(fixtures, f)
+		}
+	}
+	l.Fixtures = fixtures
+	delete(l.Teams, name)
+	delete(l.Wins, name)
+	l.invalidateRanking()
+	return nil
+}
@@ -0,0 +1,21 @@
+-- Builds matches.db: sqlite3 matches.db < matches.sql
+-- Small pages so the matches table has interior pages and the long team name
+-- spills onto overflow pages. forfeit is added later, so older rows don't
+-- have it.
+PRAGMA page_size = 512;
+PRAGMA journal_mode = DELETE;
+CREATE TABLE teams (name TEXT PRIMARY KEY, grp TEXT);
+CREATE TABLE matches (
+	id INTEGER PRIMARY KEY,
+	team1 TEXT NOT NULL,
+	score1 INTEGER NOT NULL,
+	team2 TEXT NOT NULL,
+	score2 INTEGER NOT NULL,
+	CHECK (team1 <> team2)
+);
+WITH RECURSIVE n(i) AS (SELECT 1 UNION ALL SELECT i + 1 FROM n WHERE i < 200)
+INSERT INTO matches (team1, score1, team2, score2)
+SELECT 'Team' || (i % 7), i % 5, 'Team' || ((i % 7) + 1), (i * 3) % 5 FROM n;
+INSERT INTO matches (team1, score1, team2, score2) VALUES (printf('%.1000c', 'X'), 100000, 'Team1', -3);
+ALTER TABLE matches ADD COLUMN forfeit INTEGER;
+INSERT INTO matches (team1, score1, team2, score2, forfeit) VALUES ('Team2', 0, 'Team3', 20, 1);
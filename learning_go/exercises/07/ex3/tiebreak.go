@@ -0,0 +1,94 @@
+package main
+
+// Tiebreaker compares two teams, returning -1 if a should be ranked above b,
+// 1 if b should be ranked above a, and 0 if it can't separate them.
+type Tiebreaker func(l *League, a, b string) int
+
+func compareInts(a, b int) int {
+	switch {
+	case a > b:
+		return -1
+	case a < b:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// record totals a team's draws and the scores for and against it across the
+// match history.
+func (l *League) record(name string) (draws, scored, conceded int) {
+	for _, m := range l.history {
+		switch name {
+		case m.Team1:
+			scored += m.Score1
+			conceded += m.Score2
+		case m.Team2:
+			scored += m.Score2
+			conceded += m.Score1
+		default:
+			continue
+		}
+		if m.Score1 == m.Score2 {
+			draws++
+		}
+	}
+	return draws, scored, conceded
+}
+
+// ByWins ranks the team with more wins higher.
+func ByWins(l *League, a, b string) int {
+	return compareInts(l.WinCount(a), l.WinCount(b))
+}
+
+// ByPoints ranks the team with more points higher, using the same points as
+// Standings.
+func ByPoints(l *League, a, b string) int {
+	drawsA, _, _ := l.record(a)
+	drawsB, _, _ := l.record(b)
+	return compareInts(l.points(a, drawsA, l.losses(a)), l.points(b, drawsB, l.losses(b)))
+}
+
+// ByGoalDifference ranks the team that has scored more than it conceded by
+// the bigger margin higher.
+func ByGoalDifference(l *League, a, b string) int {
+	_, scoredA, concededA := l.record(a)
+	_, scoredB, concededB := l.record(b)
+	return compareInts(scoredA-concededA, scoredB-concededB)
+}
+
+// ByHeadToHead ranks the team that won more of the matches between the two
+// teams higher.
+func ByHeadToHead(l *League, a, b string) int {
+	var winsA, winsB int
+	for _, m := range l.history {
+		var scoreA, scoreB int
+		switch {
+		case m.Team1 == a && m.Team2 == b:
+			scoreA, scoreB = m.Score1, m.Score2
+		case m.Team1 == b && m.Team2 == a:
+			scoreA, scoreB = m.Score2, m.Score1
+		default:
+			continue
+		}
+		if scoreA > scoreB {
+			winsA++
+		} else if scoreB > scoreA {
+			winsB++
+		}
+	}
+	return compareInts(winsA, winsB)
+}
+
+// ByName ranks teams alphabetically. It always separates two different
+// teams, so it's a good last link in a chain.
+func ByName(l *League, a, b string) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	default:
+		return 0
+	}
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"bytes"
+	"slices"
+	"testing"
+)
+
+// tiebreakLeague has three teams where wins and goal difference disagree:
+// A won twice by a goal, B won once by ten.
+func tiebreakLeague(t *testing.T) *League {
+	t.Helper()
+	l := &League{
+		Teams: map[string]Team{"A": {Name: "A"}, "B": {Name: "B"}, "C": {Name: "C"}},
+	}
+	for _, m := range []Match{
+		{Team1: "A", Score1: 1, Team2: "B", Score2: 0},
+		{Team1: "A", Score1: 1, Team2: "C", Score2: 0},
+		{Team1: "B", Score1: 10, Team2: "C", Score2: 0},
+	} {
+		if err := l.MatchResult(m.Team1, m.Score1, m.Team2, m.Score2); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return l
+}
+
+func TestTiebreakerOrder(t *testing.T) {
+	tests := []struct {
+		name  string
+		chain []Tiebreaker
+		want  []string
+	}{
+		{"default", nil, []string{"A", "B", "C"}},
+		{"wins first", []Tiebreaker{ByWins, ByGoalDifference, ByName}, []string{"A", "B", "C"}},
+		{"goal difference first", []Tiebreaker{ByGoalDifference, ByWins, ByName}, []string{"B", "A", "C"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l := tiebreakLeague(t)
+			l.Tiebreakers = tt.chain
+			if got := l.Ranking(); !slices.Equal(got, tt.want) {
+				t.Errorf("Ranking() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSaveWithTiebreakers(t *testing.T) {
+	l := tiebreakLeague(t)
+	l.Tiebreakers = []Tiebreaker{ByGoalDifference, ByName}
+	var buf bytes.Buffer
+	if err := l.Save(&buf); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	loaded, err := Load(&buf)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if loaded.Tiebreakers != nil {
+		t.Errorf("loaded league has %d tiebreakers, want none", len(loaded.Tiebreakers))
+	}
+	if got, want := loaded.Ranking(), []string{"A", "B", "C"}; !slices.Equal(got, want) {
+		t.Errorf("loaded Ranking() = %v, want %v", got, want)
+	}
+}
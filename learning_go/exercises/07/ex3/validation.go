@@ -0,0 +1,48 @@
+package main
+
+import "fmt"
+
+const defaultMaxScore = 200
+
+// ValidationError describes a single bad input to MatchResult. Field is the
+// name of the MatchResult parameter that was rejected: team1, score1, team2,
+// or score2.
+type ValidationError struct {
+	Field  string
+	Value  any
+	Reason string
+}
+
+func (ve *ValidationError) Error() string {
+	return fmt.Sprintf("invalid %s %v: %s", ve.Field, ve.Value, ve.Reason)
+}
+
+// validateMatch checks the arguments to MatchResult, returning a
+// *ValidationError for the first problem it finds.
+func (l League) validateMatch(team1 string, score1 int, team2 string, score2 int) error {
+	if _, ok := l.Teams[team1]; !ok {
+		return &ValidationError{Field: "team1", Value: team1, Reason: "unknown team"}
+	}
+	if _, ok := l.Teams[team2]; !ok {
+		return &ValidationError{Field: "team2", Value: team2, Reason: "unknown team"}
+	}
+	if team1 == team2 {
+		return &ValidationError{Field: "team2", Value: team2, Reason: "a team can't play itself"}
+	}
+	maxScore := l.MaxScore
+	if maxScore == 0 {
+		maxScore = defaultMaxScore
+	}
+	for _, s := range []struct {
+		field string
+		score int
+	}{{"score1", score1}, {"score2", score2}} {
+		if s.score < 0 {
+			return &ValidationError{Field: s.field, Value: s.score, Reason: "score can't be negative"}
+		}
+		if s.score > maxScore {
+			return &ValidationError{Field: s.field, Value: s.score, Reason: fmt.Sprintf("score is above the maximum of %d", maxScore)}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,170 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sync"
+
+	"nhooyr.io/websocket"
+	"nhooyr.io/websocket/wsjson"
+)
+
+// liveBuffer is how many events a WebSocket client can fall behind by
+// before it's disconnected.
+const liveBuffer = 16
+
+// liveConn is one connected WebSocket client.
+type liveConn struct {
+	send chan MatchEvent
+	// cancel ends the connection, it's called when the client falls too far
+	// behind
+	cancel context.CancelFunc
+}
+
+// liveHandler streams a League's results to WebSocket clients.
+type liveHandler struct {
+	s      *SyncLeague
+	bus    *Bus[MatchEvent]
+	events <-chan MatchEvent
+	// done is closed once every event has been broadcast after stop
+	done     chan struct{}
+	stopOnce sync.Once
+
+	connsMu sync.RWMutex
+	conns   map[*liveConn]struct{}
+	// stopped turns away new clients
+	stopped bool
+}
+
+// LiveMatchHandler returns a handler that upgrades requests to WebSocket
+// connections and sends every result recorded on the league to all of them,
+// as a MatchEvent in JSON. Clients can record results too, by sending
+// {"team1":"A","score1":3,"team2":"B","score2":1}. Invalid results are
+// answered with {"error": "..."} on that connection only.
+//
+// The handler subscribes to the league's EventBus, creating it if needed, so
+// results recorded elsewhere are sent as well. A client that can't keep up is
+// disconnected. Calls to MatchResult are serialised on s's lock.
+//
+// stop unsubscribes from the EventBus and disconnects every client, and
+// after it the handler answers with a 503. It can be called more than once.
+func LiveMatchHandler(s *SyncLeague) (h http.Handler, stop func()) {
+	lh := &liveHandler{s: s, done: make(chan struct{}), conns: map[*liveConn]struct{}{}}
+	s.Do(func(l *League) {
+		if l.EventBus == nil {
+			l.EventBus = NewBus[MatchEvent](defaultBusBuffer)
+		}
+		lh.bus = l.EventBus
+	})
+	lh.events = lh.bus.Subscribe()
+	go func() {
+		defer close(lh.done)
+		for e := range lh.events {
+			lh.broadcast(e)
+		}
+	}()
+	return lh, lh.stop
+}
+
+// stop is the stop func returned by LiveMatchHandler.
+func (h *liveHandler) stop() {
+	h.stopOnce.Do(func() {
+		h.bus.Unsubscribe(h.events)
+		<-h.done
+		h.connsMu.Lock()
+		defer h.connsMu.Unlock()
+		h.stopped = true
+		for c := range h.conns {
+			c.cancel()
+		}
+	})
+}
+
+// broadcast queues e for every client, and disconnects the ones with no
+// room left for it.
+func (h *liveHandler) broadcast(e MatchEvent) {
+	h.connsMu.RLock()
+	defer h.connsMu.RUnlock()
+	for c := range h.conns {
+		select {
+		case c.send <- e:
+		default:
+			c.cancel()
+		}
+	}
+}
+
+func (h *liveHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithCancel(r.Context())
+	defer cancel()
+	// Register before the handshake, so once a client is connected it's
+	// sure to get every result recorded after that
+	c := &liveConn{send: make(chan MatchEvent, liveBuffer), cancel: cancel}
+	h.connsMu.Lock()
+	if h.stopped {
+		h.connsMu.Unlock()
+		writeError(w, http.StatusServiceUnavailable, errors.New("live results have stopped"))
+		return
+	}
+	h.conns[c] = struct{}{}
+	h.connsMu.Unlock()
+	defer func() {
+		h.connsMu.Lock()
+		delete(h.conns, c)
+		h.connsMu.Unlock()
+	}()
+
+	conn, err := websocket.Accept(w, r, nil)
+	if err != nil {
+		// Accept has already written the response
+		return
+	}
+	defer conn.CloseNow()
+	conn.SetReadLimit(maxRequestBody)
+
+	go func() {
+		// Reading stops when the client goes away, which ends the
+		// connection
+		defer cancel()
+		for {
+			if err := h.readResult(ctx, conn); err != nil {
+				return
+			}
+		}
+	}()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case e := <-c.send:
+			if err := wsjson.Write(ctx, conn, e); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// readResult reads one message from conn and records it as a result. The
+// error is only for when conn can't be used any more, a bad result is
+// reported to the client.
+func (h *liveHandler) readResult(ctx context.Context, conn *websocket.Conn) error {
+	_, msg, err := conn.Read(ctx)
+	if err != nil {
+		return err
+	}
+	var req matchRequest
+	dec := json.NewDecoder(bytes.NewReader(msg))
+	dec.DisallowUnknownFields()
+	if err = dec.Decode(&req); err == nil {
+		h.s.Do(func(l *League) {
+			err = l.MatchResult(req.Team1, req.Score1, req.Team2, req.Score2)
+		})
+	}
+	if err != nil {
+		return wsjson.Write(ctx, conn, map[string]string{"error": err.Error()})
+	}
+	return nil
+}
@@ -0,0 +1,126 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"nhooyr.io/websocket"
+	"nhooyr.io/websocket/wsjson"
+)
+
+// liveServer serves LeagueHandler and LiveMatchHandler, at /live, for the
+// same league.
+func liveServer(t *testing.T) (srv *httptest.Server, stop func(), s *SyncLeague) {
+	t.Helper()
+	s = NewSyncLeague(&League{
+		Teams: map[string]Team{"USA": {Name: "USA"}, "Canada": {Name: "Canada"}},
+	})
+	live, stop := LiveMatchHandler(s)
+	mux := http.NewServeMux()
+	mux.Handle("/", LeagueHandler(s))
+	mux.Handle("/live", live)
+	srv = httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+	t.Cleanup(stop)
+	return srv, stop, s
+}
+
+func dialLive(ctx context.Context, t *testing.T, srv *httptest.Server) *websocket.Conn {
+	t.Helper()
+	conn, _, err := websocket.Dial(ctx, "ws"+strings.TrimPrefix(srv.URL, "http")+"/live", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.CloseNow() })
+	return conn
+}
+
+func TestLiveMatchHandler(t *testing.T) {
+	srv, _, s := liveServer(t)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	sender := dialLive(ctx, t, srv)
+	watcher := dialLive(ctx, t, srv)
+
+	readEvent := func(want string) {
+		t.Helper()
+		var e MatchEvent
+		if err := wsjson.Read(ctx, watcher, &e); err != nil {
+			t.Fatal(err)
+		}
+		if got := e.Team1 + " " + strconv.Itoa(e.Score1) + "-" + strconv.Itoa(e.Score2) + " " + e.Team2; got != want {
+			t.Errorf("watcher got %s, want %s", got, want)
+		}
+	}
+
+	if err := wsjson.Write(ctx, sender, matchRequest{Team1: "USA", Score1: 2, Team2: "Canada", Score2: 1}); err != nil {
+		t.Fatal(err)
+	}
+	// Write returns once the frame is sent, so wait for the broadcast to
+	// know the result has been recorded before posting the next one
+	readEvent("USA 2-1 Canada")
+	// A result posted to the other handler is broadcast too
+	resp, err := http.Post(srv.URL+"/match", "application/json", strings.NewReader(`{"team1":"Canada","score1":3,"team2":"USA","score2":0}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("POST /match: %s", resp.Status)
+	}
+	readEvent("Canada 3-0 USA")
+
+	if err := wsjson.Write(ctx, sender, matchRequest{Team1: "USA", Score1: 1, Team2: "Nowhere"}); err != nil {
+		t.Fatal(err)
+	}
+	// The sender sees both results broadcast before the error
+	var msg map[string]any
+	for i := 0; i < 3; i++ {
+		msg = nil
+		if err := wsjson.Read(ctx, sender, &msg); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if errMsg, _ := msg["error"].(string); !strings.Contains(errMsg, "Nowhere") {
+		t.Errorf("sender got %v, want an error about Nowhere", msg)
+	}
+	s.Do(func(l *League) {
+		if got := len(l.Matches()); got != 2 {
+			t.Errorf("league has %d matches, want 2", got)
+		}
+	})
+}
+
+func TestLiveMatchHandlerStop(t *testing.T) {
+	srv, stop, s := liveServer(t)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	conn := dialLive(ctx, t, srv)
+
+	stop()
+	var e MatchEvent
+	if err := wsjson.Read(ctx, conn, &e); err == nil {
+		t.Fatal("connection still open after stop")
+	}
+	s.Do(func(l *League) {
+		l.EventBus.mu.RLock()
+		defer l.EventBus.mu.RUnlock()
+		if n := len(l.EventBus.subs); n != 0 {
+			t.Errorf("%d subscribers left after stop", n)
+		}
+	})
+	_, resp, err := websocket.Dial(ctx, "ws"+strings.TrimPrefix(srv.URL, "http")+"/live", nil)
+	if err == nil {
+		t.Fatal("connected after stop")
+	}
+	if resp == nil || resp.StatusCode != http.StatusServiceUnavailable {
+		t.Errorf("got response %v, want a 503", resp)
+	}
+	// Calling it again is fine
+	stop()
+}